@@ -4,14 +4,58 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 import (
+	"flag"
 	"github.com/niftysoft/2d-platformer/internal"
-	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
 )
 
+// tpsEnvVar is the environment variable used to configure the tick rate when the --tps flag is not provided.
+const tpsEnvVar = "DAY3_TPS"
+
 func main() {
+	var tps int
+	opts := internal.DefaultOptions
+	flag.IntVar(&tps, "tps", 0, "ticks per second, in the range [30, 240]; overrides "+tpsEnvVar)
+	flag.IntVar(&opts.MaxTPS, "max-tps", opts.MaxTPS,
+		"caps the tick rate, including any set by --tps or "+tpsEnvVar+", in the range [30, 240]; 0 disables the cap")
+	flag.BoolVar(&opts.SleepOnIdle, "sleep-on-idle", opts.SleepOnIdle,
+		"yield the CPU while idle; disable for high-precision frame timing")
+	flag.BoolVar(&opts.ShaderEnabled, "crt", opts.ShaderEnabled, "draw the screen through a CRT scanline shader")
+	flag.Int64Var(&opts.Seed, "seed", time.Now().UnixNano(),
+		"seeds the randomness of every level; reuse a seed to replay the same world")
+	flag.BoolVar(&opts.LevelSelect, "level-select", opts.LevelSelect, "start at the level select menu")
+	flag.BoolVar(&opts.SweepMovement, "sweep", opts.SweepMovement,
+		"move players diagonally in a single pass so they cannot clip through corners")
+	flag.Parse()
+
+	internal.SetupLogger(slog.LevelInfo, "text")
+	if tps == 0 {
+		if env, ok := os.LookupEnv(tpsEnvVar); ok {
+			n, err := strconv.Atoi(env)
+			if err != nil {
+				slog.Error("invalid "+tpsEnvVar, "value", env, "err", err)
+				os.Exit(1)
+			}
+			tps = n
+		}
+	}
+	if err := internal.SetOptions(opts); err != nil {
+		slog.Error("invalid options", "err", err)
+		os.Exit(1)
+	}
+	if tps != 0 {
+		if err := internal.SetTPS(tps); err != nil {
+			slog.Error("invalid tick rate", "err", err)
+			os.Exit(1)
+		}
+	}
 	game, err := internal.NewGame()
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("error starting game", "err", err)
+		os.Exit(1)
 	}
 
 	// Specify the window size as you like. Here, a doubled size is specified.
@@ -19,6 +63,7 @@ func main() {
 	ebiten.SetWindowTitle("NiftyFramework")
 	// Call ebiten.RunGame to start your game loop.
 	if err := ebiten.RunGame(game); err != nil {
-		log.Fatal(err)
+		slog.Error("error running game", "err", err)
+		os.Exit(1)
 	}
 }
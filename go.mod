@@ -1,23 +1,25 @@
 module github.com/niftysoft/2d-platformer
 
-go 1.19
+go 1.21
 
 require (
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/google/uuid v1.3.0
 	github.com/hajimehoshi/ebiten/v2 v2.5.0
 	github.com/kalexmills/asebiten v0.3.0
 	github.com/stretchr/testify v1.8.2
+	golang.org/x/image v0.6.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/ebitengine/purego v0.3.0 // indirect
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20221017161538-93cebf72946b // indirect
+	github.com/hajimehoshi/oto/v2 v2.4.0 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/exp v0.0.0-20230315142452-642cacee5cc0 // indirect
 	golang.org/x/exp/shiny v0.0.0-20230315142452-642cacee5cc0 // indirect
-	golang.org/x/image v0.6.0 // indirect
 	golang.org/x/mobile v0.0.0-20230301163155-e0f57694e12c // indirect
 	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/sys v0.6.0 // indirect
@@ -0,0 +1,83 @@
+// Package ai holds steering behaviours for enemies which are independent of any particular scene.
+package ai
+
+import (
+	"math"
+)
+
+const (
+	NeighbourRadius  = 24 // NeighbourRadius is the distance within which boids align with and move toward each other.
+	SeparationRadius = 8  // SeparationRadius is the distance within which boids steer away from each other.
+)
+
+// Vec2 is a 2D vector. It mirrors the vector type used by the game, which cannot be imported here without a cycle.
+type Vec2 struct{ X, Y float64 }
+
+// Add returns the sum of this vector and other.
+func (v Vec2) Add(other Vec2) Vec2 { return Vec2{X: v.X + other.X, Y: v.Y + other.Y} }
+
+// Sub returns the difference of this vector and other.
+func (v Vec2) Sub(other Vec2) Vec2 { return Vec2{X: v.X - other.X, Y: v.Y - other.Y} }
+
+// Scale returns this vector scaled by f.
+func (v Vec2) Scale(f float64) Vec2 { return Vec2{X: v.X * f, Y: v.Y * f} }
+
+// Mag returns the magnitude of this vector.
+func (v Vec2) Mag() float64 { return math.Hypot(v.X, v.Y) }
+
+// ClampMag returns this vector with its magnitude limited to max.
+func (v Vec2) ClampMag(max float64) Vec2 {
+	if mag := v.Mag(); mag > max {
+		return v.Scale(max / mag)
+	}
+	return v
+}
+
+// Boid is a single member of a flock.
+type Boid struct {
+	Pos, Vel Vec2
+	MaxSpeed float64
+}
+
+// UpdateBoids steers each boid by the three classic flocking forces, each scaled by the provided weight:
+//   - separation steers away from boids closer than SeparationRadius, more strongly the closer they are;
+//   - alignment steers toward the average velocity of boids within NeighbourRadius;
+//   - cohesion steers toward the average position of boids within NeighbourRadius.
+//
+// Each boid's new velocity is clamped to its MaxSpeed. Positions are not changed; callers move each boid by its
+// velocity so that they can handle collisions.
+func UpdateBoids(boids []Boid, separationWeight, alignmentWeight, cohesionWeight float64) {
+	steer := make([]Vec2, len(boids))
+	for i, b := range boids {
+		var separation, avgVel, avgPos Vec2
+		neighbours := 0
+		for j, other := range boids {
+			if i == j {
+				continue
+			}
+			offset := b.Pos.Sub(other.Pos)
+			dist := offset.Mag()
+			if dist >= NeighbourRadius {
+				continue
+			}
+			neighbours++
+			avgVel = avgVel.Add(other.Vel)
+			avgPos = avgPos.Add(other.Pos)
+			if dist < SeparationRadius && dist > 0 {
+				separation = separation.Add(offset.Scale(1 / (dist * dist)))
+			}
+		}
+		if neighbours == 0 {
+			continue
+		}
+		n := float64(neighbours)
+		alignment := avgVel.Scale(1 / n).Sub(b.Vel)
+		cohesion := avgPos.Scale(1 / n).Sub(b.Pos)
+		steer[i] = separation.Scale(separationWeight).
+			Add(alignment.Scale(alignmentWeight)).
+			Add(cohesion.Scale(cohesionWeight))
+	}
+	for i := range boids {
+		boids[i].Vel = boids[i].Vel.Add(steer[i]).ClampMag(boids[i].MaxSpeed)
+	}
+}
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdateBoids(t *testing.T) {
+	tests := []struct {
+		name                 string
+		boids                []Boid
+		sep, align, cohesion float64
+		wantX, wantY         float64 // wantX and wantY give the sign of the first boid's new velocity on each axis.
+	}{
+		{
+			name:  "separation steers away",
+			boids: []Boid{{Pos: Vec2{X: 0}, MaxSpeed: 10}, {Pos: Vec2{X: 4}, MaxSpeed: 10}},
+			sep:   1, wantX: -1,
+		},
+		{
+			name:     "cohesion steers toward",
+			boids:    []Boid{{Pos: Vec2{Y: 0}, MaxSpeed: 10}, {Pos: Vec2{Y: 20}, MaxSpeed: 10}},
+			cohesion: 1, wantY: 1,
+		},
+		{
+			name:  "alignment matches velocity",
+			boids: []Boid{{Pos: Vec2{X: 0}, MaxSpeed: 10}, {Pos: Vec2{X: 20}, Vel: Vec2{Y: -2}, MaxSpeed: 10}},
+			align: 1, wantY: -1,
+		},
+		{
+			name:  "out of range",
+			boids: []Boid{{Pos: Vec2{X: 0}, MaxSpeed: 10}, {Pos: Vec2{X: NeighbourRadius}, MaxSpeed: 10}},
+			sep:   1, align: 1, cohesion: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UpdateBoids(tt.boids, tt.sep, tt.align, tt.cohesion)
+			vel := tt.boids[0].Vel
+			if sign(vel.X) != tt.wantX || sign(vel.Y) != tt.wantY {
+				t.Errorf("UpdateBoids() Vel = %v; want signs (%v, %v)", vel, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestUpdateBoidsClampsSpeed(t *testing.T) {
+	boids := []Boid{{Pos: Vec2{X: 0}, MaxSpeed: 1.5}, {Pos: Vec2{X: 1}, MaxSpeed: 1.5}}
+	UpdateBoids(boids, 100, 0, 0)
+	for i, b := range boids {
+		if got := b.Vel.Mag(); got > b.MaxSpeed+1e-9 {
+			t.Errorf("boid %d speed = %v; want at most %v", i, got, b.MaxSpeed)
+		}
+	}
+}
+
+// sign returns -1, 0 or 1 according to the sign of f.
+func sign(f float64) float64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Copysign(1, f)
+}
+
+// BenchmarkUpdateBoids measures one second of updates at 60Hz for a flock of 50 boids.
+func BenchmarkUpdateBoids(b *testing.B) {
+	boids := make([]Boid, 50)
+	for i := range boids {
+		angle := 2 * math.Pi * float64(i) / float64(len(boids))
+		boids[i] = Boid{Pos: Vec2{X: 40 * math.Cos(angle), Y: 40 * math.Sin(angle)}, MaxSpeed: 1.5}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for frame := 0; frame < 60; frame++ {
+			UpdateBoids(boids, 1.5, 0.05, 0.01)
+			for j := range boids {
+				boids[j].Pos = boids[j].Pos.Add(boids[j].Vel)
+			}
+		}
+	}
+}
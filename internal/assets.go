@@ -14,25 +14,44 @@ const (
 	PlayerAnimJump
 	PlayerAnimRun
 	PlayerAnimWalk
+	PlayerAnimDead
+	PlayerAnimAttack
 )
 
 const (
 	jumpUpTag   = "up"
 	jumpMaxTag  = "max"
 	jumpDownTag = "down"
+
+	deadFallTag = "fall" // deadFallTag marks the frames of the death animation in which the player falls over.
+	deadDownTag = "down" // deadDownTag marks the frame of the death animation in which the player lies still.
+
+	attackActiveTag = "active" // attackActiveTag marks the frames of the attack animation in which the attack can hit.
+)
+
+// PlayerAnimBlendFrames is the number of frames taken to crossfade between player animations.
+const PlayerAnimBlendFrames = 4
+
+const (
+	MinAnimSpeed = 0.1 // MinAnimSpeed is the slowest speed multiplier accepted by PlayerSprite.SetSpeed.
+	MaxAnimSpeed = 4.0 // MaxAnimSpeed is the fastest speed multiplier accepted by PlayerSprite.SetSpeed.
 )
 
 var anims = map[PlayerAnim]string{
-	PlayerAnimIdle: "idle.json",
-	PlayerAnimJump: "jump.json",
-	PlayerAnimRun:  "run.json",
-	PlayerAnimWalk: "run.json",
+	PlayerAnimIdle:   "idle.json",
+	PlayerAnimJump:   "jump.json",
+	PlayerAnimRun:    "run.json",
+	PlayerAnimWalk:   "run.json",
+	PlayerAnimDead:   "dead.json",
+	PlayerAnimAttack: "attack.json",
 }
 
 func LoadPlayerAnims() (*PlayerSprite, error) {
 	var err error
 	result := &PlayerSprite{
-		anims: make(map[PlayerAnim]*asebiten.Animation, len(anims)),
+		anims:           make(map[PlayerAnim]*asebiten.Animation, len(anims)),
+		blendFrames:     PlayerAnimBlendFrames,
+		speedMultiplier: 1,
 	}
 	for anim, path := range anims {
 		result.anims[anim], err = asebiten.LoadAnimation(gameData, "gamedata/sprites/"+path)
@@ -57,21 +76,63 @@ type PlayerSprite struct {
 	// collision hitboxes for the sprite.
 	hitboxes map[PlayerAnim]image.Rectangle
 
-	facingLeft bool // true if the player is facing left.
+	facingLeft  bool // true if the player is facing left.
+	flashFrames int  // flashFrames is the number of frames remaining in the current flash.
+
+	onFrame   map[PlayerAnim]func(frameIdx int) // onFrame holds callbacks called when each animation changes frame.
+	lastFrame int                               // lastFrame is the index of the frame shown after the last update.
+
+	blendFrames int                 // blendFrames is the length of each crossfade; zero cuts between animations.
+	blendLeft   int                 // blendLeft is the number of frames remaining in the current crossfade.
+	prev        *asebiten.Animation // prev is the animation fading out during a crossfade.
+	prevLeft    bool                // prevLeft is true if prev is drawn facing left.
+
+	frameCache      *ebiten.Image // frameCache holds the current frame of curr, already flipped to face the right way.
+	frameCacheValid bool          // frameCacheValid is false if frameCache must be redrawn before it is next used.
+	cachedFrame     int           // cachedFrame is the index of the frame held in frameCache.
+	cachedLeft      bool          // cachedLeft is true if frameCache was drawn facing left.
+
+	speedMultiplier float64 // speedMultiplier scales the playback speed of every animation; 1 is normal speed.
+	speedAccum      float64 // speedAccum accumulates speedMultiplier each frame; each whole unit advances the animation.
 }
 
+// flashPeriod is the number of frames the sprite spends tinted or untinted while flashing.
+const flashPeriod = 4
+
 func (p *PlayerSprite) Update() {
+	if p.flashFrames > 0 {
+		p.flashFrames--
+	}
+	if p.blendLeft > 0 {
+		p.blendLeft--
+	}
 	if p.curr == nil {
 		p.curr = p.anims[PlayerAnimIdle]
 		p.curr.Resume()
 	}
-	p.curr.Update()
+	// advance the animation once for each whole frame accumulated, so fractional speeds skip frames.
+	for p.speedAccum += p.speedMultiplier; p.speedAccum >= 1; p.speedAccum-- {
+		p.curr.Update()
+	}
+	if idx := p.curr.FrameIdx(); idx != p.lastFrame {
+		p.lastFrame = idx
+		if f, ok := p.onFrame[p.currKey]; ok {
+			f(idx)
+		}
+	}
 }
 
+// SetAnim switches to the animation with the provided key. If blendFrames is set, the previous animation is frozen and
+// crossfaded out over that many frames while the new animation plays.
 func (p *PlayerSprite) SetAnim(key PlayerAnim, left bool) {
 	p.currTag = ""
+	p.lastFrame = -1
 	if animation, ok := p.anims[key]; ok {
+		if p.curr != nil && p.curr != animation && p.blendFrames > 0 {
+			p.prev, p.prevLeft, p.blendLeft = p.curr, p.facingLeft, p.blendFrames
+		}
 		p.curr = animation
+		p.frameCacheValid = false
 		p.currKey = key
 	}
 	p.facingLeft = left
@@ -81,26 +142,128 @@ func (p *PlayerSprite) SetAnim(key PlayerAnim, left bool) {
 	}
 }
 
+// SetSpeed sets the playback speed of every animation as a multiple of normal speed, clamped to
+// [MinAnimSpeed, MaxAnimSpeed]; e.g. 0.5 plays in slow motion at half speed.
+func (p *PlayerSprite) SetSpeed(mult float64) {
+	p.speedMultiplier = min(max(mult, MinAnimSpeed), MaxAnimSpeed)
+}
+
 func (p *PlayerSprite) SetTag(tag string) {
 	p.curr.SetTag(tag)
 	p.currTag = tag
+	p.frameCacheValid = false
+}
+
+// OnAnimEnd registers the provided func to be called each time the animation with the provided key ends.
+func (p *PlayerSprite) OnAnimEnd(key PlayerAnim, f func()) {
+	p.OnTagEnd(key, "", f)
+}
+
+// OnTagEnd registers the provided func to be called each time the frames with the provided tag end while playing in
+// the animation with the provided key.
+func (p *PlayerSprite) OnTagEnd(key PlayerAnim, tag string, f func()) {
+	p.anims[key].OnEnd(tag, func(*asebiten.Animation) { f() })
+}
+
+// OnFrame registers the provided func to be called each time the animation with the provided key changes frame.
+func (p *PlayerSprite) OnFrame(key PlayerAnim, f func(frameIdx int)) {
+	if p.onFrame == nil {
+		p.onFrame = make(map[PlayerAnim]func(int))
+	}
+	p.onFrame[key] = f
+}
+
+// InTag returns true if the current frame of the current animation is one of the frames with the provided tag.
+func (p *PlayerSprite) InTag(tag string) bool {
+	idx := p.curr.Frame().FrameIdx
+	if p.currTag == "" {
+		idx = p.curr.FrameIdx() // frames of the untagged animation don't record their index.
+	}
+	for _, frame := range p.curr.FramesByTagName[tag] {
+		if frame.FrameIdx == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// Flash tints the sprite on and off for the provided number of frames.
+func (p *PlayerSprite) Flash(frames int) {
+	p.flashFrames = frames
 }
 
 func (p *PlayerSprite) SetFacing(left bool) {
 	p.facingLeft = left
 }
 
+// DrawTo draws the current animation frame to screen. During a crossfade, the previous animation is drawn with opacity
+// blendLeft/blendFrames and the current animation with the remaining opacity.
 func (p *PlayerSprite) DrawTo(screen *ebiten.Image, options *ebiten.DrawImageOptions) {
+	prevAlpha, currAlpha := p.blendAlphas()
+	if prevAlpha > 0 {
+		p.drawAnim(screen, p.prev, p.prevLeft, prevAlpha, options)
+	}
+	p.drawCached(screen, currAlpha, options)
+}
+
+// blendAlphas returns the opacities the previous and current animations are drawn with. The previous animation is
+// not drawn at all outside of a crossfade.
+func (p *PlayerSprite) blendAlphas() (prevAlpha, currAlpha float32) {
+	if p.blendLeft <= 0 || p.prev == nil {
+		return 0, 1
+	}
+	prevAlpha = float32(p.blendLeft) / float32(p.blendFrames)
+	return prevAlpha, 1 - prevAlpha
+}
+
+// drawCached draws the current frame of the current animation to screen with the provided opacity, redrawing
+// frameCache first only if the frame or facing has changed since it was last drawn.
+func (p *PlayerSprite) drawCached(screen *ebiten.Image, alpha float32, options *ebiten.DrawImageOptions) {
+	idx := p.curr.FrameIdx()
+	if !p.frameCacheValid || idx != p.cachedFrame || p.facingLeft != p.cachedLeft {
+		bounds := p.curr.Bounds()
+		if p.frameCache == nil || p.frameCache.Bounds().Size() != bounds.Size() {
+			p.frameCache = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+		}
+		p.frameCache.Clear()
+		opts := ebiten.DrawImageOptions{}
+		if p.facingLeft {
+			opts.GeoM.Scale(-1, 1) // flip horizontal
+			opts.GeoM.Translate(float64(bounds.Dx()), 0)
+		}
+		p.curr.DrawTo(p.frameCache, &opts)
+		p.frameCacheValid, p.cachedFrame, p.cachedLeft = true, idx, p.facingLeft
+	}
+	opts := ebiten.DrawImageOptions{GeoM: options.GeoM}
+	opts.ColorScale = p.colorScale(alpha, options)
+	opts.Blend = options.Blend
+	opts.Filter = options.Filter
+	screen.DrawImage(p.frameCache, &opts)
+}
+
+// colorScale returns the color scale of the provided options, tinted if the sprite is flashing and scaled by alpha.
+func (p *PlayerSprite) colorScale(alpha float32, options *ebiten.DrawImageOptions) ebiten.ColorScale {
+	result := options.ColorScale
+	if (p.flashFrames/flashPeriod)%2 == 1 {
+		result.Scale(1, 0.3, 0.3, 1)
+	}
+	result.ScaleAlpha(alpha)
+	return result
+}
+
+// drawAnim draws the current frame of the provided animation to screen with the provided opacity.
+func (p *PlayerSprite) drawAnim(screen *ebiten.Image, anim *asebiten.Animation, left bool, alpha float32,
+	options *ebiten.DrawImageOptions) {
 	opts := ebiten.DrawImageOptions{}
-	if p.facingLeft {
+	if left {
 		opts.GeoM.Scale(-1, 1) // flip horizontal
-		opts.GeoM.Translate(float64(p.curr.Bounds().Dx()), 0)
+		opts.GeoM.Translate(float64(anim.Bounds().Dx()), 0)
 	}
 	opts.GeoM.Concat(options.GeoM)
-	opts.ColorScale = options.ColorScale
+	opts.ColorScale = p.colorScale(alpha, options)
 	opts.Blend = options.Blend
 	opts.Filter = options.Filter
-	p.curr.DrawTo(screen, &opts)
+	anim.DrawTo(screen, &opts)
 }
 
 func (p *PlayerSprite) Bounds() image.Rectangle {
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kalexmills/asebiten"
+	"testing"
+)
+
+// newTestSprite loads the player's sprite and shows its first frame.
+func newTestSprite(t testing.TB) *PlayerSprite {
+	t.Helper()
+	sprite, err := LoadPlayerAnims()
+	if err != nil {
+		t.Fatalf("could not load player animations: %v", err)
+	}
+	sprite.Update()
+	return sprite
+}
+
+func TestPlayerSpriteCrossfade(t *testing.T) {
+	sprite := newTestSprite(t)
+	if prev, curr := sprite.blendAlphas(); prev != 0 || curr != 1 {
+		t.Fatalf("blendAlphas() = %v, %v before any crossfade; want 0, 1", prev, curr)
+	}
+	sprite.SetAnim(PlayerAnimRun, false)
+	if sprite.prev != sprite.anims[PlayerAnimIdle] {
+		t.Fatalf("crossfade started from %v; want the idle animation", sprite.prev)
+	}
+	for frame := 0; frame <= PlayerAnimBlendFrames; frame++ {
+		wantPrev := float32(PlayerAnimBlendFrames-frame) / PlayerAnimBlendFrames
+		prev, curr := sprite.blendAlphas()
+		if prev != wantPrev || curr != 1-wantPrev {
+			t.Errorf("blendAlphas() = %v, %v after %d updates; want %v, %v", prev, curr, frame, wantPrev, 1-wantPrev)
+		}
+		if frame == PlayerAnimBlendFrames/2 && (prev != 0.5 || curr != 0.5) {
+			t.Errorf("blendAlphas() = %v, %v at the midpoint of the crossfade; want 0.5, 0.5", prev, curr)
+		}
+		sprite.Update()
+	}
+}
+
+func TestPlayerSpriteNoBlend(t *testing.T) {
+	sprite := newTestSprite(t)
+	sprite.blendFrames = 0
+	sprite.SetAnim(PlayerAnimRun, false)
+	if prev, curr := sprite.blendAlphas(); prev != 0 || curr != 1 {
+		t.Errorf("blendAlphas() = %v, %v with blending disabled; want a hard cut to 0, 1", prev, curr)
+	}
+}
+
+func TestPlayerSpriteFrameCache(t *testing.T) {
+	sprite := newTestSprite(t)
+	screen := ebiten.NewImage(64, 64)
+	opts := &ebiten.DrawImageOptions{}
+	sprite.DrawTo(screen, opts)
+	cache := sprite.frameCache
+	if !sprite.frameCacheValid || cache == nil {
+		t.Fatalf("frame cache was not filled by DrawTo")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { sprite.DrawTo(screen, opts) }); allocs != 0 {
+		t.Errorf("DrawTo() of an unchanged frame made %v allocations; want 0", allocs)
+	}
+	if sprite.frameCache != cache {
+		t.Errorf("DrawTo() of an unchanged frame replaced the cached image")
+	}
+	sprite.SetFacing(true)
+	sprite.DrawTo(screen, opts)
+	if !sprite.cachedLeft {
+		t.Errorf("frame cache was not redrawn after turning to face left")
+	}
+}
+
+// BenchmarkPlayerSpriteDrawTo measures drawing a static idle frame, which should be served from the frame cache.
+func BenchmarkPlayerSpriteDrawTo(b *testing.B) {
+	sprite := newTestSprite(b)
+	screen := ebiten.NewImage(64, 64)
+	opts := &ebiten.DrawImageOptions{}
+	sprite.DrawTo(screen, opts)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sprite.DrawTo(screen, opts)
+	}
+}
+
+// newCountingAnim returns an animation of the provided number of frames which advances exactly one frame each time it
+// is updated, so that its frame index counts its updates.
+func newCountingAnim(t *testing.T, frames int) *asebiten.Animation {
+	t.Helper()
+	const delta = 10
+	prev := asebiten.DeltaMillis
+	asebiten.DeltaMillis = delta
+	t.Cleanup(func() { asebiten.DeltaMillis = prev })
+	anim := &asebiten.Animation{FramesByTagName: map[string][]asebiten.AniFrame{"": make([]asebiten.AniFrame, frames)}}
+	for i := range anim.FramesByTagName[""] {
+		anim.FramesByTagName[""][i] = asebiten.AniFrame{FrameIdx: i, DurationMillis: delta}
+	}
+	anim.Update() // the first update only accumulates the first frame's duration.
+	return anim
+}
+
+func TestPlayerSpriteSpeed(t *testing.T) {
+	tests := []struct {
+		speed      float64
+		wantFrames int // wantFrames is the number of logical frames the animation advances in 60 updates.
+	}{
+		{speed: 0.5, wantFrames: 30},
+		{speed: 1, wantFrames: 60},
+		{speed: 2, wantFrames: 120},
+		{speed: 0.25, wantFrames: 15},
+		{speed: 10, wantFrames: 240}, // clamped to MaxAnimSpeed.
+	}
+	for _, tt := range tests {
+		sprite := newTestSprite(t)
+		sprite.curr = newCountingAnim(t, 300)
+		sprite.SetSpeed(tt.speed)
+		for i := 0; i < 60; i++ {
+			sprite.Update()
+		}
+		if got := sprite.curr.FrameIdx(); got != tt.wantFrames {
+			t.Errorf("SetSpeed(%v): animation advanced %d frames in 60 updates; want %d", tt.speed, got, tt.wantFrames)
+		}
+	}
+}
+
+func TestPlayerSpriteSetSpeedClamps(t *testing.T) {
+	tests := []struct {
+		speed, want float64
+	}{
+		{speed: 0, want: MinAnimSpeed},
+		{speed: -1, want: MinAnimSpeed},
+		{speed: 0.5, want: 0.5},
+		{speed: 10, want: MaxAnimSpeed},
+	}
+	sprite := newTestSprite(t)
+	for _, tt := range tests {
+		if sprite.SetSpeed(tt.speed); sprite.speedMultiplier != tt.want {
+			t.Errorf("SetSpeed(%v) set the multiplier to %v; want %v", tt.speed, sprite.speedMultiplier, tt.want)
+		}
+	}
+}
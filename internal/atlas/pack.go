@@ -0,0 +1,66 @@
+// Package atlas packs many images into a single texture atlas, so that drawing from any of them does not break Ebiten's
+// draw call batching.
+package atlas
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"math"
+	"sort"
+)
+
+// MaxSize is the largest width or height of a packed atlas, in pixels.
+const MaxSize = 4096
+
+// PackTilesets arranges every provided tileset into a single atlas image using shelf packing, returning the atlas and
+// the top-left pixel offset of each tileset within it, keyed by tileset UID. Tilesets are packed tallest first onto
+// shelves no wider than the larger of the widest tileset and the square root of the total area. Returns an error if
+// the atlas would be larger than MaxSize in either dimension.
+func PackTilesets(tilesets map[int64]*ebiten.Image) (*ebiten.Image, map[int64]image.Point, error) {
+	uids := make([]int64, 0, len(tilesets))
+	area, width := 0, 1
+	for uid, img := range tilesets {
+		uids = append(uids, uid)
+		size := img.Bounds().Size()
+		area += size.X * size.Y
+		width = max(width, size.X)
+	}
+	sort.Slice(uids, func(i, j int) bool {
+		hi, hj := tilesets[uids[i]].Bounds().Dy(), tilesets[uids[j]].Bounds().Dy()
+		if hi != hj {
+			return hi > hj
+		}
+		return uids[i] < uids[j]
+	})
+	width = max(width, int(math.Ceil(math.Sqrt(float64(area)))))
+	if width > MaxSize {
+		return nil, nil, fmt.Errorf("atlas width %d exceeds %d", width, MaxSize)
+	}
+
+	offsets := make(map[int64]image.Point, len(tilesets))
+	var x, y, shelfH int
+	for _, uid := range uids {
+		size := tilesets[uid].Bounds().Size()
+		if x+size.X > width { // start a new shelf.
+			x, y, shelfH = 0, y+shelfH, 0
+		}
+		offsets[uid] = image.Pt(x, y)
+		x += size.X
+		shelfH = max(shelfH, size.Y)
+	}
+	height := max(y+shelfH, 1)
+	if height > MaxSize {
+		return nil, nil, fmt.Errorf("atlas height %d exceeds %d", height, MaxSize)
+	}
+
+	result := ebiten.NewImage(width, height)
+	opts := ebiten.DrawImageOptions{}
+	for uid, offset := range offsets {
+		img := tilesets[uid]
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(float64(offset.X-img.Bounds().Min.X), float64(offset.Y-img.Bounds().Min.Y))
+		result.DrawImage(img, &opts)
+	}
+	return result, offsets, nil
+}
@@ -0,0 +1,46 @@
+package atlas
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"testing"
+)
+
+func TestPackTilesets(t *testing.T) {
+	sizes := map[int64]image.Point{1: {64, 32}, 2: {16, 16}, 3: {48, 48}, 4: {16, 64}, 5: {32, 16}}
+	tilesets := make(map[int64]*ebiten.Image, len(sizes))
+	for uid, size := range sizes {
+		tilesets[uid] = ebiten.NewImage(size.X, size.Y)
+	}
+	img, offsets, err := PackTilesets(tilesets)
+	if err != nil {
+		t.Fatalf("PackTilesets() error = %v", err)
+	}
+	if len(offsets) != len(tilesets) {
+		t.Fatalf("PackTilesets() returned %d offsets; want %d", len(offsets), len(tilesets))
+	}
+	placed := make(map[int64]image.Rectangle, len(offsets))
+	for uid, offset := range offsets {
+		rect := image.Rectangle{Min: offset, Max: offset.Add(sizes[uid])}
+		if !rect.In(img.Bounds()) {
+			t.Errorf("tileset %d placed at %v; want it inside the atlas bounds %v", uid, rect, img.Bounds())
+		}
+		for other, otherRect := range placed {
+			if rect.Overlaps(otherRect) {
+				t.Errorf("tileset %d at %v overlaps tileset %d at %v", uid, rect, other, otherRect)
+			}
+		}
+		placed[uid] = rect
+	}
+}
+
+func TestPackTilesetsEmpty(t *testing.T) {
+	img, offsets, err := PackTilesets(nil)
+	if err != nil {
+		t.Fatalf("PackTilesets(nil) error = %v", err)
+	}
+	if len(offsets) != 0 || img.Bounds().Dx() < 1 || img.Bounds().Dy() < 1 {
+		t.Errorf("PackTilesets(nil) = %v image with %d offsets; want a non-empty image with none", img.Bounds(),
+			len(offsets))
+	}
+}
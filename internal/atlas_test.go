@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"testing"
+)
+
+// atlasTestLayers is the number of layers drawn by scenes created with newAtlasTestScene.
+const atlasTestLayers = 4
+
+// newAtlasTestScene returns a scene whose level has atlasTestLayers layers filling an 8×8 grid with tiles, alternating
+// between two tilesets. If packed is true, the tilesets are packed into an atlas.
+func newAtlasTestScene(t testing.TB, packed bool) *PlatformerScene {
+	t.Helper()
+	s := newTestScene(t, grid(8, 8)...)
+	first := addTestTileset(s)
+	s.level.layers = s.level.layers[:len(s.level.layers)-1]
+	second := ebiten.NewImage(testCellSize, testCellSize)
+	second.Fill(color.RGBA{R: 0xff, G: 0xff, A: 0xff})
+	s.gdat.Tilesets[testTilesetUID+1] = second
+	for i := 0; i < atlasTestLayers; i++ {
+		uid := int64(testTilesetUID + i%2)
+		layer := &TileLayer{ID: "Tiles", Opacity: 1, GridSize: testCellSize, CellDims: first.CellDims, TileSetUID: &uid}
+		for cy := i; cy < 8; cy++ {
+			for cx := 0; cx < 8; cx++ {
+				idx := 0 // the second tileset holds a single tile.
+				if i%2 == 0 {
+					idx = (cx + cy) % len(testTileColors)
+				}
+				layer.Tiles = append(layer.Tiles, testTile(cx, cy, idx))
+			}
+		}
+		s.level.layers = append(s.level.layers, layer)
+	}
+	if packed {
+		s.gdat.packAtlas()
+	}
+	return s
+}
+
+// sheetSwitches returns the number of times the image drawn from changes between consecutive tiles of the scene's
+// level, each of which breaks Ebiten's batching of draw calls.
+func sheetSwitches(s *PlatformerScene) int {
+	var prev *ebiten.Image
+	result := 0
+	for _, layer := range s.level.layers {
+		if layer.TileSetUID == nil {
+			continue
+		}
+		for range layer.Tiles {
+			sheet, _ := s.tileSheet(s.gdat.Tilesets[*layer.TileSetUID], layer)
+			if prev != nil && sheet != prev {
+				result++
+			}
+			prev = sheet
+		}
+	}
+	return result
+}
+
+func TestAtlasReducesSheetSwitches(t *testing.T) {
+	unpacked, packed := sheetSwitches(newAtlasTestScene(t, false)), sheetSwitches(newAtlasTestScene(t, true))
+	if unpacked != atlasTestLayers-1 {
+		t.Fatalf("unpacked tilesets switched images %d times; want %d", unpacked, atlasTestLayers-1)
+	}
+	if packed > unpacked/2 {
+		t.Errorf("atlas switched images %d times; want at most half of the %d switches without it", packed, unpacked)
+	}
+}
+
+func TestAtlasDrawsSameTiles(t *testing.T) {
+	unpacked, packed := newAtlasTestScene(t, false), newAtlasTestScene(t, true)
+	if packed.gdat.Atlas == nil {
+		t.Fatalf("tilesets were not packed into an atlas")
+	}
+	area := IRect{W: 8 * testCellSize, H: 8 * testCellSize}
+	unpacked.redrawTiles(area)
+	packed.redrawTiles(area)
+	for cy := 0; cy < 8; cy++ {
+		for cx := 0; cx < 8; cx++ {
+			x, y := cx*testCellSize+testCellSize/2, cy*testCellSize+testCellSize/2
+			if got, want := backgroundAt(packed, x, y), backgroundAt(unpacked, x, y); got != want {
+				t.Errorf("cell (%d, %d) drawn from the atlas = %v; want %v", cx, cy, got, want)
+			}
+		}
+	}
+}
+
+// BenchmarkDrawTiles measures redrawing every tile of a level whose layers alternate between two tilesets, with and
+// without packing the tilesets into an atlas.
+func BenchmarkDrawTiles(b *testing.B) {
+	for _, bm := range []struct {
+		name   string
+		packed bool
+	}{{"tilesets", false}, {"atlas", true}} {
+		b.Run(bm.name, func(b *testing.B) {
+			s := newAtlasTestScene(b, bm.packed)
+			area := IRect{W: 8 * testCellSize, H: 8 * testCellSize}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.redrawTiles(area)
+			}
+			b.ReportMetric(float64(sheetSwitches(s)), "switches/op")
+		})
+	}
+}
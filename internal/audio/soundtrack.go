@@ -0,0 +1,135 @@
+// Package audio manages the music played in the background of the game.
+package audio
+
+import (
+	"bytes"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"io/fs"
+	"log/slog"
+	"path"
+)
+
+// track is the part of *audio.Player used to play a soundtrack, allowing tracks to be replaced in tests.
+type track interface {
+	Play()
+	SetVolume(volume float64)
+	Close() error
+}
+
+// SoundtrackManager plays a single looping track at a time, cross-fading between tracks when asked to change. Tracks are
+// loaded by name from <dir>/<name>.wav in the provided filesystem; tracks which fail to load are logged and replaced by
+// silence.
+type SoundtrackManager struct {
+	ctx  *audio.Context
+	fsys fs.FS
+	dir  string
+	open func(name string) track // open loads the named track, returning nil if it could not be loaded.
+
+	current      track // current is the track playing, which fades out during a cross-fade.
+	next         track // next is the track fading in during a cross-fade; nil if fading to silence.
+	currentName  string
+	nextName     string
+	fading       bool    // fading is true while a cross-fade is in progress.
+	fadeFrames   int     // fadeFrames is the number of frames elapsed in the current cross-fade.
+	fadeDuration int     // fadeDuration is the total number of frames in the current cross-fade.
+	volume       float64 // volume scales the volume of every track.
+}
+
+// NewSoundtrackManager constructs a SoundtrackManager which loads tracks from dir in fsys and plays them in ctx.
+func NewSoundtrackManager(ctx *audio.Context, fsys fs.FS, dir string) *SoundtrackManager {
+	result := &SoundtrackManager{ctx: ctx, fsys: fsys, dir: dir, volume: 1}
+	result.open = result.load
+	return result
+}
+
+// CrossFadeTo fades out the current track while fading in the named track over durationFrames frames. An empty name
+// fades to silence. The incoming track starts silent and reaches full volume after durationFrames calls to Update.
+// Nothing happens if the named track is already playing or fading in. A cross-fade in progress is finished immediately
+// before the new one begins.
+func (m *SoundtrackManager) CrossFadeTo(trackName string, durationFrames int) {
+	if m.fading && trackName == m.nextName || !m.fading && trackName == m.currentName {
+		return
+	}
+	if m.fading {
+		m.finishFade()
+	}
+	m.nextName = trackName
+	m.next = m.open(trackName)
+	if m.next != nil {
+		m.next.SetVolume(0)
+		m.next.Play()
+	}
+	m.fading, m.fadeFrames, m.fadeDuration = true, 0, max(durationFrames, 1)
+}
+
+// Update advances the cross-fade in progress, if any. It should be called once per frame.
+func (m *SoundtrackManager) Update() {
+	if !m.fading {
+		return
+	}
+	m.fadeFrames++
+	t := float64(m.fadeFrames) / float64(m.fadeDuration)
+	if m.fadeFrames >= m.fadeDuration {
+		m.finishFade()
+		return
+	}
+	if m.current != nil {
+		m.current.SetVolume((1 - t) * m.volume)
+	}
+	if m.next != nil {
+		m.next.SetVolume(t * m.volume)
+	}
+}
+
+// SetVolume sets the volume scaling all tracks, between 0 and 1.
+func (m *SoundtrackManager) SetVolume(v float64) {
+	m.volume = min(max(v, 0), 1)
+	t := 0.0
+	if m.fading {
+		t = float64(m.fadeFrames) / float64(m.fadeDuration)
+	}
+	if m.current != nil {
+		m.current.SetVolume((1 - t) * m.volume)
+	}
+	if m.next != nil {
+		m.next.SetVolume(t * m.volume)
+	}
+}
+
+// finishFade stops the outgoing track and makes the incoming track current at full volume.
+func (m *SoundtrackManager) finishFade() {
+	if m.current != nil {
+		_ = m.current.Close()
+	}
+	m.current, m.currentName = m.next, m.nextName
+	m.next, m.nextName = nil, ""
+	m.fading = false
+	if m.current != nil {
+		m.current.SetVolume(m.volume)
+	}
+}
+
+// load constructs a looping player for the named track, returning nil if the name is empty or the track could not be
+// loaded.
+func (m *SoundtrackManager) load(name string) track {
+	if name == "" {
+		return nil
+	}
+	data, err := fs.ReadFile(m.fsys, path.Join(m.dir, name+".wav"))
+	if err != nil {
+		slog.Warn("could not load track", "name", name, "err", err)
+		return nil
+	}
+	stream, err := wav.DecodeWithSampleRate(m.ctx.SampleRate(), bytes.NewReader(data))
+	if err != nil {
+		slog.Warn("could not decode track", "name", name, "err", err)
+		return nil
+	}
+	player, err := m.ctx.NewPlayer(audio.NewInfiniteLoop(stream, stream.Length()))
+	if err != nil {
+		slog.Warn("could not play track", "name", name, "err", err)
+		return nil
+	}
+	return player
+}
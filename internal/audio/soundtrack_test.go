@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"testing"
+)
+
+// fakeTrack records the volume a SoundtrackManager sets on a track.
+type fakeTrack struct {
+	volume          float64
+	playing, closed bool
+}
+
+func (f *fakeTrack) Play()                    { f.playing = true }
+func (f *fakeTrack) SetVolume(volume float64) { f.volume = volume }
+func (f *fakeTrack) Close() error             { f.closed = true; return nil }
+
+// newTestManager returns a SoundtrackManager which plays a fakeTrack for every name but the empty name.
+func newTestManager() (*SoundtrackManager, map[string]*fakeTrack) {
+	tracks := make(map[string]*fakeTrack)
+	m := &SoundtrackManager{volume: 1}
+	m.open = func(name string) track {
+		if name == "" {
+			return nil
+		}
+		tracks[name] = &fakeTrack{}
+		return tracks[name]
+	}
+	return m, tracks
+}
+
+func TestCrossFadeReachesFullVolume(t *testing.T) {
+	tests := []int{1, 2, 10, 60}
+	for _, duration := range tests {
+		m, tracks := newTestManager()
+		m.CrossFadeTo("intro", 1)
+		m.Update()
+		m.CrossFadeTo("cave", duration)
+		intro, cave := tracks["intro"], tracks["cave"]
+		if !cave.playing || cave.volume != 0 {
+			t.Fatalf("duration %d: incoming track playing = %v at volume %v; want playing at volume 0", duration,
+				cave.playing, cave.volume)
+		}
+		for frame := 1; frame < duration; frame++ {
+			m.Update()
+			if cave.volume >= 1 {
+				t.Fatalf("duration %d: incoming volume = %v after %d frames; want less than 1", duration, cave.volume,
+					frame)
+			}
+			if got := intro.volume + cave.volume; got < 0.999 || got > 1.001 {
+				t.Errorf("duration %d: volumes sum to %v after %d frames; want 1", duration, got, frame)
+			}
+		}
+		m.Update()
+		if cave.volume != 1 {
+			t.Errorf("duration %d: incoming volume = %v after %d frames; want 1", duration, cave.volume, duration)
+		}
+		if !intro.closed {
+			t.Errorf("duration %d: outgoing track was not closed once the fade finished", duration)
+		}
+	}
+}
+
+func TestCrossFadeScalesWithVolume(t *testing.T) {
+	m, tracks := newTestManager()
+	m.CrossFadeTo("cave", 4)
+	m.Update()
+	m.Update()
+	m.SetVolume(0.5)
+	if got := tracks["cave"].volume; got != 0.25 {
+		t.Errorf("volume halfway through fade at SetVolume(0.5) = %v; want 0.25", got)
+	}
+	m.Update()
+	m.Update()
+	if got := tracks["cave"].volume; got != 0.5 {
+		t.Errorf("volume after fade at SetVolume(0.5) = %v; want 0.5", got)
+	}
+}
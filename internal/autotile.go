@@ -0,0 +1,307 @@
+package internal
+
+import (
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+)
+
+const (
+	autoRuleAnyValue = 1000001  // autoRuleAnyValue in a rule pattern matches any non-empty cell.
+	autoRuleNoValue  = -1000001 // autoRuleNoValue in a rule pattern matches only empty cells.
+)
+
+// AutoRule is a single LDtk auto-layer rule. It places one of its tiles in each cell whose neighbourhood matches its
+// pattern.
+type AutoRule struct {
+	uid          int64
+	size         int     // size is the width and height of the pattern, in cells; always odd.
+	pattern      []int64 // pattern holds the IntGrid value required of each cell in the neighbourhood; 0 matches any.
+	tileIDs      []int
+	chance       float64 // chance is the probability that the rule is applied to a matching cell.
+	breakOnMatch bool    // breakOnMatch prevents any later rules from being applied to a matching cell.
+	flipX, flipY bool    // flipX and flipY allow the pattern to match when mirrored, flipping the tile to match.
+	xModulo      int
+	yModulo      int
+	xOffset      int
+	yOffset      int
+	outOfBounds  *int64 // outOfBounds is the value of cells outside the level; if nil, such cells never match.
+}
+
+// AutoTiler places tiles on an auto-layer by evaluating its rules against the IntGrid of its source layer. This
+// replaces the tiles baked into the LDtk file and allows tiles to be recomputed when cells change at runtime.
+//
+// Rules in "Stamp" tile mode are not supported and are skipped, as are the checker and Perlin filters. Random choices
+// are made by hashing each cell, so they are stable between runs but do not match those made by LDtk.
+type AutoTiler struct {
+	SourceLayer UID // SourceLayer is the UID of the layer definition whose IntGrid the rules are evaluated against.
+
+	rules     []AutoRule
+	gridSize  int // gridSize, spacing, padding, and tilesWide describe the layout of the tileset.
+	spacing   int
+	padding   int
+	tilesWide int
+	radius    int // radius is the furthest any rule looks from the cell it is evaluated at, in cells.
+}
+
+// LoadAutoTilers constructs an AutoTiler for each layer definition with auto-layer rules, keyed by layer definition
+// UID.
+func LoadAutoTilers(json *ldtk.LdtkJSON) map[UID]*AutoTiler {
+	tilesets := make(map[UID]ldtk.TilesetDefinition, len(json.Defs.Tilesets))
+	for _, ts := range json.Defs.Tilesets {
+		tilesets[ts.Uid] = ts
+	}
+	result := make(map[UID]*AutoTiler)
+	for _, def := range json.Defs.Layers {
+		if len(def.AutoRuleGroups) == 0 || def.TilesetDefUid == nil {
+			continue
+		}
+		ts, ok := tilesets[*def.TilesetDefUid]
+		if !ok {
+			continue
+		}
+		tiler := &AutoTiler{
+			SourceLayer: def.Uid,
+			gridSize:    int(ts.TileGridSize),
+			spacing:     int(ts.Spacing),
+			padding:     int(ts.Padding),
+			tilesWide:   int(ts.CWid),
+		}
+		if def.AutoSourceLayerDefUid != nil {
+			tiler.SourceLayer = *def.AutoSourceLayerDefUid
+		}
+		for _, group := range def.AutoRuleGroups {
+			if !group.Active {
+				continue
+			}
+			for _, rule := range group.Rules {
+				if !rule.Active || rule.TileMode == ldtk.Stamp || len(rule.TileIDS) == 0 {
+					continue
+				}
+				tiler.addRule(rule)
+			}
+		}
+		if len(tiler.rules) > 0 {
+			result[def.Uid] = tiler
+		}
+	}
+	return result
+}
+
+// addRule converts the provided LDtk rule definition and adds it to this tiler. Rules using the checker or Perlin
+// filters are skipped, since the cells they match cannot be reproduced.
+func (t *AutoTiler) addRule(def ldtk.AutoLayerRuleDefinition) {
+	if def.PerlinActive || def.Checker != ldtk.CheckerNone {
+		return
+	}
+	rule := AutoRule{
+		uid:          def.Uid,
+		size:         int(def.Size),
+		pattern:      def.Pattern,
+		chance:       def.Chance,
+		breakOnMatch: def.BreakOnMatch,
+		flipX:        def.FlipX,
+		flipY:        def.FlipY,
+		xModulo:      max(int(def.XModulo), 1),
+		yModulo:      max(int(def.YModulo), 1),
+		xOffset:      int(def.XOffset),
+		yOffset:      int(def.YOffset),
+		outOfBounds:  def.OutOfBoundsValue,
+	}
+	for _, id := range def.TileIDS {
+		rule.tileIDs = append(rule.tileIDs, int(id))
+	}
+	t.rules = append(t.rules, rule)
+	t.radius = max(t.radius, rule.size/2)
+}
+
+// CellValueFunc returns the IntGrid value of the provided cell. ok is false if the cell lies outside the level.
+type CellValueFunc func(cx, cy int) (value int, ok bool)
+
+// TilesAt returns the tiles placed in the provided cell of a layer with the provided grid size, in draw order.
+func (t *AutoTiler) TilesAt(cx, cy, gridSize int, value CellValueFunc) []Tile {
+	var result []Tile
+	for _, rule := range t.rules {
+		if posMod(cx-rule.xOffset, rule.xModulo) != 0 || posMod(cy-rule.yOffset, rule.yModulo) != 0 {
+			continue
+		}
+		flipBits, ok := rule.match(cx, cy, value)
+		if !ok {
+			continue
+		}
+		hash := cellHash(rule.uid, cx, cy)
+		if float64(hash%10000)/10000 >= rule.chance {
+			continue
+		}
+		id := rule.tileIDs[int((hash/10000)%uint32(len(rule.tileIDs)))]
+		result = append(result, Tile{
+			PxCoords:  IVec2{X: cx * gridSize, Y: cy * gridSize},
+			SrcCoords: t.srcCoords(id),
+			TileID:    id,
+			FlipBits:  flipBits,
+		})
+		if rule.breakOnMatch {
+			break
+		}
+	}
+	// earlier rules are drawn on top of later ones.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// Apply replaces the tiles of the provided layer by evaluating this tiler's rules against the IntGrid of source.
+func (t *AutoTiler) Apply(layer, source *TileLayer) {
+	value := gridValueFunc(source)
+	layer.Tiles = layer.Tiles[:0]
+	for cy := 0; cy < source.CellDims.H; cy++ {
+		for cx := 0; cx < source.CellDims.W; cx++ {
+			layer.Tiles = append(layer.Tiles, t.TilesAt(cx, cy, layer.GridSize, value)...)
+		}
+	}
+}
+
+// srcCoords returns the pixel coordinates of the tile with the provided ID in the tileset.
+func (t *AutoTiler) srcCoords(id int) IVec2 {
+	return IVec2{
+		X: t.padding + (id%t.tilesWide)*(t.gridSize+t.spacing),
+		Y: t.padding + (id/t.tilesWide)*(t.gridSize+t.spacing),
+	}
+}
+
+// match returns true if the pattern of this rule matches the neighbourhood of the provided cell, along with the flip
+// bits of the tile to place. Mirrored patterns are only tried if the rule allows them.
+func (r *AutoRule) match(cx, cy int, value CellValueFunc) (flipBits byte, ok bool) {
+	for _, flip := range []byte{0, 1, 2, 3} {
+		if flip&1 != 0 && !r.flipX || flip&2 != 0 && !r.flipY {
+			continue
+		}
+		if r.matchFlipped(cx, cy, flip&1 != 0, flip&2 != 0, value) {
+			return flip, true
+		}
+	}
+	return 0, false
+}
+
+// matchFlipped returns true if the pattern of this rule, mirrored as requested, matches the neighbourhood of the
+// provided cell.
+func (r *AutoRule) matchFlipped(cx, cy int, flipX, flipY bool, value CellValueFunc) bool {
+	half := r.size / 2
+	for py := 0; py < r.size; py++ {
+		for px := 0; px < r.size; px++ {
+			want := r.pattern[px+py*r.size]
+			if want == 0 {
+				continue
+			}
+			dx, dy := px-half, py-half
+			if flipX {
+				dx = -dx
+			}
+			if flipY {
+				dy = -dy
+			}
+			v, ok := value(cx+dx, cy+dy)
+			if !ok {
+				if r.outOfBounds == nil {
+					return false
+				}
+				v = int(*r.outOfBounds)
+			}
+			if !patternMatches(want, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// patternMatches returns true if the provided IntGrid value satisfies a single entry of a rule pattern.
+func patternMatches(want int64, v int) bool {
+	switch {
+	case want == autoRuleAnyValue:
+		return v != 0
+	case want == autoRuleNoValue:
+		return v == 0
+	case want > 0:
+		return int64(v) == want
+	default:
+		return int64(v) != -want
+	}
+}
+
+// gridValueFunc returns a CellValueFunc reading the IntGrid of the provided layer.
+func gridValueFunc(layer *TileLayer) CellValueFunc {
+	return func(cx, cy int) (int, bool) {
+		idx, ok := layer.cellIdx(cx, cy)
+		if !ok {
+			return 0, false
+		}
+		return layer.Grid[idx], true
+	}
+}
+
+// cellHash returns a well-mixed hash of the provided seed and cell coordinates.
+func cellHash(seed int64, cx, cy int) uint32 {
+	h := uint32(seed)*0x9e3779b1 ^ uint32(cx)*0x85ebca6b ^ uint32(cy)*0xc2b2ae35
+	h ^= h >> 16
+	h *= 0x7feb352d
+	h ^= h >> 15
+	h *= 0x846ca68b
+	h ^= h >> 16
+	return h
+}
+
+// applyAutoTilers replaces the tiles of each auto-layer in the provided level using the provided tilers.
+func applyAutoTilers(level *Level, tilers map[UID]*AutoTiler) {
+	for _, layer := range level.layers {
+		tiler, ok := tilers[layer.UID]
+		if !ok {
+			continue
+		}
+		if source := level.layerByUID(tiler.SourceLayer); source != nil {
+			tiler.Apply(layer, source)
+		}
+	}
+}
+
+// layerByUID returns the layer of this level with the provided layer definition UID, or nil if there is none.
+func (l *Level) layerByUID(uid UID) *TileLayer {
+	for _, layer := range l.layers {
+		if layer.UID == uid {
+			return layer
+		}
+	}
+	return nil
+}
+
+// retileAround recomputes the auto-layer tiles of every cell whose rules could see the provided cell, then redraws
+// them. Broken cells are treated as empty.
+func (s *PlatformerScene) retileAround(cell IVec2) {
+	for _, layer := range s.level.layers {
+		tiler, ok := s.gdat.AutoTilers[layer.UID]
+		if !ok || layer.GridSize != s.cellSize {
+			continue
+		}
+		source := s.level.layerByUID(tiler.SourceLayer)
+		if source == nil {
+			continue
+		}
+		grid := gridValueFunc(source)
+		value := func(cx, cy int) (int, bool) {
+			if s.brokenCells[IVec2{X: cx, Y: cy}] {
+				return 0, true
+			}
+			return grid(cx, cy)
+		}
+		if s.retiled[layer] == nil {
+			s.retiled[layer] = make(map[IVec2][]Tile)
+		}
+		r := tiler.radius
+		for cx := cell.X - r; cx <= cell.X+r; cx++ {
+			for cy := cell.Y - r; cy <= cell.Y+r; cy++ {
+				s.retiled[layer][IVec2{X: cx, Y: cy}] = tiler.TilesAt(cx, cy, layer.GridSize, value)
+			}
+		}
+		size := layer.GridSize
+		s.MarkDirty(IRect{X: (cell.X - r) * size, Y: (cell.Y - r) * size, W: (2*r + 1) * size, H: (2*r + 1) * size})
+	}
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+	"reflect"
+	"testing"
+)
+
+// testRule returns a rule definition placing the provided tile wherever the provided pattern matches.
+func testRule(tileID int64, pattern ...int64) ldtk.AutoLayerRuleDefinition {
+	size := int64(1)
+	for size*size < int64(len(pattern)) {
+		size += 2
+	}
+	return ldtk.AutoLayerRuleDefinition{
+		Active:  true,
+		Chance:  1,
+		Checker: ldtk.CheckerNone,
+		Pattern: pattern,
+		Size:    size,
+		TileIDS: []int64{tileID},
+		Uid:     tileID,
+		XModulo: 1,
+		YModulo: 1,
+	}
+}
+
+// newTestTiler returns an AutoTiler for a tileset of 16 pixel tiles, 4 wide, with the provided rules.
+func newTestTiler(rules ...ldtk.AutoLayerRuleDefinition) *AutoTiler {
+	result := &AutoTiler{gridSize: testCellSize, tilesWide: 4}
+	for _, rule := range rules {
+		result.addRule(rule)
+	}
+	return result
+}
+
+// testGridValues returns a CellValueFunc reading the provided rows, in which '#' has the value 1 and every other
+// character has the value 0.
+func testGridValues(rows ...string) CellValueFunc {
+	return func(cx, cy int) (int, bool) {
+		if cy < 0 || cy >= len(rows) || cx < 0 || cx >= len(rows[cy]) {
+			return 0, false
+		}
+		if rows[cy][cx] == '#' {
+			return 1, true
+		}
+		return 0, true
+	}
+}
+
+func TestPatternMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		want int64
+		v    int
+		ok   bool
+	}{
+		{name: "any matches a value", want: autoRuleAnyValue, v: 2, ok: true},
+		{name: "any rejects empty", want: autoRuleAnyValue, v: 0, ok: false},
+		{name: "none matches empty", want: autoRuleNoValue, v: 0, ok: true},
+		{name: "none rejects a value", want: autoRuleNoValue, v: 1, ok: false},
+		{name: "value matches itself", want: 1, v: 1, ok: true},
+		{name: "value rejects another", want: 1, v: 2, ok: false},
+		{name: "value rejects empty", want: 1, v: 0, ok: false},
+		{name: "negative rejects its value", want: -1, v: 1, ok: false},
+		{name: "negative matches another", want: -1, v: 2, ok: true},
+		{name: "negative matches empty", want: -1, v: 0, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := patternMatches(tt.want, tt.v); got != tt.ok {
+				t.Errorf("patternMatches(%d, %d) = %v; want %v", tt.want, tt.v, got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAutoTilerTilesAt(t *testing.T) {
+	left := testRule(1, 0, 0, 0, 1, 0, 0, 0, 0, 0)  // left requires solid ground to the left of the cell.
+	above := testRule(2, 0, 1, 0, 0, 0, 0, 0, 0, 0) // above requires solid ground above the cell.
+	flipX, flipY := left, above
+	flipX.FlipX, flipY.FlipY = true, true
+	everyOther := testRule(3, autoRuleAnyValue)
+	everyOther.XModulo, everyOther.XOffset = 2, 1
+	breaking := testRule(4, autoRuleAnyValue)
+	breaking.BreakOnMatch = true
+	perlin, checker := testRule(5, autoRuleAnyValue), testRule(6, autoRuleAnyValue)
+	perlin.PerlinActive, checker.Checker = true, ldtk.Horizontal
+
+	type tile struct {
+		id       int
+		flipBits byte
+	}
+	tests := []struct {
+		name  string
+		rules []ldtk.AutoLayerRuleDefinition
+		rows  []string
+		cell  IVec2
+		want  []tile
+	}{
+		{name: "pattern matches", rules: []ldtk.AutoLayerRuleDefinition{left},
+			rows: []string{"...", "#..", "..."}, cell: IVec2{X: 1, Y: 1}, want: []tile{{id: 1}}},
+		{name: "mirrored pattern needs flipX", rules: []ldtk.AutoLayerRuleDefinition{left},
+			rows: []string{"...", "..#", "..."}, cell: IVec2{X: 1, Y: 1}},
+		{name: "flipX", rules: []ldtk.AutoLayerRuleDefinition{flipX},
+			rows: []string{"...", "..#", "..."}, cell: IVec2{X: 1, Y: 1}, want: []tile{{id: 1, flipBits: TileFlipX}}},
+		{name: "mirrored pattern needs flipY", rules: []ldtk.AutoLayerRuleDefinition{above},
+			rows: []string{"...", "...", ".#."}, cell: IVec2{X: 1, Y: 1}},
+		{name: "flipY", rules: []ldtk.AutoLayerRuleDefinition{flipY},
+			rows: []string{"...", "...", ".#."}, cell: IVec2{X: 1, Y: 1}, want: []tile{{id: 2, flipBits: TileFlipY}}},
+		{name: "out of bounds never matches", rules: []ldtk.AutoLayerRuleDefinition{left},
+			rows: []string{"#"}, cell: IVec2{}},
+		{name: "modulo skips cells", rules: []ldtk.AutoLayerRuleDefinition{everyOther},
+			rows: []string{"###"}, cell: IVec2{X: 0}},
+		{name: "offset shifts the modulo", rules: []ldtk.AutoLayerRuleDefinition{everyOther},
+			rows: []string{"###"}, cell: IVec2{X: 1}, want: []tile{{id: 3}}},
+		{name: "modulo repeats", rules: []ldtk.AutoLayerRuleDefinition{everyOther},
+			rows: []string{"####"}, cell: IVec2{X: 3}, want: []tile{{id: 3}}},
+		{name: "later rules are drawn first", rules: []ldtk.AutoLayerRuleDefinition{everyOther, flipX},
+			rows: []string{"...", "##.", "..."}, cell: IVec2{X: 1, Y: 1}, want: []tile{{id: 1}, {id: 3}}},
+		{name: "breakOnMatch stops later rules", rules: []ldtk.AutoLayerRuleDefinition{breaking, everyOther},
+			rows: []string{"##"}, cell: IVec2{X: 1}, want: []tile{{id: 4}}},
+		{name: "breakOnMatch needs a match", rules: []ldtk.AutoLayerRuleDefinition{breaking, everyOther},
+			rows: []string{".."}, cell: IVec2{X: 1}},
+		{name: "perlin rules are skipped", rules: []ldtk.AutoLayerRuleDefinition{perlin},
+			rows: []string{"#"}, cell: IVec2{}},
+		{name: "checker rules are skipped", rules: []ldtk.AutoLayerRuleDefinition{checker},
+			rows: []string{"#"}, cell: IVec2{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []tile
+			for _, tl := range newTestTiler(tt.rules...).TilesAt(tt.cell.X, tt.cell.Y, testCellSize,
+				testGridValues(tt.rows...)) {
+				got = append(got, tile{id: tl.TileID, flipBits: tl.FlipBits})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TilesAt(%d, %d) = %v; want %v", tt.cell.X, tt.cell.Y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetileAround(t *testing.T) {
+	s := newTestScene(t, grid(3, 3)...)
+	layer := &TileLayer{UID: 1, GridSize: testCellSize, CellDims: IDim{W: 3, H: 3}, Grid: []int{
+		0, 0, 0,
+		1, 1, 1,
+		1, 1, 1,
+	}}
+	s.level.layers = []*TileLayer{layer}
+	// top places tile 1 on solid cells with nothing above them.
+	top := testRule(1, 0, autoRuleNoValue, 0, 0, autoRuleAnyValue, 0, 0, 0, 0)
+	s.gdat.AutoTilers = map[UID]*AutoTiler{layer.UID: newTestTiler(top)}
+	s.gdat.AutoTilers[layer.UID].SourceLayer = layer.UID
+
+	s.brokenCells[IVec2{X: 1, Y: 1}] = true
+	s.retileAround(IVec2{X: 1, Y: 1})
+
+	tests := []struct {
+		cell IVec2
+		want []int
+	}{
+		{cell: IVec2{X: 0, Y: 1}, want: []int{1}},
+		{cell: IVec2{X: 1, Y: 1}},
+		{cell: IVec2{X: 1, Y: 2}, want: []int{1}},
+		{cell: IVec2{X: 0, Y: 2}},
+		{cell: IVec2{X: 2, Y: 2}},
+	}
+	for _, tt := range tests {
+		tiles, ok := s.retiled[layer][tt.cell]
+		if !ok {
+			t.Errorf("cell %v was not retiled", tt.cell)
+			continue
+		}
+		var got []int
+		for _, tl := range tiles {
+			got = append(got, tl.TileID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tiles of cell %v after breaking (1, 1) = %v; want %v", tt.cell, got, tt.want)
+		}
+	}
+	if _, ok := s.retiled[layer][IVec2{X: 3, Y: 1}]; ok {
+		t.Errorf("cell (3, 1) was retiled; want only cells within the radius of the rules")
+	}
+	if len(s.dirtyRegions) != 1 || s.dirtyRegions[0] != (IRect{W: 3 * testCellSize, H: 3 * testCellSize}) {
+		t.Errorf("dirty regions = %v; want the 3x3 cells around (1, 1)", s.dirtyRegions)
+	}
+}
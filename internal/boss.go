@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const (
+	BossEnemyHP         = 20  // BossEnemyHP is the default health of each BossEnemy.
+	BossProjectileLife  = 180 // BossProjectileLife is the number of frames each of the boss's shots lasts.
+	BossProjectileSpeed = 2.5 // BossProjectileSpeed is the speed of each of the boss's shots in pixels per frame.
+	BossIntroFrames     = 60  // BossIntroFrames is the number of frames the boss waits after its intro flash.
+)
+
+// DefaultBossPhaseThresholds are the fractions of max HP at which a boss enters each successive phase.
+var DefaultBossPhaseThresholds = []float64{0.66, 0.33}
+
+// bossPhase describes the behaviour of a boss during one phase of the fight.
+type bossPhase struct {
+	speed         float64 // speed is how quickly the boss walks toward the player.
+	shootInterval int     // shootInterval is the number of frames between each shot, or zero to never shoot.
+	color         color.RGBA
+}
+
+// bossPhases lists the behaviour of the boss during each phase. Phases beyond the end of the list reuse the last.
+var bossPhases = []bossPhase{
+	{speed: 0.5, color: color.RGBA{R: 0x80, G: 0x20, B: 0x20, A: 0xff}},
+	{speed: 0.75, shootInterval: 90, color: color.RGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0xff}},
+	{speed: 1, shootInterval: 45, color: color.RGBA{R: 0xff, G: 0x20, B: 0x60, A: 0xff}},
+}
+
+// BossHealth is published with TopicBossHealthChanged.
+type BossHealth struct {
+	HP, MaxHP, Phase int
+}
+
+// BossEnemy chases the player, growing faster and more aggressive as its health drops past each phase threshold.
+type BossEnemy struct {
+	enemyBody
+	maxHP           int
+	phase           int       // phase is the index of the current phase; it starts at zero.
+	phaseThresholds []float64 // phaseThresholds are the fractions of maxHP at which each subsequent phase begins.
+	shootCooldown   Cooldown  // shootCooldown lasts for the shoot interval of the phase in which it was started.
+	announced       bool      // announced is set once the boss's intro has started.
+	awake           bool      // awake is set once the boss's intro has finished and it starts fighting.
+}
+
+// NewBossEnemy constructs a BossEnemy from the provided entity. The "hp" field overrides the boss's max HP.
+func NewBossEnemy(s *PlatformerScene, entity *Entity) (Enemy, error) {
+	maxHP := entity.FieldInt("hp")
+	if maxHP <= 0 {
+		maxHP = BossEnemyHP
+	}
+	return &BossEnemy{
+		enemyBody:       newEnemyBody(s, entity, maxHP),
+		maxHP:           maxHP,
+		phaseThresholds: DefaultBossPhaseThresholds,
+	}, nil
+}
+
+// Update chases and shoots at the player according to the current phase.
+func (e *BossEnemy) Update() {
+	if !e.announced {
+		e.announced = true
+		e.scene.RunSequence(e.intro())
+	}
+	if !e.awake {
+		return
+	}
+	phase := e.currentPhase()
+	player := e.scene.nearestPlayer(e.Pos)
+	if player == nil {
+		return
+	}
+	dir := sign(player.Pos.X - e.Pos.X)
+	e.Vel.X = float64(dir) * phase.speed
+	e.move()
+
+	if phase.shootInterval == 0 {
+		return
+	}
+	if !e.shootCooldown.Ready() {
+		e.shootCooldown.Tick()
+		return
+	}
+	if dir == 0 {
+		dir = 1
+	}
+	center := e.Hitbox().Center().Vec2()
+	e.scene.projectiles.Spawn(center, Vec2{X: float64(dir) * BossProjectileSpeed}, BossProjectileLife, 1)
+	e.shootCooldown = NewCooldown(phase.shootInterval)
+	e.shootCooldown.Start()
+}
+
+// TakeDamage reduces the boss's health, advancing to the next phase each time a threshold is reached. Returns true if
+// the boss died.
+func (e *BossEnemy) TakeDamage(amount int) bool {
+	died := e.enemyBody.TakeDamage(amount)
+	for e.phase < len(e.phaseThresholds) && float64(e.HP) <= e.phaseThresholds[e.phase]*float64(e.maxHP) {
+		e.phase++
+	}
+	if died {
+		e.scene.game.bus.Publish(TopicBossDefeated, nil)
+	} else {
+		e.publishHealth()
+	}
+	return died
+}
+
+// intro returns the Sequence played when the boss is first seen: the screen flashes, then the health bar appears and
+// the boss starts fighting after a pause.
+func (e *BossEnemy) intro() *Sequence {
+	return NewSequence(
+		func() int {
+			e.scene.flashFrames = TeleportFlashFrames
+			return BossIntroFrames
+		},
+		func() int {
+			e.publishHealth()
+			e.awake = true
+			return 0
+		},
+	)
+}
+
+// currentPhase returns the behaviour of the boss during its current phase.
+func (e *BossEnemy) currentPhase() bossPhase {
+	return bossPhases[min(e.phase, len(bossPhases)-1)]
+}
+
+// publishHealth publishes the boss's health so that it can be shown in the HUD.
+func (e *BossEnemy) publishHealth() {
+	e.scene.game.bus.Publish(TopicBossHealthChanged, BossHealth{HP: e.HP, MaxHP: e.maxHP, Phase: e.phase})
+}
+
+// Draw draws the boss as a placeholder rectangle coloured by its phase.
+func (e *BossEnemy) Draw(screen *ebiten.Image, camera IVec2) {
+	e.drawRect(screen, camera, e.currentPhase().color)
+}
@@ -0,0 +1,57 @@
+package internal
+
+import "testing"
+
+func TestBossEnemyPhaseThresholds(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	tests := []struct {
+		maxHP      int
+		thresholds []float64
+		hp         int
+		want       int
+	}{
+		{20, []float64{0.5, 0.25}, 11, 0},
+		{20, []float64{0.5, 0.25}, 10, 1},
+		{20, []float64{0.5, 0.25}, 6, 1},
+		{20, []float64{0.5, 0.25}, 5, 2},
+		{20, []float64{0.5, 0.25}, 1, 2},
+		{100, DefaultBossPhaseThresholds, 67, 0},
+		{100, DefaultBossPhaseThresholds, 66, 1},
+		{100, DefaultBossPhaseThresholds, 34, 1},
+		{100, DefaultBossPhaseThresholds, 33, 2},
+	}
+	for _, tt := range tests {
+		enemy, err := NewBossEnemy(s, &Entity{ID: EtyBossEnemy, Fields: map[string]any{"hp": float64(tt.maxHP)}})
+		if err != nil {
+			t.Fatalf("could not create boss: %v", err)
+		}
+		boss := enemy.(*BossEnemy)
+		boss.phaseThresholds = tt.thresholds
+		if boss.TakeDamage(tt.maxHP - tt.hp) {
+			t.Fatalf("boss died at %d HP", tt.hp)
+		}
+		if boss.phase != tt.want {
+			t.Errorf("boss with thresholds %v is in phase %d at %d/%d HP; want %d", tt.thresholds, boss.phase, tt.hp,
+				tt.maxHP, tt.want)
+		}
+	}
+}
+
+func TestBossEnemyPhasesOneHitAtATime(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	enemy, err := NewBossEnemy(s, &Entity{ID: EtyBossEnemy, Fields: map[string]any{"hp": 4.0}})
+	if err != nil {
+		t.Fatalf("could not create boss: %v", err)
+	}
+	boss := enemy.(*BossEnemy)
+	boss.phaseThresholds = []float64{0.75, 0.5}
+	for _, want := range []int{1, 2, 2} {
+		boss.TakeDamage(1)
+		if boss.phase != want {
+			t.Errorf("boss is in phase %d at %d HP; want %d", boss.phase, boss.HP, want)
+		}
+	}
+	if !boss.TakeDamage(1) || !boss.Dead() {
+		t.Errorf("boss survived losing all of its HP")
+	}
+}
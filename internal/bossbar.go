@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/colornames"
+)
+
+const (
+	bossBarWidth  = 160 // bossBarWidth is the width of the boss health bar at full health, in pixels.
+	bossBarHeight = 6   // bossBarHeight is the height of the boss health bar, in pixels.
+)
+
+// BossHealthBar draws a wide bar across the top of the screen showing the health of the active boss.
+type BossHealthBar struct {
+	health  BossHealth
+	visible bool // visible is set while a boss is active.
+}
+
+// Draw draws the health bar centered horizontally at the provided y-coordinate, if a boss is active.
+func (b *BossHealthBar) Draw(screen *ebiten.Image, y int) {
+	if !b.visible || b.health.MaxHP <= 0 {
+		return
+	}
+	x := float32(screen.Bounds().Dx()-bossBarWidth) / 2
+	filled := float32(bossBarWidth * b.health.HP / b.health.MaxHP)
+	phase := bossPhases[min(b.health.Phase, len(bossPhases)-1)]
+	vector.DrawFilledRect(screen, x-1, float32(y)-1, bossBarWidth+2, bossBarHeight+2, colornames.Black, false)
+	vector.DrawFilledRect(screen, x, float32(y), bossBarWidth, bossBarHeight, colornames.Dimgray, false)
+	vector.DrawFilledRect(screen, x, float32(y), filled, bossBarHeight, phase.color, false)
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"image/color"
+	"log/slog"
+)
+
+// BreakDropItem is the key in tile custom data naming the entity ID of the collectible dropped when a breakable cell
+// drawn with that tile is destroyed, e.g. "break_drop=Coin".
+const BreakDropItem = "break_drop"
+
+var breakParticleColor = color.RGBA{R: 0xa0, G: 0x90, B: 0x80, A: 0xff}
+
+// breakCellsIn destroys every breakable cell overlapping the provided region.
+func (s *PlatformerScene) breakCellsIn(region IRect) {
+	minX, minY := s.screenToCell(float64(region.X), float64(region.Y))
+	maxX, maxY := s.screenToCell(float64(region.X+region.W-1), float64(region.Y+region.H-1))
+	for cx := minX; cx <= maxX; cx++ {
+		for cy := minY; cy <= maxY; cy++ {
+			s.breakCell(IVec2{X: cx, Y: cy})
+		}
+	}
+}
+
+// breakCellsInRadius destroys every breakable cell whose center lies within radius of center.
+func (s *PlatformerScene) breakCellsInRadius(center Vec2, radius float64) {
+	size := float64(s.cellSize)
+	minX, minY := s.screenToCell(center.X-radius, center.Y-radius)
+	maxX, maxY := s.screenToCell(center.X+radius, center.Y+radius)
+	for cx := minX; cx <= maxX; cx++ {
+		for cy := minY; cy <= maxY; cy++ {
+			cellCenter := Vec2{X: (float64(cx) + 0.5) * size, Y: (float64(cy) + 0.5) * size}
+			if cellCenter.Sub(center).Mag() <= radius {
+				s.breakCell(IVec2{X: cx, Y: cy})
+			}
+		}
+	}
+}
+
+// breakCell destroys the provided cell if it is breakable, retiling its neighbours and dropping any item
+// named by the BreakDropItem of its tiles. Cells without a BreakDropItem roll their tiles' DropTableKey table instead.
+// Returns true if the cell was destroyed.
+func (s *PlatformerScene) breakCell(cell IVec2) bool {
+	if s.gridDataI(cell.X, cell.Y) != IntGridBreakable {
+		return false
+	}
+	s.setGridDataI(cell.X, cell.Y, IntGridNothing)
+	s.brokenCells[cell] = true
+	s.retileAround(cell)
+
+	size := s.cellSize
+	region := IRect{X: cell.X * size, Y: cell.Y * size, W: size, H: size}
+	s.MarkDirty(region)
+	s.particles.Burst(region.Center().Vec2(), 10, 1.5, 20, breakParticleColor)
+	s.game.bus.Publish(TopicTileDestroyed, cell)
+	if drop := s.breakDrop(cell); drop != "" {
+		s.dropItem(drop, region)
+	}
+	return true
+}
+
+// breakDrop returns the entity ID of the item dropped by the provided cell, or the empty string if nothing drops.
+func (s *PlatformerScene) breakDrop(cell IVec2) EntityID {
+	if drop := s.cellTileData(cell, BreakDropItem); drop != "" {
+		return drop
+	}
+	tableStr := s.cellTileData(cell, DropTableKey)
+	if tableStr == "" {
+		return ""
+	}
+	table, err := ParseDropTable(tableStr)
+	if err != nil {
+		slog.Warn("invalid drop table", "cell", cell, "err", err)
+		return ""
+	}
+	item := table.Roll(s.rng.Rand)
+	id, ok := dropEntityID(item)
+	if !ok && item != DropNothing {
+		slog.Warn("unknown item in drop table", "cell", cell, "item", item)
+	}
+	return id
+}
+
+// cellTileData returns the value of the provided key in the custom data of any tile drawn in the provided cell, or the
+// empty string if no tile sets it.
+func (s *PlatformerScene) cellTileData(cell IVec2, key string) string {
+	pos := cell.Scale(s.cellSize)
+	for _, layer := range s.level.layers {
+		if layer.TileSetUID == nil {
+			continue
+		}
+		for _, tile := range layer.Tiles {
+			if tile.PxCoords != pos {
+				continue
+			}
+			if value := tileDataValue(s.gdat.TileCustomData(*layer.TileSetUID, tile.TileID), key); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// dropItem spawns a collectible with the provided entity ID occupying the provided region.
+func (s *PlatformerScene) dropItem(id EntityID, region IRect) {
+	item := &Entity{
+		ID:       id,
+		IID:      uuid.New(),
+		PxCoords: IVec2{X: region.X, Y: region.Y},
+		Dim:      IDim{W: region.W, H: region.H},
+	}
+	switch id {
+	case EtyCoin:
+		s.coins = append(s.coins, item)
+		s.totalCoins++
+	case EtyKey:
+		s.keyItems = append(s.keyItems, item)
+	case EtyPowerUpDoubleJump:
+		s.powerUps = append(s.powerUps, item)
+	default:
+		slog.Warn("unknown item dropped by breakable cell", "item", id)
+	}
+}
+
+// isBrokenTile returns true if the provided tile was drawn in a cell which has since been destroyed.
+func (s *PlatformerScene) isBrokenTile(tile Tile) bool {
+	if len(s.brokenCells) == 0 {
+		return false
+	}
+	cx, cy := s.screenToCell(float64(tile.PxCoords.X), float64(tile.PxCoords.Y))
+	return s.brokenCells[IVec2{X: cx, Y: cy}]
+}
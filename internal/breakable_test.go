@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestBreakCellClearsIndependently(t *testing.T) {
+	rows := grid(8, 4)
+	rows[2] = "..BBB..."
+	s := newTestScene(t, rows...)
+	bus := s.game.bus
+	s.game.bus = NewEventBus()
+	t.Cleanup(func() { s.game.bus = bus })
+	var destroyed []IVec2
+	s.game.bus.Subscribe(TopicTileDestroyed, func(data any) { destroyed = append(destroyed, data.(IVec2)) })
+
+	order := []IVec2{{X: 3, Y: 2}, {X: 2, Y: 2}, {X: 4, Y: 2}}
+	for i, cell := range order {
+		if !s.breakCell(cell) {
+			t.Fatalf("breakCell(%v) = false; want true", cell)
+		}
+		for j, other := range order {
+			want := IntGridBreakable
+			if j <= i {
+				want = IntGridNothing
+			}
+			if got := s.gridDataI(other.X, other.Y); got != want {
+				t.Errorf("cell %v is %v after breaking %v; want %v", other, got, order[:i+1], want)
+			}
+		}
+	}
+	if s.breakCell(order[0]) {
+		t.Errorf("breakCell(%v) = true for a cell which was already broken; want false", order[0])
+	}
+	if len(destroyed) != len(order) {
+		t.Fatalf("%d tile.destroyed events were published; want %d", len(destroyed), len(order))
+	}
+	for i, cell := range order {
+		if destroyed[i] != cell {
+			t.Errorf("tile.destroyed event %d was for %v; want %v", i, destroyed[i], cell)
+		}
+	}
+}
+
+func TestBreakCellsInRegion(t *testing.T) {
+	rows := grid(8, 4)
+	rows[2] = "..BBB..."
+	s := newTestScene(t, rows...)
+	s.breakCellsIn(IRect{X: 3 * testCellSize, Y: 2 * testCellSize, W: testCellSize, H: testCellSize})
+
+	for cx, want := range map[int]IntGridData{2: IntGridBreakable, 3: IntGridNothing, 4: IntGridBreakable} {
+		if got := s.gridDataI(cx, 2); got != want {
+			t.Errorf("cell (%d, 2) is %v after breaking only (3, 2); want %v", cx, got, want)
+		}
+	}
+}
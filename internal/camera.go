@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"math"
+	"math/rand"
+)
+
+// WorldCamera follows a target point through the level, keeping its view inside Bounds.
+type WorldCamera struct {
+	Pos        Vec2    // Pos is the point in world coordinates at the center of the view.
+	Size       IDim    // Size is the size of the view in pixels.
+	LerpFactor float64 // LerpFactor is the fraction of the distance to the target covered each update; 1 snaps.
+	Deadzone   IDim    // Deadzone is a region around Pos in which the target may move without the camera following.
+	Bounds     IRect   // Bounds is the region the view is kept inside, in world coordinates.
+
+	target      Vec2
+	shakes      []ShakeSource
+	shakeOffset Vec2 // shakeOffset is the sum of the offsets of every active shake, chosen during the last Update.
+}
+
+// ShakeSource is a single shake of the camera, whose magnitude decays linearly to zero over its duration.
+type ShakeSource struct {
+	magnitude  float64
+	duration   int
+	framesLeft int
+	rng        *rand.Rand
+}
+
+// offset returns a random offset within ±magnitude along each axis, scaled by the fraction of the shake remaining.
+func (s *ShakeSource) offset() Vec2 {
+	m := s.magnitude * float64(s.framesLeft) / float64(s.duration)
+	return Vec2{X: (s.rng.Float64()*2 - 1) * m, Y: (s.rng.Float64()*2 - 1) * m}
+}
+
+// Shake shakes the camera by up to magnitude pixels along each axis for the provided number of frames. Overlapping
+// shakes add together.
+func (c *WorldCamera) Shake(magnitude float64, duration int, rng *rand.Rand) {
+	if duration <= 0 {
+		return
+	}
+	c.shakes = append(c.shakes, ShakeSource{magnitude: magnitude, duration: duration, framesLeft: duration, rng: rng})
+}
+
+// Target sets the point the camera moves toward during each Update.
+func (c *WorldCamera) Target(pt Vec2) {
+	c.target = pt
+}
+
+// Update moves the camera toward its target, keeping the target inside the deadzone and the view inside Bounds.
+func (c *WorldCamera) Update() {
+	goal := Vec2{
+		X: followAxis(c.Pos.X, c.target.X, float64(c.Deadzone.W)/2),
+		Y: followAxis(c.Pos.Y, c.target.Y, float64(c.Deadzone.H)/2),
+	}
+	pos := c.Pos.Lerp(goal, c.LerpFactor)
+	c.Pos = Vec2{
+		X: clampAxis(pos.X, float64(c.Size.W), float64(c.Bounds.X), float64(c.Bounds.W)),
+		Y: clampAxis(pos.Y, float64(c.Size.H), float64(c.Bounds.Y), float64(c.Bounds.H)),
+	}
+	c.updateShakes()
+}
+
+// updateShakes sums the offsets of every active shake and removes any shakes which have finished.
+func (c *WorldCamera) updateShakes() {
+	c.shakeOffset = Vec2{}
+	remaining := c.shakes[:0]
+	for _, shake := range c.shakes {
+		c.shakeOffset = c.shakeOffset.Add(shake.offset())
+		shake.framesLeft--
+		if shake.framesLeft > 0 {
+			remaining = append(remaining, shake)
+		}
+	}
+	c.shakes = remaining
+}
+
+// Offset returns the offset added to world coordinates to find where they are drawn on screen, including any shake.
+// The offset is rounded to whole pixels so that tiles never fall between pixels.
+func (c *WorldCamera) Offset() Vec2 {
+	return Vec2{
+		X: math.Round(float64(c.Size.W)/2 - c.Pos.X + c.shakeOffset.X),
+		Y: math.Round(float64(c.Size.H)/2 - c.Pos.Y + c.shakeOffset.Y),
+	}
+}
+
+// ScreenToWorld converts the provided screen coordinates into world coordinates.
+func (c *WorldCamera) ScreenToWorld(screen Vec2) Vec2 {
+	return screen.Sub(c.Offset())
+}
+
+// followAxis returns the position along one axis which keeps target within halfDeadzone of pos.
+func followAxis(pos, target, halfDeadzone float64) float64 {
+	switch {
+	case target > pos+halfDeadzone:
+		return target - halfDeadzone
+	case target < pos-halfDeadzone:
+		return target + halfDeadzone
+	}
+	return pos
+}
+
+// clampAxis returns the center of a view along one axis which keeps a view of the provided size inside the range
+// [lo, lo+size). If the range is smaller than the view, the view is centered on the range.
+func clampAxis(center, view, lo, size float64) float64 {
+	if size <= view {
+		return lo + size/2
+	}
+	return min(max(center, lo+view/2), lo+size-view/2)
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// newTestCamera returns a 100×100 camera centered at (500, 500) inside a 1000×1000 level.
+func newTestCamera(lerp float64, deadzone IDim) WorldCamera {
+	return WorldCamera{
+		Pos:        Vec2{X: 500, Y: 500},
+		Size:       IDim{W: 100, H: 100},
+		LerpFactor: lerp,
+		Deadzone:   deadzone,
+		Bounds:     IRect{W: 1000, H: 1000},
+	}
+}
+
+func TestWorldCameraFollowsTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		lerp     float64
+		deadzone IDim
+		target   Vec2
+		want     Vec2
+	}{
+		{name: "snap", lerp: 1, target: Vec2{X: 600, Y: 450}, want: Vec2{X: 600, Y: 450}},
+		{name: "lerp", lerp: 0.5, target: Vec2{X: 600, Y: 400}, want: Vec2{X: 550, Y: 450}},
+		{name: "inside deadzone", lerp: 1, deadzone: IDim{W: 40, H: 40}, target: Vec2{X: 515, Y: 485},
+			want: Vec2{X: 500, Y: 500}},
+		{name: "past deadzone", lerp: 1, deadzone: IDim{W: 40, H: 40}, target: Vec2{X: 550, Y: 450},
+			want: Vec2{X: 530, Y: 470}},
+		{name: "clamped to bounds", lerp: 1, target: Vec2{X: 10, Y: 990}, want: Vec2{X: 50, Y: 950}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCamera(tt.lerp, tt.deadzone)
+			c.Target(tt.target)
+			c.Update()
+			if math.Abs(c.Pos.X-tt.want.X) > vecTolerance || math.Abs(c.Pos.Y-tt.want.Y) > vecTolerance {
+				t.Errorf("Pos = %v after targeting %v; want %v", c.Pos, tt.target, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorldCameraCentersSmallLevels(t *testing.T) {
+	c := newTestCamera(1, IDim{})
+	c.Bounds = IRect{X: 20, Y: 0, W: 60, H: 400}
+	c.Target(Vec2{X: 0, Y: 200})
+	c.Update()
+	if c.Pos.X != 50 {
+		t.Errorf("Pos.X = %v in a level narrower than the view; want 50, the level's center", c.Pos.X)
+	}
+}
+
+func TestWorldCameraScreenToWorld(t *testing.T) {
+	c := newTestCamera(1, IDim{})
+	if got, want := c.Offset(), (Vec2{X: -450, Y: -450}); got != want {
+		t.Errorf("Offset() = %v; want %v", got, want)
+	}
+	for _, screen := range []Vec2{{}, {X: 50, Y: 50}, {X: 99, Y: 12}} {
+		world := c.ScreenToWorld(screen)
+		if got := world.Add(c.Offset()); got != screen {
+			t.Errorf("ScreenToWorld(%v) = %v, which is drawn at %v; want %v", screen, world, got, screen)
+		}
+	}
+	if got, want := c.ScreenToWorld(Vec2{X: 50, Y: 50}), c.Pos; got != want {
+		t.Errorf("ScreenToWorld() of the screen's center = %v; want %v, the camera's position", got, want)
+	}
+}
+
+func TestWorldCameraShakesAccumulate(t *testing.T) {
+	shakes := []struct {
+		magnitude float64
+		duration  int
+		seed      int64
+	}{
+		{magnitude: 4, duration: 10, seed: 1},
+		{magnitude: 2, duration: 5, seed: 2},
+		{magnitude: 8, duration: 20, seed: 3},
+	}
+	combined := newTestCamera(1, IDim{})
+	alone := make([]WorldCamera, len(shakes))
+	for i, sh := range shakes {
+		combined.Shake(sh.magnitude, sh.duration, rand.New(rand.NewSource(sh.seed)))
+		alone[i] = newTestCamera(1, IDim{})
+		alone[i].Shake(sh.magnitude, sh.duration, rand.New(rand.NewSource(sh.seed)))
+	}
+	for frame := 0; frame < 25; frame++ {
+		combined.Update()
+		var want Vec2
+		for i := range alone {
+			alone[i].Update()
+			want = want.Add(alone[i].shakeOffset)
+		}
+		if got := combined.shakeOffset; math.Abs(got.X-want.X) > vecTolerance || math.Abs(got.Y-want.Y) > vecTolerance {
+			t.Fatalf("shake offset on frame %d = %v; want %v, the sum of each shake alone", frame, got, want)
+		}
+		for i, sh := range shakes {
+			off := alone[i].shakeOffset
+			if math.Abs(off.X) > sh.magnitude || math.Abs(off.Y) > sh.magnitude {
+				t.Errorf("shake %d offset on frame %d = %v; want it within ±%v", i, frame, off, sh.magnitude)
+			}
+		}
+	}
+	if combined.shakeOffset != (Vec2{}) || len(combined.shakes) != 0 {
+		t.Errorf("shake offset = %v with %d shakes left after every shake ended; want none", combined.shakeOffset,
+			len(combined.shakes))
+	}
+}
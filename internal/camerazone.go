@@ -0,0 +1,34 @@
+package internal
+
+// CameraZone restricts the camera to its bounds while the player is inside it.
+type CameraZone struct {
+	Bounds   IRect // Bounds is the region the camera is clamped to, in pixel coordinates.
+	Priority int   // Priority determines which zone is used when the player is inside more than one.
+}
+
+// NewCameraZone constructs a CameraZone from the bounds and "priority" field of the provided entity.
+func NewCameraZone(entity *Entity) *CameraZone {
+	return &CameraZone{Bounds: entity.PxBounds(), Priority: entity.FieldInt("priority")}
+}
+
+// updateCameraZone activates the highest-priority camera zone containing the provided point, if any. The point is the
+// center of the player's hitbox, or the midpoint between both players in co-op.
+func (s *PlatformerScene) updateCameraZone(center IVec2) {
+	s.activeCameraZone = nil
+	for _, zone := range s.cameraZones {
+		if !zone.Bounds.Contains(center) {
+			continue
+		}
+		if s.activeCameraZone == nil || zone.Priority > s.activeCameraZone.Priority {
+			s.activeCameraZone = zone
+		}
+	}
+}
+
+// cameraBounds returns the region the camera is currently clamped to; either the active camera zone or the level.
+func (s *PlatformerScene) cameraBounds() IRect {
+	if s.activeCameraZone != nil {
+		return s.activeCameraZone.Bounds
+	}
+	return IRect{W: s.level.PxDims.W, H: s.level.PxDims.H}
+}
@@ -0,0 +1,31 @@
+package internal
+
+import "testing"
+
+func TestCameraZoneTakesEffectOnEntry(t *testing.T) {
+	s := newTestScene(t, grid(40, 20)...)
+	p := newTestPlayer(t, s, IVec2{X: 32, Y: 32})
+	low := &Entity{ID: EtyCamera, PxCoords: IVec2{X: 320, Y: 0}, Dim: IDim{W: 320, H: 320}}
+	high := &Entity{ID: EtyCamera, PxCoords: IVec2{X: 480, Y: 0}, Dim: IDim{W: 160, H: 160},
+		Fields: map[string]any{"priority": 1.0}}
+	loadTestEntities(t, s, low, high)
+	level := IRect{W: s.level.PxDims.W, H: s.level.PxDims.H}
+
+	tests := []struct {
+		center IVec2
+		want   IRect
+	}{
+		{IVec2{X: 100, Y: 100}, level},
+		{IVec2{X: 400, Y: 100}, low.PxBounds()},
+		{IVec2{X: 500, Y: 100}, high.PxBounds()},
+		{IVec2{X: 500, Y: 200}, low.PxBounds()},
+		{IVec2{X: 100, Y: 100}, level},
+	}
+	for _, tt := range tests {
+		p.SetPos(p.Pos.Add(tt.center.Sub(p.Hitbox().Center())))
+		s.updateCamera()
+		if s.camera.Bounds != tt.want {
+			t.Errorf("camera bounds = %v with the player at %v; want %v", s.camera.Bounds, tt.center, tt.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const consoleLines = 8 // consoleLines is the number of lines of output shown above the console prompt.
+
+var consoleColor = color.RGBA{A: 0xc0}
+
+var (
+	errUsage    = errors.New("wrong arguments") // errUsage is returned by commands passed the wrong arguments.
+	errNoPlayer = errors.New("no player")       // errNoPlayer is returned by commands acting on a missing player.
+)
+
+// CommandContext is passed to every console command, giving it access to the running game.
+type CommandContext struct {
+	scene  *PlatformerScene
+	player *Player
+	bus    *EventBus
+}
+
+// ConsoleCommand is a command which can be run from the console. Run is called with the arguments following the
+// command's name and returns the text to print.
+type ConsoleCommand struct {
+	Usage string // Usage is printed when the command is passed the wrong arguments, e.g. "inspect <cx> <cy>".
+	Run   func(ctx CommandContext, args []string) (string, error)
+}
+
+// Console is a debug console which runs commands typed while it is open. It is toggled by the backtick key in debug
+// builds.
+type Console struct {
+	commands map[string]ConsoleCommand
+	open     bool
+	input    []rune
+	output   []string // output holds every line printed to the console, oldest first.
+}
+
+// NewConsole constructs a new Console with no commands registered.
+func NewConsole() *Console {
+	return &Console{commands: make(map[string]ConsoleCommand)}
+}
+
+// Register adds the provided command to the console under name, replacing any command already registered under it.
+func (c *Console) Register(name string, cmd ConsoleCommand) {
+	c.commands[name] = cmd
+}
+
+// Exec runs the provided line as a command, printing and returning its output. The first word of the line names the
+// command and the rest are passed to it as arguments.
+func (c *Console) Exec(ctx CommandContext, line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	var out string
+	cmd, ok := c.commands[fields[0]]
+	if !ok {
+		out = fmt.Sprintf("unknown command %q; known commands are %s", fields[0], strings.Join(c.names(), ", "))
+	} else if result, err := cmd.Run(ctx, fields[1:]); errors.Is(err, errUsage) {
+		out = "usage: " + cmd.Usage
+	} else if err != nil {
+		out = "error: " + err.Error()
+	} else {
+		out = result
+	}
+	c.output = append(c.output, "> "+line)
+	c.output = append(c.output, strings.Split(out, "\n")...)
+	return out
+}
+
+// names returns the names of every registered command in alphabetical order.
+func (c *Console) names() []string {
+	result := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Draw draws the console's prompt and most recent output along the bottom of the screen while it is open.
+func (c *Console) Draw(screen *ebiten.Image, font *BitmapFont) {
+	if !c.open {
+		return
+	}
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	top := h - (consoleLines+1)*font.GlyphH
+	vector.DrawFilledRect(screen, 0, float32(top), float32(w), float32(h-top), consoleColor, false)
+	lines := c.output[max(len(c.output)-consoleLines, 0):]
+	for i, line := range lines {
+		font.DrawText(screen, line, 0, top+i*font.GlyphH, nil)
+	}
+	font.DrawText(screen, "> "+string(c.input)+"_", 0, h-font.GlyphH, nil)
+}
+
+// updateConsole opens and closes the console with the backtick key and handles typing into it. It returns true while
+// the console is open, in which case the rest of the scene should not be updated. The console is only available in
+// debug builds.
+func (s *PlatformerScene) updateConsole() bool {
+	if !debugBuild {
+		return false
+	}
+	c := s.console
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		c.open = !c.open
+		c.input = c.input[:0]
+		return true
+	}
+	if !c.open {
+		return false
+	}
+	c.input = ebiten.AppendInputChars(c.input)
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(c.input) > 0 {
+		c.input = c.input[:len(c.input)-1]
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		c.Exec(s.commandContext(), string(c.input))
+		c.input = c.input[:0]
+	}
+	return true
+}
+
+// commandContext returns the context console commands are run with.
+func (s *PlatformerScene) commandContext() CommandContext {
+	return CommandContext{scene: s, player: s.player, bus: s.game.bus}
+}
+
+// registerConsoleCommands registers every console command which acts on the scene.
+func (s *PlatformerScene) registerConsoleCommands() {
+	s.console.Register("inspect", ConsoleCommand{Usage: "inspect <cx> <cy>", Run: inspectCommand})
+	s.console.Register("give", ConsoleCommand{Usage: "give <item_id> [count]", Run: giveCommand})
+	s.console.Register("set", ConsoleCommand{Usage: "set hp <value> | set pos <x> <y>", Run: setCommand})
+	s.console.Register("list", ConsoleCommand{Usage: "list entities", Run: listCommand})
+}
+
+// inspectCommand describes the cell at the provided cell coordinates.
+func inspectCommand(ctx CommandContext, args []string) (string, error) {
+	if len(args) != 2 {
+		return "", errUsage
+	}
+	cx, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", errUsage
+	}
+	cy, err := strconv.Atoi(args[1])
+	if err != nil {
+		return "", errUsage
+	}
+	dat := ctx.scene.gridDataI(cx, cy)
+	return fmt.Sprintf("(%d, %d): 0x%x %s", cx, cy, uint32(dat), dat.Describe()), nil
+}
+
+// giveCommand adds count of the provided item to the player's inventory. count defaults to 1.
+func giveCommand(ctx CommandContext, args []string) (string, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "", errUsage
+	}
+	count := 1
+	if len(args) == 2 {
+		var err error
+		if count, err = strconv.Atoi(args[1]); err != nil {
+			return "", errUsage
+		}
+	}
+	if ctx.player == nil {
+		return "", errNoPlayer
+	}
+	item := args[0]
+	ctx.player.inventory[item] += count
+	return fmt.Sprintf("gave %d %s; player has %d", count, item, ctx.player.inventory[item]), nil
+}
+
+// setCommand sets the player's HP or moves the player to the provided pixel coordinates.
+func setCommand(ctx CommandContext, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", errUsage
+	}
+	values := make([]int, len(args)-1)
+	for i, arg := range args[1:] {
+		var err error
+		if values[i], err = strconv.Atoi(arg); err != nil {
+			return "", errUsage
+		}
+	}
+	if ctx.player == nil {
+		return "", errNoPlayer
+	}
+	p := ctx.player
+	switch {
+	case args[0] == "hp" && len(values) == 1 && values[0] >= 0:
+		p.HP = values[0]
+		return fmt.Sprintf("hp set to %d", p.HP), nil
+	case args[0] == "pos" && len(values) == 2:
+		p.Pos = IVec2{X: values[0], Y: values[1]}
+		p.resetSubpixels()
+		return fmt.Sprintf("moved to (%d, %d)", p.Pos.X, p.Pos.Y), nil
+	}
+	return "", errUsage
+}
+
+// listCommand prints the ID and IID of every entity in the current level which has not been destroyed.
+func listCommand(ctx CommandContext, args []string) (string, error) {
+	if len(args) != 1 || args[0] != "entities" {
+		return "", errUsage
+	}
+	s := ctx.scene
+	var lines []string
+	for _, e := range s.level.Entities {
+		if !s.destroyedEntities[e.IID] {
+			lines = append(lines, fmt.Sprintf("%s %s", e.ID, e.IID))
+		}
+	}
+	if len(lines) == 0 {
+		return "no entities", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
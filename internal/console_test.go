@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestIntGridDataDescribe(t *testing.T) {
+	tests := []struct {
+		dat  IntGridData
+		want string
+	}{
+		{IntGridNothing, "None"},
+		{IntGridDirt, "Dirt | Solid"},
+		{IntGridLadder, "Ladder"},
+		{IntGridStone, "Stone | Solid"},
+		{IntGridMagnet, "Magnet | Solid"},
+		{IntGridBreakable, "Breakable | Solid"},
+		{IntGridIce, "Ice | Solid"},
+		{IntGridBounce, "Bounce | Solid"},
+		{IntGridBorder, "Border | Solid"},
+		{IntGridLadderTop, "Ladder | LadderTop | OneWay"},
+		{IntGridLadderBottom, "Ladder | LadderBottom"},
+		{IntGridOneWay, "OneWay"},
+		{IntGridDirt | IntGridOneWay, "Dirt | Solid | OneWay"},
+	}
+	for _, tt := range tests {
+		if got := tt.dat.Describe(); got != tt.want {
+			t.Errorf("IntGridData(0x%x).Describe() = %q; want %q", uint32(tt.dat), got, tt.want)
+		}
+	}
+}
+
+func TestCollideMaskDescribe(t *testing.T) {
+	tests := []struct {
+		mask CollideMask
+		want string
+	}{
+		{CollideNone, "None"},
+		{CollideStone | CollideLadder, "Ladder | Stone | Solid"},
+		{CollideLadderTop | CollideIce, "Ladder | Ice | Solid | LadderTop | OneWay"},
+		{CollideLadderBot | CollideLadderTop, "Ladder | LadderTop | LadderBottom | OneWay"},
+	}
+	for _, tt := range tests {
+		if got := tt.mask.Describe(); got != tt.want {
+			t.Errorf("CollideMask(0x%x).Describe() = %q; want %q", uint32(tt.mask), got, tt.want)
+		}
+	}
+}
+
+func TestConsoleInspect(t *testing.T) {
+	s := newTestScene(t,
+		"....",
+		".H..",
+		"SSSS",
+	)
+	ctx := s.commandContext()
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"inspect 0 0", "(0, 0): 0x0 None"},
+		{"inspect 1 1", "(1, 1): 0x40000002 Ladder | LadderBottom"},
+		{"inspect 2 2", "(2, 2): 0x3 Stone | Solid"},
+		{"  inspect   2 2 ", "(2, 2): 0x3 Stone | Solid"},
+		{"inspect 2", "usage: inspect <cx> <cy>"},
+		{"inspect x 2", "usage: inspect <cx> <cy>"},
+		{"inspekt 2 2", `unknown command "inspekt"; known commands are give, inspect, list, set`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := s.console.Exec(ctx, tt.line); got != tt.want {
+			t.Errorf("Exec(%q) = %q; want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleGive(t *testing.T) {
+	s := newTestScene(t, grid(4, 3)...)
+	p := newTestPlayer(t, s, IVec2{})
+	tests := []struct {
+		line      string
+		want      string
+		wantCoins int
+	}{
+		{"give coin 5", "gave 5 coin; player has 5", 5},
+		{"give coin", "gave 1 coin; player has 6", 6},
+		{"give coin -2", "gave -2 coin; player has 4", 4},
+		{"give coin x", "usage: give <item_id> [count]", 4},
+		{"give", "usage: give <item_id> [count]", 4},
+	}
+	for _, tt := range tests {
+		if got := s.console.Exec(s.commandContext(), tt.line); got != tt.want {
+			t.Errorf("Exec(%q) = %q; want %q", tt.line, got, tt.want)
+		}
+		if got := p.inventory["coin"]; got != tt.wantCoins {
+			t.Errorf("after Exec(%q), inventory[\"coin\"] = %d; want %d", tt.line, got, tt.wantCoins)
+		}
+	}
+}
+
+func TestConsoleSet(t *testing.T) {
+	s := newTestScene(t, grid(4, 3)...)
+	p := newTestPlayer(t, s, IVec2{})
+	tests := []struct {
+		line    string
+		want    string
+		wantHP  int
+		wantPos IVec2
+	}{
+		{"set hp 1", "hp set to 1", 1, IVec2{}},
+		{"set pos 24 -8", "moved to (24, -8)", 1, IVec2{X: 24, Y: -8}},
+		{"set hp -1", "usage: set hp <value> | set pos <x> <y>", 1, IVec2{X: 24, Y: -8}},
+		{"set pos 3", "usage: set hp <value> | set pos <x> <y>", 1, IVec2{X: 24, Y: -8}},
+		{"set speed 3", "usage: set hp <value> | set pos <x> <y>", 1, IVec2{X: 24, Y: -8}},
+	}
+	for _, tt := range tests {
+		if got := s.console.Exec(s.commandContext(), tt.line); got != tt.want {
+			t.Errorf("Exec(%q) = %q; want %q", tt.line, got, tt.want)
+		}
+		if p.HP != tt.wantHP || p.Pos != tt.wantPos {
+			t.Errorf("after Exec(%q), player has %d HP at %v; want %d HP at %v", tt.line, p.HP, p.Pos, tt.wantHP,
+				tt.wantPos)
+		}
+	}
+}
+
+func TestConsoleListEntities(t *testing.T) {
+	s := newTestScene(t, grid(4, 3)...)
+	if got, want := s.console.Exec(s.commandContext(), "list entities"), "no entities"; got != want {
+		t.Errorf("Exec(%q) in an empty level = %q; want %q", "list entities", got, want)
+	}
+	coin, key := &Entity{ID: EtyCoin, IID: uuid.New()}, &Entity{ID: EtyKey, IID: uuid.New()}
+	loadTestEntities(t, s, coin, key)
+	s.destroyedEntities[key.IID] = true
+	want := fmt.Sprintf("%s %s", EtyCoin, coin.IID)
+	if got := s.console.Exec(s.commandContext(), "list entities"); got != want {
+		t.Errorf("Exec(%q) = %q; want %q, skipping the destroyed key", "list entities", got, want)
+	}
+}
+
+func TestConsoleNoPlayer(t *testing.T) {
+	s := newTestScene(t, grid(4, 3)...)
+	for _, line := range []string{"give coin", "set hp 2"} {
+		if got, want := s.console.Exec(s.commandContext(), line), "error: no player"; got != want {
+			t.Errorf("Exec(%q) without a player = %q; want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package internal
+
+// Cooldown counts down the frames remaining until an action may be used again.
+type Cooldown struct {
+	current int // current is the number of frames remaining until the cooldown is ready.
+	max     int // max is the number of frames the cooldown lasts once started.
+}
+
+// NewCooldown constructs a ready Cooldown which lasts the provided number of frames once started.
+func NewCooldown(frames int) Cooldown {
+	return Cooldown{max: frames}
+}
+
+// Tick advances the cooldown by one frame.
+func (c *Cooldown) Tick() {
+	if c.current > 0 {
+		c.current--
+	}
+}
+
+// Ready returns true once the cooldown has elapsed.
+func (c *Cooldown) Ready() bool {
+	return c.current == 0
+}
+
+// Start restarts the cooldown from its full length, even if it is already running.
+func (c *Cooldown) Start() {
+	c.current = c.max
+}
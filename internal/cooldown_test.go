@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestCooldown(t *testing.T) {
+	c := NewCooldown(3)
+	if !c.Ready() {
+		t.Errorf("new cooldown Ready() = false; want true")
+	}
+	c.Start()
+	if c.Ready() {
+		t.Errorf("Ready() after Start() = true; want false")
+	}
+	for i := 1; i <= 3; i++ {
+		c.Tick()
+		if got, want := c.Ready(), i == 3; got != want {
+			t.Errorf("Ready() after %d ticks = %v; want %v", i, got, want)
+		}
+	}
+	c.Tick()
+	if !c.Ready() || c.current != 0 {
+		t.Errorf("ticking a ready cooldown left %d frames; want it to stay ready at 0", c.current)
+	}
+}
+
+func TestCooldownRestart(t *testing.T) {
+	c := NewCooldown(4)
+	c.Start()
+	c.Tick()
+	c.Tick()
+	c.Start()
+	for i := 1; i <= 4; i++ {
+		c.Tick()
+		if got, want := c.Ready(), i == 4; got != want {
+			t.Errorf("Ready() %d ticks after restarting = %v; want %v", i, got, want)
+		}
+	}
+}
+
+func TestCooldownZeroLength(t *testing.T) {
+	c := NewCooldown(0)
+	c.Start()
+	if !c.Ready() {
+		t.Errorf("Ready() after starting a zero-length cooldown = false; want true")
+	}
+}
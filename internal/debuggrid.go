@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const debugGridTextScale = 0.5 // debugGridTextScale is the scale at which cell coordinates are drawn on the debug grid.
+
+var debugGridColor = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x80}
+
+// loadDebugGrid allocates the lines used to draw the debug grid across the whole screen.
+func (s *PlatformerScene) loadDebugGrid() {
+	w, h := s.camera.Size.W, s.camera.Size.H
+	if s.gridLineV == nil || s.gridLineV.Bounds().Dy() != h {
+		s.gridLineV = ebiten.NewImage(1, h)
+		s.gridLineV.Fill(debugGridColor)
+	}
+	if s.gridLineH == nil || s.gridLineH.Bounds().Dx() != w {
+		s.gridLineH = ebiten.NewImage(w, 1)
+		s.gridLineH.Fill(debugGridColor)
+	}
+}
+
+// drawDebugGrid draws a line along every cell boundary visible on screen. The coordinates of each cell are drawn in its
+// upper-left corner whenever they fit inside the cell.
+func (s *PlatformerScene) drawDebugGrid(screen *ebiten.Image) {
+	s.loadDebugGrid() // the screen changes size as the camera zooms.
+	if s.gridLineV == nil || s.gridLineH == nil || s.cellSize <= 0 {
+		return
+	}
+	size := s.cellSize
+	offset, view := s.camera.Offset().IVec2(), s.camera.Size
+	offsetX, offsetY := posMod(offset.X, size), posMod(offset.Y, size)
+	opts := ebiten.DrawImageOptions{}
+	for x := offsetX; x < view.W; x += size {
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(float64(x), 0)
+		screen.DrawImage(s.gridLineV, &opts)
+	}
+	for y := offsetY; y < view.H; y += size {
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(0, float64(y))
+		screen.DrawImage(s.gridLineH, &opts)
+	}
+	for x := offsetX; x < view.W; x += size {
+		for y := offsetY; y < view.H; y += size {
+			cx, cy := (x-offset.X)/size, (y-offset.Y)/size
+			label := fmt.Sprintf("%d,%d", cx, cy)
+			if w, _ := s.game.font.MeasureText(label); float64(w)*debugGridTextScale > float64(size) {
+				continue // the cell is too small to label.
+			}
+			opts.GeoM.Reset()
+			opts.GeoM.Scale(debugGridTextScale, debugGridTextScale)
+			opts.GeoM.Translate(float64(x+1), float64(y+1))
+			s.game.font.DrawText(screen, label, 0, 0, &opts)
+		}
+	}
+}
+
+// posMod returns a mod m, in the range [0, m).
+func posMod(a, m int) int {
+	return ((a % m) + m) % m
+}
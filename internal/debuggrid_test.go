@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"testing"
+)
+
+// newTestGridScene returns a scene whose camera shows 50×30 cells, the size of a typical debug session.
+func newTestGridScene(t testing.TB) (*PlatformerScene, *ebiten.Image) {
+	t.Helper()
+	s := newTestScene(t, grid(60, 40)...)
+	s.camera.Size = IDim{W: 50 * testCellSize, H: 30 * testCellSize}
+	return s, ebiten.NewImage(s.camera.Size.W, s.camera.Size.H)
+}
+
+func TestDebugGridCachesLines(t *testing.T) {
+	s, screen := newTestGridScene(t)
+	s.drawDebugGrid(screen)
+	lineV, lineH := s.gridLineV, s.gridLineH
+	if got := lineV.Bounds().Dy(); got != s.camera.Size.H {
+		t.Errorf("vertical grid line is %d pixels tall; want %d", got, s.camera.Size.H)
+	}
+	if got := lineH.Bounds().Dx(); got != s.camera.Size.W {
+		t.Errorf("horizontal grid line is %d pixels wide; want %d", got, s.camera.Size.W)
+	}
+	s.drawDebugGrid(screen)
+	if s.gridLineV != lineV || s.gridLineH != lineH {
+		t.Errorf("grid lines were reallocated by a second draw at the same size; want them cached")
+	}
+	if got := color.RGBAModel.Convert(screen.At(testCellSize, testCellSize/2)); got == (color.RGBA{}) {
+		t.Errorf("screen is clear on a cell boundary; want a grid line drawn there")
+	}
+}
+
+// BenchmarkDrawDebugGrid should stay well under 2ms per op on a developer machine.
+func BenchmarkDrawDebugGrid(b *testing.B) {
+	s, screen := newTestGridScene(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.drawDebugGrid(screen)
+	}
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"image/color"
+	"strings"
+)
+
+const (
+	DialogueCharsPerLine = 40 // DialogueCharsPerLine is the maximum number of characters shown on each line of dialogue.
+	DialogueLinesPerPage = 3  // DialogueLinesPerPage is the number of lines of dialogue shown at once.
+	dialogueLineHeight   = 16
+	dialogueMargin       = 8
+)
+
+var dialogueBoxColor = color.RGBA{R: 0x10, G: 0x10, B: 0x20, A: 0xe0}
+
+// DialogueScene draws a box of text over the scene beneath it. The player advances through the text by pressing jump;
+// once the final page has been confirmed the scene pops itself.
+type DialogueScene struct {
+	*BaseScene
+	lines     []string
+	page      int
+	drawBelow func(*ebiten.Image) // drawBelow draws the paused scene beneath the dialogue box.
+	box       *ebiten.Image
+
+	keys      []ebiten.Key
+	lastInput PlayerInput
+}
+
+// NewDialogueScene constructs a new DialogueScene displaying the provided text. drawBelow is used to draw the scene
+// the dialogue is shown over; it may be nil.
+func NewDialogueScene(g *Game, text string, drawBelow func(*ebiten.Image)) *DialogueScene {
+	result := &DialogueScene{
+		BaseScene: NewBaseScene(g),
+		lines:     wrapText(text, DialogueCharsPerLine),
+		drawBelow: drawBelow,
+	}
+	result.lastInput = result.handleInput() // ignore a jump that was already held when the dialogue began.
+	return result
+}
+
+// Update advances the dialogue whenever confirm is pressed.
+func (s *DialogueScene) Update() error {
+	input := s.handleInput()
+	confirmed := input&InputJumped > 0 && s.lastInput&InputJumped == 0
+	s.lastInput = input
+	if !confirmed {
+		return nil
+	}
+	s.page++
+	if s.page*DialogueLinesPerPage >= len(s.lines) {
+		s.game.PopScene()
+	}
+	return nil
+}
+
+// Draw draws the current page of dialogue at the bottom of the screen.
+func (s *DialogueScene) Draw(screen *ebiten.Image) {
+	if s.drawBelow != nil {
+		s.drawBelow(screen)
+	}
+	w, h := s.Layout(0, 0)
+	boxH := DialogueLinesPerPage*dialogueLineHeight + dialogueMargin
+	if s.box == nil {
+		s.box = ebiten.NewImage(w-2*dialogueMargin, boxH)
+		s.box.Fill(dialogueBoxColor)
+	}
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(dialogueMargin, float64(h-boxH-dialogueMargin))
+	screen.DrawImage(s.box, &opts)
+
+	start := s.page * DialogueLinesPerPage
+	end := min(start+DialogueLinesPerPage, len(s.lines))
+	for i, line := range s.lines[start:end] {
+		s.game.font.DrawText(screen, line, 2*dialogueMargin, h-boxH-dialogueMargin/2+i*dialogueLineHeight, nil)
+	}
+}
+
+func (s *DialogueScene) handleInput() PlayerInput {
+	s.keys = inpututil.AppendPressedKeys(s.keys[:0])
+	return inputFromKeys(s.keys)
+}
+
+// wrapText splits the provided text into lines of at most charsPerLine characters, breaking only on spaces. Words
+// longer than charsPerLine are placed on a line of their own.
+func wrapText(text string, charsPerLine int) []string {
+	var (
+		result []string
+		line   strings.Builder
+	)
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && line.Len()+1+len(word) > charsPerLine {
+			result = append(result, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		result = append(result, line.String())
+	}
+	return result
+}
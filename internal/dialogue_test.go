@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapText80(t *testing.T) {
+	word := strings.Repeat("a", 9) // with a space, eight words exactly fill an 80-character line.
+	eight := strings.TrimSpace(strings.Repeat(word+" ", 8))
+	long := strings.Repeat("b", 100)
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "short", text: "hello there", want: []string{"hello there"}},
+		{name: "exactly 80", text: eight[:79] + "a", want: []string{eight[:79] + "a"}},
+		{name: "one past 80", text: eight + " " + word, want: []string{eight, word}},
+		{name: "long word", text: "a " + long + " b", want: []string{"a", long, "b"}},
+		{name: "extra spaces", text: "  a   b  ", want: []string{"a b"}},
+	}
+	for _, tt := range tests {
+		got := wrapText(tt.text, 80)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: wrapText(%q, 80) = %q; want %q", tt.name, tt.text, got, tt.want)
+		}
+		for _, line := range got {
+			if len(line) > 80 && !strings.Contains(tt.name, "long") {
+				t.Errorf("%s: line %q is longer than 80 characters", tt.name, line)
+			}
+		}
+	}
+}
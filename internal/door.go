@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"math"
+)
+
+const (
+	DoorSpeed       = 1.0 // DoorSpeed is how many pixels a sliding door moves each frame.
+	DoorOpenStagger = 10  // DoorOpenStagger is the number of frames between each door opened by the same switch.
+)
+
+// doorTile is a tile drawn as part of a sliding door, along with the layer it belongs to.
+type doorTile struct {
+	layer *TileLayer
+	tile  Tile
+}
+
+// SliderDoor is a solid block which slides by its open offset when activated by a switch sharing its trigger ID.
+type SliderDoor struct {
+	entity     *Entity
+	TriggerID  string
+	basePos    IVec2 // basePos is the position of the closed door in pixel coordinates.
+	openOffset IVec2 // openOffset is the displacement from basePos to the open position in pixel coordinates.
+	current    Vec2  // current is the door's current position in pixel coordinates.
+	target     Vec2  // target is the position the door is currently moving toward in pixel coordinates.
+	tiles      []doorTile
+	moving     bool // moving is set while the door pushes actors, so that the door does not block them.
+}
+
+// NewSliderDoor constructs a closed SliderDoor from the provided entity. The "open_offset" field is a point, measured
+// in cells, giving the displacement from the closed position to the open position.
+func NewSliderDoor(entity *Entity, cellSize int) *SliderDoor {
+	offset, _ := entity.FieldPoint("open_offset")
+	return &SliderDoor{
+		entity:     entity,
+		TriggerID:  entity.FieldString("trigger_id"),
+		basePos:    entity.PxCoords,
+		openOffset: offset.Scale(cellSize),
+		current:    entity.PxCoords.Vec2(),
+		target:     entity.PxCoords.Vec2(),
+	}
+}
+
+// Open starts sliding this door toward its open position.
+func (d *SliderDoor) Open() {
+	d.target = d.basePos.Add(d.openOffset).Vec2()
+}
+
+// OpenNow moves this door to its open position immediately.
+func (d *SliderDoor) OpenNow() {
+	d.Open()
+	d.current = d.target
+}
+
+// Pos returns the current position of this door, rounded to the nearest pixel.
+func (d *SliderDoor) Pos() IVec2 {
+	return IVec2{X: int(math.Round(d.current.X)), Y: int(math.Round(d.current.Y))}
+}
+
+// Bounds returns the current bounds of this door in pixel coordinates.
+func (d *SliderDoor) Bounds() IRect {
+	pos := d.Pos()
+	return IRect{X: pos.X, Y: pos.Y, W: d.entity.Dim.W, H: d.entity.Dim.H}
+}
+
+// closedBounds returns the bounds of this door in its closed position, in pixel coordinates.
+func (d *SliderDoor) closedBounds() IRect {
+	return d.entity.PxBounds()
+}
+
+// step moves this door toward its target by up to DoorSpeed pixels, returning the distance moved in whole pixels.
+func (d *SliderDoor) step() IVec2 {
+	before := d.Pos()
+	remaining := d.target.Sub(d.current)
+	if dist := remaining.Mag(); dist <= DoorSpeed {
+		d.current = d.target
+	} else {
+		d.current = d.current.Lerp(d.target, DoorSpeed/dist)
+	}
+	return d.Pos().Sub(before)
+}
+
+// loadDoorTiles takes ownership of any tiles lying inside the closed bounds of each door, so that they move along with
+// it.
+func (s *PlatformerScene) loadDoorTiles() {
+	for _, door := range s.doors {
+		for _, layer := range s.level.layers {
+			if layer.TileSetUID == nil {
+				continue
+			}
+			for _, tile := range layer.Tiles {
+				if door.closedBounds().Contains(tile.PxCoords) {
+					door.tiles = append(door.tiles, doorTile{layer: layer, tile: tile})
+				}
+			}
+		}
+	}
+}
+
+// isDoorTile returns true if the provided tile is drawn as part of a door rather than as part of the level.
+func (s *PlatformerScene) isDoorTile(tile Tile) bool {
+	for _, door := range s.doors {
+		if door.closedBounds().Contains(tile.PxCoords) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateDoors slides any moving doors, pushing each player along if they are standing on or blocked by a door.
+func (s *PlatformerScene) updateDoors() {
+	players := s.players()
+	riding := make([]bool, len(players))
+	for _, door := range s.doors {
+		before := door.Bounds()
+		for i, p := range players {
+			hitbox := p.Hitbox()
+			riding[i] = !hitbox.Overlaps(before) && hitbox.Add(IVec2{Y: 1}).Overlaps(before)
+		}
+
+		delta := door.step()
+		if delta == (IVec2{}) {
+			continue
+		}
+		after := door.Bounds()
+		door.moving = true
+		for i, p := range players {
+			hitbox := p.Hitbox()
+			if !riding[i] && !hitbox.Overlaps(after) {
+				continue
+			}
+			dx, _ := p.Actor.MoveX(hitbox, float64(delta.X), ClipNone)
+			p.Pos.X += dx
+			dy, _ := p.Actor.MoveY(p.Hitbox(), float64(delta.Y), ClipNone)
+			p.Pos.Y += dy
+		}
+		door.moving = false
+		s.markDoorDirty(before, after)
+	}
+}
+
+// markDoorDirty marks the background dirty in the region covered by a door which moved from before to after.
+func (s *PlatformerScene) markDoorDirty(before, after IRect) {
+	minX, minY := min(before.X, after.X), min(before.Y, after.Y)
+	maxX, maxY := max(before.X+before.W, after.X+after.W), max(before.Y+before.H, after.Y+after.H)
+	s.MarkDirty(IRect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY})
+}
+
+// drawDoorTiles draws the tiles of any door overlapping the provided region at the door's current position.
+func (s *PlatformerScene) drawDoorTiles(region IRect, opts *ebiten.DrawImageOptions) {
+	for _, door := range s.doors {
+		if !region.Overlaps(door.Bounds()) {
+			continue
+		}
+		offset := door.Pos().Sub(door.basePos)
+		for _, dt := range door.tiles {
+			tileset, ok := s.gdat.Tilesets[*dt.layer.TileSetUID]
+			if !ok {
+				continue
+			}
+			tile := dt.tile
+			tile.PxCoords = tile.PxCoords.Add(offset)
+			s.drawTile(tileset, dt.layer, tile, opts)
+		}
+	}
+}
+
+// doorCollides returns CollideStone if the provided hitbox overlaps any door which is not currently pushing actors.
+func (s *PlatformerScene) doorCollides(hitbox IRect) CollideMask {
+	for _, door := range s.doors {
+		if !door.moving && hitbox.Overlaps(door.Bounds()) {
+			return CollideStone
+		}
+	}
+	return 0
+}
+
+// updateSwitches opens the doors matching the trigger ID of any switch either player has just started touching. Doors
+// sharing a trigger ID open one after another, DoorOpenStagger frames apart.
+func (s *PlatformerScene) updateSwitches() {
+	for _, sw := range s.switches {
+		wasTouching := s.touching[sw.IID]
+		s.touching[sw.IID] = s.playerTouching(sw.PxBounds()) != nil
+		if wasTouching || !s.touching[sw.IID] {
+			continue
+		}
+		s.activeSwitches[sw.IID] = true
+		var steps []Step
+		for _, door := range s.doors {
+			if door.TriggerID == sw.FieldString("trigger_id") {
+				door := door
+				steps = append(steps, func() int {
+					door.Open()
+					return DoorOpenStagger
+				})
+			}
+		}
+		s.RunSequence(NewSequence(steps...))
+	}
+}
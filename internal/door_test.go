@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSliderDoorOpens(t *testing.T) {
+	door := NewSliderDoor(&Entity{
+		ID:       EtySliderDoor,
+		PxCoords: IVec2{X: 32, Y: 48},
+		Dim:      IDim{W: 16, H: 32},
+		Fields:   map[string]any{"open_offset": map[string]any{"cx": 0.0, "cy": -2.0}},
+	}, testCellSize)
+	door.Open()
+	open := IVec2{X: 32, Y: 16}
+	openFrames := int(math.Ceil(2 * testCellSize / DoorSpeed))
+	for i := 0; i < openFrames; i++ {
+		door.step()
+	}
+	if d := door.Pos().Sub(open); abs(d.X) > 1 || abs(d.Y) > 1 {
+		t.Errorf("door.Pos() = %v after %d frames; want within 1 pixel of %v", door.Pos(), openFrames, open)
+	}
+	if delta := door.step(); delta != (IVec2{}) {
+		t.Errorf("door.step() = %v once open; want %v", delta, IVec2{})
+	}
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	DropTableKey = "drops"   // DropTableKey is the key in tile custom data holding a drop table, e.g. "drops=coin:70,key:30".
+	DropNothing  = "nothing" // DropNothing is the item ID rolled when nothing should drop.
+)
+
+// DropEntry is a single item in a DropTable, chosen with probability proportional to its weight.
+type DropEntry struct {
+	itemID string
+	weight int
+}
+
+// DropTable chooses which item, if any, is dropped when something is destroyed.
+type DropTable struct {
+	entries []DropEntry
+	total   int // total is the sum of the weights of all entries.
+}
+
+// ParseDropTable parses a drop table from comma-separated "item:weight" pairs, e.g. "coin:70,key:20,nothing:10".
+func ParseDropTable(s string) (*DropTable, error) {
+	result := &DropTable{}
+	for _, pair := range strings.Split(s, ",") {
+		item, weightStr, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid drop table entry %q: expected item:weight", pair)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in drop table entry %q", pair)
+		}
+		result.entries = append(result.entries, DropEntry{itemID: strings.TrimSpace(item), weight: weight})
+		result.total += weight
+	}
+	if result.total == 0 {
+		return nil, fmt.Errorf("drop table %q has no weight", s)
+	}
+	return result, nil
+}
+
+// Roll returns the ID of a randomly chosen item, with each item chosen in proportion to its weight.
+func (dt *DropTable) Roll(rng *rand.Rand) string {
+	n := rng.Intn(dt.total)
+	for _, entry := range dt.entries {
+		if n < entry.weight {
+			return entry.itemID
+		}
+		n -= entry.weight
+	}
+	return DropNothing // unreachable while total is the sum of the weights.
+}
+
+// dropEntityID returns the entity ID of the collectible named by the provided item ID, ignoring case. ok is false for
+// DropNothing and unknown items.
+func dropEntityID(itemID string) (id EntityID, ok bool) {
+	for _, id := range []EntityID{EtyCoin, EtyKey, EtyPowerUpDoubleJump} {
+		if strings.EqualFold(itemID, id) {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseDropTable(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantTotal int
+		wantErr   bool
+	}{
+		{in: "coin:70,key:20,nothing:10", wantTotal: 100},
+		{in: " coin : 3 , key:1", wantTotal: 4},
+		{in: "coin", wantErr: true},
+		{in: "coin:x", wantErr: true},
+		{in: "coin:-1", wantErr: true},
+		{in: "coin:0,key:0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			dt, err := ParseDropTable(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDropTable(%q) error = %v; want error %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && dt.total != tt.wantTotal {
+				t.Errorf("ParseDropTable(%q).total = %d; want %d", tt.in, dt.total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestDropTableRoll(t *testing.T) {
+	dt, err := ParseDropTable("coin:70,key:20,nothing:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const rolls = 1000
+	counts := make(map[string]int)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < rolls; i++ {
+		counts[dt.Roll(rng)]++
+	}
+	want := map[string]float64{"coin": 0.7, "key": 0.2, DropNothing: 0.1}
+	for item, share := range want {
+		got := float64(counts[item]) / rolls
+		if got < share-0.05 || got > share+0.05 {
+			t.Errorf("%s rolled %.1f%% of the time; want about %.0f%%", item, 100*got, 100*share)
+		}
+	}
+	if len(counts) != len(want) {
+		t.Errorf("Roll() returned items %v; want only %v", counts, want)
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log/slog"
+)
+
+const EnemyContactDamage = 1 // EnemyContactDamage is the damage dealt to the player when touching an enemy.
+
+// Enemy is any hostile actor in a PlatformerScene.
+type Enemy interface {
+	Mover
+	// TakeDamage reduces the enemy's health by the provided amount, returning true if the enemy died.
+	TakeDamage(amount int) bool
+	// Dead returns true once the enemy has run out of health and should be removed.
+	Dead() bool
+}
+
+// EntityFactory constructs an Enemy from an LDtk entity.
+type EntityFactory func(*PlatformerScene, *Entity) (Enemy, error)
+
+// enemyFactories maps each built-in enemy type to its factory. Every scene starts with these registered.
+var enemyFactories = map[EntityID]EntityFactory{
+	EtyPatrolEnemy:  NewPatrolEnemy,
+	EtyJumpEnemy:    NewJumpEnemy,
+	EtyShooterEnemy: NewShooterEnemy,
+	EtyBossEnemy:    NewBossEnemy,
+	EtySwarmEnemy:   NewSwarmEnemy,
+}
+
+// registerEnemyFactories registers a factory for each built-in enemy type.
+func (s *PlatformerScene) registerEnemyFactories() {
+	for id, factory := range enemyFactories {
+		s.RegisterEnemy(id, factory)
+	}
+}
+
+// RegisterEnemy registers a factory used to construct enemies from entities with the provided ID.
+func (s *PlatformerScene) RegisterEnemy(id EntityID, factory EntityFactory) {
+	if s.entityFactory == nil {
+		s.entityFactory = make(map[string]EntityFactory)
+	}
+	s.entityFactory[id] = factory
+}
+
+// spawnEnemy constructs an enemy from the provided entity, returning false if no factory is registered for it.
+func (s *PlatformerScene) spawnEnemy(entity *Entity) (bool, error) {
+	factory, ok := s.entityFactory[entity.ID]
+	if !ok {
+		return false, nil
+	}
+	enemy, err := factory(s, entity)
+	if err != nil {
+		return true, err
+	}
+	s.enemies = append(s.enemies, enemy)
+	s.enemyIIDs[enemy] = entity.IID
+	s.AddMover(enemy)
+	return true, nil
+}
+
+// updateEnemies damages any player on contact with an enemy and removes any enemies which have died. Enemies are
+// updated along with every other Mover.
+func (s *PlatformerScene) updateEnemies() {
+	remaining := s.enemies[:0]
+	for _, enemy := range s.enemies {
+		if enemy.Dead() {
+			slog.Debug("enemy died", "hitbox", enemy.Hitbox())
+			if iid, ok := s.enemyIIDs[enemy]; ok {
+				s.destroyedEntities[iid] = true
+			}
+			s.RemoveMover(enemy)
+			continue
+		}
+		box := enemy.Hitbox()
+		for _, p := range s.players() {
+			if hitbox := p.Hitbox(); box.Overlaps(hitbox) {
+				p.TakeDamage(EnemyContactDamage, sign(box.Center().X-hitbox.Center().X))
+			}
+		}
+		remaining = append(remaining, enemy)
+	}
+	s.enemies = remaining
+}
+
+// enemyBody holds the state shared by all enemies which move through the level under gravity.
+type enemyBody struct {
+	Actor
+	Pos  IVec2 // Pos is the top-left corner of the enemy's hitbox in pixel coordinates.
+	Vel  Vec2
+	Dim  IDim
+	HP   int
+	subX float64
+	subY float64
+}
+
+// newEnemyBody constructs an enemyBody with the position and dimensions of the provided entity.
+func newEnemyBody(s *PlatformerScene, entity *Entity, hp int) enemyBody {
+	return enemyBody{Actor: Actor{scene: s}, Pos: entity.PxCoords, Dim: entity.Dim, HP: hp}
+}
+
+// Hitbox returns the enemy's hitbox in pixel coordinates.
+func (e *enemyBody) Hitbox() IRect {
+	return IRect{X: e.Pos.X, Y: e.Pos.Y, W: e.Dim.W, H: e.Dim.H}
+}
+
+// TakeDamage reduces the enemy's health by the provided amount, returning true if the enemy died.
+func (e *enemyBody) TakeDamage(amount int) bool {
+	e.HP = max(e.HP-amount, 0)
+	return e.HP == 0
+}
+
+// Dead returns true once the enemy has run out of health.
+func (e *enemyBody) Dead() bool {
+	return e.HP <= 0
+}
+
+// onSolidGround returns true if the enemy is standing on a solid cell.
+func (e *enemyBody) onSolidGround() bool {
+	return e.Collides(e.Hitbox().Add(IVec2{Y: 1})).Colliding(ClipNone)
+}
+
+// move applies gravity and moves the enemy by its velocity, returning the cells collided with along each axis.
+func (e *enemyBody) move() (collidesX, collidesY CollideMask) {
+	e.Vel.Y = min(e.Vel.Y+Gravity/TPS, PlayerTerminalVelocity)
+
+	var whole float64
+	whole, e.subY = splitSubpixel(e.Vel.Y + e.subY)
+	dy, collidesY := e.MoveY(e.Hitbox(), whole, ClipNone)
+	e.Pos.Y += dy
+	if collidesY.Colliding(ClipNone) {
+		e.Vel.Y, e.subY = 0, 0
+	}
+	whole, e.subX = splitSubpixel(e.Vel.X + e.subX)
+	dx, collidesX := e.MoveX(e.Hitbox(), whole, ClipNone)
+	e.Pos.X += dx
+	return collidesX, collidesY
+}
+
+// drawRect draws the enemy's hitbox as a placeholder rectangle, offset by the provided camera position.
+func (e *enemyBody) drawRect(screen *ebiten.Image, camera IVec2, c color.Color) {
+	box := e.Hitbox().Add(camera)
+	vector.DrawFilledRect(screen, float32(box.X), float32(box.Y), float32(box.W), float32(box.H), c, false)
+}
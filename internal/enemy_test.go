@@ -0,0 +1,46 @@
+package internal
+
+import "testing"
+
+func TestRegisterEnemy(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	var spawned []*Entity
+	s.RegisterEnemy("TestEnemy", func(s *PlatformerScene, entity *Entity) (Enemy, error) {
+		spawned = append(spawned, entity)
+		return NewPatrolEnemy(s, entity)
+	})
+	entity := &Entity{ID: "TestEnemy", PxCoords: IVec2{X: 64, Y: 96}, Dim: IDim{W: 16, H: 16}}
+	loadTestEntities(t, s, entity)
+
+	if len(spawned) != 1 || spawned[0] != entity {
+		t.Fatalf("factory was called with %v; want exactly %v", spawned, entity)
+	}
+	if len(s.enemies) != 1 {
+		t.Fatalf("scene has %d enemies; want 1", len(s.enemies))
+	}
+	if got := s.enemies[0].Hitbox(); got != entity.PxBounds() {
+		t.Errorf("enemy hitbox = %v; want %v", got, entity.PxBounds())
+	}
+	if s.enemyIIDs[s.enemies[0]] != entity.IID {
+		t.Errorf("enemy IID = %v; want %v", s.enemyIIDs[s.enemies[0]], entity.IID)
+	}
+}
+
+func TestUpdateEnemiesRemovesDead(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	entity := &Entity{ID: EtyPatrolEnemy, PxCoords: IVec2{X: 64, Y: 96}, Dim: IDim{W: 16, H: 16}}
+	loadTestEntities(t, s, entity)
+	if len(s.enemies) != 1 {
+		t.Fatalf("scene has %d enemies; want 1", len(s.enemies))
+	}
+	if !s.enemies[0].TakeDamage(1000) {
+		t.Fatalf("enemy survived 1000 damage")
+	}
+	s.updateEnemies()
+	if len(s.enemies) != 0 {
+		t.Errorf("scene has %d enemies after one died; want 0", len(s.enemies))
+	}
+	if !s.destroyedEntities[entity.IID] {
+		t.Errorf("dead enemy %v was not recorded as destroyed", entity.IID)
+	}
+}
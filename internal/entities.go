@@ -5,4 +5,94 @@ type EntityID = string
 
 const (
 	EtyPlayer EntityID = "Player"
+	// EtyPlayer2 marks where the second player spawns in co-op. Levels without one spawn both players together.
+	EtyPlayer2 EntityID = "PlayerSpawn2"
+	EtyCoin    EntityID = "Coin" // EtyCoin is a collectible coin.
+	EtyKey     EntityID = "Key"  // EtyKey is a collectible key.
+	// EtyPowerUpDoubleJump grants the player a second air jump when collected.
+	EtyPowerUpDoubleJump EntityID = "PowerUpDoubleJump"
+	EtyExit              EntityID = "Exit" // EtyExit ends the current level; its "level" field names the level to load next.
+	// EtyDialogue shows its "text" field when touched by the player. If "trigger_once" is set, it is shown only once.
+	EtyDialogue EntityID = "Dialogue"
+	// EtySliderDoor slides by its "open_offset" field when a switch with a matching "trigger_id" is activated.
+	EtySliderDoor EntityID = "SliderDoor"
+	// EtySwitch opens all doors sharing its "trigger_id" field when touched by the player. Switches with their "magnet"
+	// field set power the level's magnets while pressed.
+	EtySwitch EntityID = "Switch"
+	// EtyWind accelerates actors inside it by its "force_x" and "force_y" fields, in pixels per second^2.
+	EtyWind EntityID = "WindZone"
+	// EtyWater ripples the screen while the player is inside it. Its "amplitude" and "frequency" fields shape the
+	// ripples.
+	EtyWater EntityID = "WaterZone"
+	// EtyDarkness darkens the level it is placed in, leaving only lit areas visible. Its "alpha" field sets how dark.
+	EtyDarkness EntityID = "Darkness"
+	// EtyTorch lights the area within its "radius" field in dark levels.
+	EtyTorch EntityID = "Torch"
+	// EtyLight is a static light in dark levels, with "radius", "color", and "intensity" fields.
+	EtyLight EntityID = "Light"
+	// EtyCamera restricts the camera to its bounds while the player is inside it. Overlapping zones are resolved by
+	// their "priority" field.
+	EtyCamera EntityID = "CameraZone"
+	// EtyPatrolEnemy walks between its "patrol_left_x" and "patrol_right_x" fields.
+	EtyPatrolEnemy EntityID = "PatrolEnemy"
+	// EtyJumpEnemy patrols like EtyPatrolEnemy, leaping at the player when within its "detect_range" field.
+	EtyJumpEnemy EntityID = "JumpEnemy"
+	// EtyShooterEnemy fires at the player every "shoot_interval" frames while they are level with it.
+	EtyShooterEnemy EntityID = "ShooterEnemy"
+	// EtyBossEnemy is a boss with its max health given by its "hp" field.
+	EtyBossEnemy EntityID = "BossEnemy"
+	// EtySwarmEnemy is a flock of flying boids which chase the player. Its "count" field sets the number of boids.
+	EtySwarmEnemy EntityID = "SwarmEnemy"
+	// EtyZipLine carries the player from its "start" point field to its "end" point field once grabbed.
+	EtyZipLine EntityID = "ZipLine"
+	// EtyRopeAnchor is a point the player can swing from on a rope.
+	EtyRopeAnchor EntityID = "RopeAnchor"
+	// EtyEscalator carries the player standing inside it upward at its "speed_y" field, in pixels per second. Negative
+	// speeds carry the player downward.
+	EtyEscalator EntityID = "Escalator"
+	// EtyCrate is a movable crate. Crates with their "magnetic" field set are pulled toward powered magnets.
+	EtyCrate EntityID = "Crate"
+	// EtyTeleporter warps the player to the teleporter whose IID is given by its "partner_iid" field.
+	EtyTeleporter EntityID = "Teleporter"
 )
+
+// PxBounds returns the bounds of this entity in pixel coordinates.
+func (e *Entity) PxBounds() IRect {
+	return IRect{X: e.PxCoords.X, Y: e.PxCoords.Y, W: e.Dim.W, H: e.Dim.H}
+}
+
+// FieldString retrieves the value of a String field on this entity, or the empty string if no such field was set.
+func (e *Entity) FieldString(id string) string {
+	v, _ := e.Fields[id].(string)
+	return v
+}
+
+// FieldInt retrieves the value of an Int field on this entity, or zero if no such field was set.
+func (e *Entity) FieldInt(id string) int {
+	v, _ := e.Fields[id].(float64) // JSON numbers are always decoded as float64.
+	return int(v)
+}
+
+// FieldFloat retrieves the value of a Float field on this entity, or zero if no such field was set.
+func (e *Entity) FieldFloat(id string) float64 {
+	v, _ := e.Fields[id].(float64)
+	return v
+}
+
+// FieldBool retrieves the value of a Bool field on this entity, or false if no such field was set.
+func (e *Entity) FieldBool(id string) bool {
+	v, _ := e.Fields[id].(bool)
+	return v
+}
+
+// FieldPoint retrieves the value of a Point field on this entity in cell coordinates. ok is false if no such field was
+// set.
+func (e *Entity) FieldPoint(id string) (pt IVec2, ok bool) {
+	v, ok := e.Fields[id].(map[string]any)
+	if !ok {
+		return IVec2{}, false
+	}
+	cx, _ := v["cx"].(float64) // JSON numbers are always decoded as float64.
+	cy, _ := v["cy"].(float64)
+	return IVec2{X: int(cx), Y: int(cy)}, true
+}
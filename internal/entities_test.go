@@ -0,0 +1,41 @@
+package internal
+
+import "testing"
+
+func TestEntityPxBounds(t *testing.T) {
+	e := &Entity{PxCoords: IVec2{X: 10, Y: 20}, Dim: IDim{W: 16, H: 24}}
+	if got, want := e.PxBounds(), (IRect{X: 10, Y: 20, W: 16, H: 24}); got != want {
+		t.Errorf("PxBounds() = %v; want %v", got, want)
+	}
+}
+
+func TestEntityFields(t *testing.T) {
+	e := &Entity{Fields: map[string]any{
+		"name":  "door",
+		"count": float64(3),
+		"speed": 1.5,
+		"once":  true,
+		"point": map[string]any{"cx": float64(4), "cy": float64(7)},
+	}}
+	if got := e.FieldString("name"); got != "door" {
+		t.Errorf("FieldString() = %q; want %q", got, "door")
+	}
+	if got := e.FieldInt("count"); got != 3 {
+		t.Errorf("FieldInt() = %d; want 3", got)
+	}
+	if got := e.FieldFloat("speed"); got != 1.5 {
+		t.Errorf("FieldFloat() = %v; want 1.5", got)
+	}
+	if !e.FieldBool("once") {
+		t.Error("FieldBool() = false; want true")
+	}
+	if pt, ok := e.FieldPoint("point"); !ok || pt != (IVec2{X: 4, Y: 7}) {
+		t.Errorf("FieldPoint() = %v, %v; want {4 7}, true", pt, ok)
+	}
+	if got := e.FieldString("missing"); got != "" {
+		t.Errorf("FieldString() of a missing field = %q; want empty", got)
+	}
+	if _, ok := e.FieldPoint("name"); ok {
+		t.Error("FieldPoint() of a string field returned ok")
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+)
+
+// LdtkEnum is the value of an enum defined in LDtk.
+type LdtkEnum = string
+
+// EntityTypeEnum is the name of the LDtk enum listing every entity type the game is expected to handle.
+const EntityTypeEnum = "EntityType"
+
+// LoadEnums extracts the values of all enums defined in LDtk, keyed by the name of each enum.
+func LoadEnums(json *ldtk.LdtkJSON) map[string][]LdtkEnum {
+	result := make(map[string][]LdtkEnum, len(json.Defs.Enums))
+	for _, enum := range json.Defs.Enums {
+		values := make([]LdtkEnum, 0, len(enum.Values))
+		for _, value := range enum.Values {
+			values = append(values, value.ID)
+		}
+		result[enum.Identifier] = values
+	}
+	return result
+}
+
+// EnumValues returns the values of the named LDtk enum, or nil if no such enum was defined.
+func (g *GameData) EnumValues(enumName string) []LdtkEnum {
+	return g.Enums[enumName]
+}
+
+// isEnumValue returns true if value is one of the values of the named LDtk enum. If the enum is not defined, every value
+// is accepted.
+func (g *GameData) isEnumValue(enumName string, value LdtkEnum) bool {
+	values, ok := g.Enums[enumName]
+	if !ok {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+const escalatorArrowPeriod = 30 // escalatorArrowPeriod is the number of frames it takes each arrow to cycle once.
+
+var escalatorArrowColor = color.RGBA{R: 0xf0, G: 0xd0, B: 0x40, A: 0xc0}
+
+// Escalator carries actors standing inside its bounds up or down without them needing to climb.
+type Escalator struct {
+	Bounds IRect
+	SpeedY float64 // SpeedY is the speed in pixels per second actors are carried upward; negative values carry them down.
+}
+
+// NewEscalator constructs an Escalator from the "speed_y" field of the provided entity.
+func NewEscalator(entity *Entity) Escalator {
+	return Escalator{Bounds: entity.PxBounds(), SpeedY: entity.FieldFloat("speed_y")}
+}
+
+// escalatorAt returns the escalator containing the provided point, if any.
+func (s *PlatformerScene) escalatorAt(pt IVec2) (Escalator, bool) {
+	for _, esc := range s.escalators {
+		b := esc.Bounds
+		if pt.X >= b.X && pt.X < b.X+b.W && pt.Y >= b.Y && pt.Y < b.Y+b.H {
+			return esc, true
+		}
+	}
+	return Escalator{}, false
+}
+
+// updateEscalators sets the vertical velocity of each player standing on an escalator to its speed, so they are carried
+// when they next move. Players who are jumping or falling are not carried.
+func (s *PlatformerScene) updateEscalators() {
+	for _, p := range s.players() {
+		switch p.state() {
+		case PlayerStateIdle, PlayerStateWalking, PlayerStateRunning:
+		default:
+			continue
+		}
+		esc, ok := s.escalatorAt(p.foot())
+		if !ok {
+			continue
+		}
+		p.Vel.Y = -esc.SpeedY / TPS
+	}
+}
+
+// drawEscalators draws arrows in each cell of every escalator showing the direction it carries actors.
+func (s *PlatformerScene) drawEscalators(screen *ebiten.Image) {
+	size := s.cellSize
+	offset := s.camera.Offset().IVec2()
+	for _, esc := range s.escalators {
+		if esc.SpeedY == 0 {
+			continue
+		}
+		dir := 1 // arrows point down the screen for negative speeds.
+		if esc.SpeedY > 0 {
+			dir = -1
+		}
+		drift := dir * (s.frame % escalatorArrowPeriod) * size / (2 * escalatorArrowPeriod)
+		for x := esc.Bounds.X; x < esc.Bounds.X+esc.Bounds.W; x += size {
+			for y := esc.Bounds.Y; y < esc.Bounds.Y+esc.Bounds.H; y += size {
+				cx := float32(x + size/2 + offset.X)
+				tip := float32(y + size/2 + dir*size/4 + drift + offset.Y)
+				tail := tip - float32(dir*size/4)
+				vector.StrokeLine(screen, cx-float32(size)/4, tail, cx, tip, 1, escalatorArrowColor, false)
+				vector.StrokeLine(screen, cx+float32(size)/4, tail, cx, tip, 1, escalatorArrowColor, false)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestEscalatorCarriesIdlePlayer(t *testing.T) {
+	tests := []struct {
+		name   string
+		speedY float64
+	}{
+		{"down", -60},
+		{"up", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScene(t, grid(8, 12)...)
+			p := newTestPlayer(t, s, IVec2{X: 3 * testCellSize, Y: 2 * testCellSize})
+			setFeet(p, IVec2{X: 3*testCellSize + testCellSize/2, Y: 6 * testCellSize})
+			s.escalators = []Escalator{{Bounds: IRect{X: 2 * testCellSize, W: 3 * testCellSize, H: 11 * testCellSize},
+				SpeedY: tt.speedY}}
+			p.states.Set(PlayerStateIdle)
+
+			start := p.Pos.Y
+			for i := 0; i < int(TPS); i++ {
+				s.updateEscalators()
+				updatePlayer(p, InputNone)
+				if p.state() != PlayerStateIdle {
+					t.Fatalf("player is in state %v after %d frames; want them to stay idle", p.state(), i+1)
+				}
+			}
+			if got, want := p.Pos.Y-start, int(-tt.speedY); got != want {
+				t.Errorf("player moved %d pixels down after %d frames; want %d", got, int(TPS), want)
+			}
+		})
+	}
+}
+
+func TestStartIdlingOnEscalatorResetsSubpixels(t *testing.T) {
+	s := newTestScene(t, grid(8, 12)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 3*testCellSize + testCellSize/2, Y: 6 * testCellSize})
+	s.escalators = []Escalator{{Bounds: IRect{X: 2 * testCellSize, W: 3 * testCellSize, H: 11 * testCellSize},
+		SpeedY: 30}}
+	if !p.onEscalator() {
+		t.Fatalf("player with feet at %v is not on the escalator", p.foot())
+	}
+	p.subX, p.subY = 0.9, -0.9
+	p.states.Set(p.startIdling())
+	if p.subX != 0 || p.subY != 0 {
+		t.Errorf("subpixels after idling on an escalator = (%v, %v); want (0, 0)", p.subX, p.subY)
+	}
+}
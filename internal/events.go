@@ -0,0 +1,42 @@
+package internal
+
+// Topics published on the EventBus.
+const (
+	TopicPlayerDamaged   = "player.damaged"   // TopicPlayerDamaged is published with the player's remaining HP.
+	TopicPlayerRespawned = "player.respawned" // TopicPlayerRespawned is published with the player's HP after respawning.
+	TopicCoinCollected   = "coin.collected"   // TopicCoinCollected is published with the collected coin's *Entity.
+	TopicKeyCollected    = "key.collected"    // TopicKeyCollected is published with the collected key's *Entity.
+	// TopicBossHealthChanged is published with a BossHealth when a boss becomes active or takes damage.
+	TopicBossHealthChanged = "boss.health"
+	// TopicBossDefeated is published with nil when a boss dies or its level is unloaded.
+	TopicBossDefeated = "boss.defeated"
+	// TopicTileDestroyed is published with the IVec2 coordinates of a breakable cell when it is destroyed.
+	TopicTileDestroyed = "tile.destroyed"
+	// TopicPlayerLanded is published with the distance in pixels the player fell each time they land on the ground.
+	TopicPlayerLanded = "player.landed"
+)
+
+// EventHandler handles the data published with an event.
+type EventHandler func(data any)
+
+// EventBus dispatches events to subscribers by topic. Events are dispatched synchronously on the calling goroutine.
+type EventBus struct {
+	handlers map[string][]EventHandler
+}
+
+// NewEventBus constructs a new EventBus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// Subscribe registers the provided handler to be called whenever an event is published to topic.
+func (b *EventBus) Subscribe(topic string, handler EventHandler) {
+	b.handlers[topic] = append(b.handlers[topic], handler)
+}
+
+// Publish calls all handlers subscribed to topic with the provided data, in the order they were subscribed.
+func (b *EventBus) Publish(topic string, data any) {
+	for _, handler := range b.handlers[topic] {
+		handler(data)
+	}
+}
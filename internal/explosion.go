@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+const (
+	ExplosionFrames     = 15    // ExplosionFrames is the number of frames each explosion lasts for.
+	ExplosionShakeScale = 0.125 // ExplosionShakeScale is the camera shake magnitude per pixel of explosion radius.
+)
+
+// Explosion damages and pushes away any actors within Radius of its Center.
+type Explosion struct {
+	Center    Vec2
+	Radius    float64
+	Damage    int
+	Force     float64
+	FrameLife int // FrameLife is the number of frames remaining before the explosion is removed.
+
+	hitPlayers []*Player // hitPlayers holds the players this explosion has already damaged.
+	light      *Light    // light lights up the area around the explosion until it expires.
+}
+
+// hit returns true if this explosion has already damaged the provided player.
+func (e *Explosion) hit(player *Player) bool {
+	for _, p := range e.hitPlayers {
+		if p == player {
+			return true
+		}
+	}
+	return false
+}
+
+// impulse returns the velocity added to an actor at pos by this explosion. The impulse points away from the center and
+// has magnitude Force / distance. Actors outside the radius receive no impulse.
+func (e *Explosion) impulse(pos Vec2) Vec2 {
+	offset := pos.Sub(e.Center)
+	dist := offset.Mag()
+	if dist > e.Radius {
+		return Vec2{}
+	}
+	dist = max(dist, 1) // avoid unbounded impulses at the center.
+	if offset.Mag() == 0 {
+		offset = Vec2{Y: -1} // push actors at the exact center upwards.
+	}
+	return offset.Normalize().Scale(e.Force / dist)
+}
+
+// ExplosionSystem manages all active explosions in a scene.
+type ExplosionSystem struct {
+	scene      *PlatformerScene
+	explosions []Explosion
+}
+
+// NewExplosionSystem constructs an empty ExplosionSystem for the provided scene.
+func NewExplosionSystem(scene *PlatformerScene) *ExplosionSystem {
+	return &ExplosionSystem{scene: scene}
+}
+
+// Spawn creates a new explosion at center and shakes the camera in proportion to its radius.
+func (es *ExplosionSystem) Spawn(center Vec2, radius, force float64, damage int) {
+	es.explosions = append(es.explosions, Explosion{
+		Center:    center,
+		Radius:    radius,
+		Damage:    damage,
+		Force:     force,
+		FrameLife: ExplosionFrames,
+		light: es.scene.lighting.Add(Light{
+			Pos:       center,
+			Radius:    radius * ExplosionLightScale,
+			Color:     explosionColor,
+			Intensity: 1,
+		}),
+	})
+	es.scene.breakCellsInRadius(center, radius)
+	es.scene.camera.Shake(radius*ExplosionShakeScale, ExplosionFrames, es.scene.rng.Rand)
+}
+
+// Update damages and pushes any actors caught in an explosion and removes expired explosions. Each actor is only hit
+// once by any given explosion.
+func (es *ExplosionSystem) Update() {
+	remaining := es.explosions[:0]
+	for _, e := range es.explosions {
+		e.FrameLife--
+		if e.FrameLife <= 0 {
+			es.scene.lighting.Remove(e.light)
+			continue
+		}
+		e.light.Intensity = float64(e.FrameLife) / ExplosionFrames
+		for _, player := range es.scene.players() {
+			if e.hit(player) {
+				continue
+			}
+			if center := player.center(); center.Sub(e.Center).Mag() <= e.Radius {
+				e.hitPlayers = append(e.hitPlayers, player)
+				player.TakeDamage(e.Damage, sign(int(e.Center.X-center.X)))
+				player.Vel = player.Vel.Add(e.impulse(center))
+			}
+		}
+		remaining = append(remaining, e)
+	}
+	es.explosions = remaining
+}
+
+// Draw draws each explosion as a circle expanding to its full radius, offset by the provided camera position.
+func (es *ExplosionSystem) Draw(screen *ebiten.Image, camera IVec2) {
+	for _, e := range es.explosions {
+		t := 1 - float64(e.FrameLife)/ExplosionFrames
+		center := e.Center.Add(camera.Vec2())
+		vector.StrokeCircle(screen, float32(center.X), float32(center.Y), float32(e.Radius*t), 2,
+			color.RGBA{R: 0xff, G: 0xa0, B: 0x20, A: 0xff}, false)
+	}
+}
+
+// Clear removes all explosions.
+func (es *ExplosionSystem) Clear() {
+	es.explosions = es.explosions[:0]
+}
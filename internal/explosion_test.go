@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExplosionImpulse(t *testing.T) {
+	e := Explosion{Center: Vec2{X: 100, Y: 100}, Radius: 40, Force: 200}
+	half := e.impulse(Vec2{X: 120, Y: 100})
+	full := e.impulse(Vec2{X: 100, Y: 140})
+	if math.Abs(half.Mag()-2*full.Mag()) > vecTolerance {
+		t.Errorf("impulse at half radius = %v; want twice the impulse at full radius %v", half, full)
+	}
+	if half.X <= 0 || full.Y <= 0 {
+		t.Errorf("impulses %v and %v do not point away from the center", half, full)
+	}
+	if out := e.impulse(Vec2{X: 141, Y: 100}); out != (Vec2{}) {
+		t.Errorf("impulse outside radius = %v; want %v", out, Vec2{})
+	}
+}
+
+func TestExplosionHitsPlayerOnce(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	p := newTestPlayer(t, s, IVec2{X: 64, Y: 64})
+	hp := p.HP
+	s.explosions.Spawn(p.center().Add(Vec2{X: -10}), 40, 100, 1)
+	for i := 0; i < ExplosionFrames; i++ {
+		s.explosions.Update()
+	}
+	if p.HP != hp-1 {
+		t.Errorf("player HP = %d after explosion; want %d", p.HP, hp-1)
+	}
+	if len(s.explosions.explosions) != 0 {
+		t.Errorf("%d explosions remain after %d frames; want 0", len(s.explosions.explosions), ExplosionFrames)
+	}
+}
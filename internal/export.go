@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+	"io"
+)
+
+// ExportIntGrid returns the current IntGrid of the collision layer as the raw values used in LDtk, laid out as
+// idx = x + y*cellsWide. Flags set at runtime, such as ladder tops, are dropped.
+func (s *PlatformerScene) ExportIntGrid() []int {
+	values := make(map[CollideMask]int, len(s.gdat.IntGridMasks))
+	for value, mask := range s.gdat.IntGridMasks {
+		values[mask] = value
+	}
+	result := make([]int, len(s.intGridData))
+	for i, d := range s.intGridData {
+		d &= 0x3fffffff // unset flags.
+		if value, ok := values[d.CollideMask()]; ok {
+			result[i] = value
+		} else {
+			result[i] = int(d)
+		}
+	}
+	return result
+}
+
+// PatchLevelIntGrid replaces the IntGrid of the collision layer of the level with the provided UID, both in the loaded
+// level and in the LDtk project written by WriteLdtk. The grid must have one value per cell of the layer.
+func (gd *GameData) PatchLevelIntGrid(levelUID UID, grid []int) error {
+	level, ok := gd.Levels[levelUID]
+	if !ok {
+		return fmt.Errorf("no level found with id: %d", levelUID)
+	}
+	layer, ok := level.layersByID[CollisionLayerID]
+	if !ok || len(layer.Grid) != len(grid) {
+		return fmt.Errorf("level %s: grid does not match layer '%s'", level.ID, CollisionLayerID)
+	}
+	instance := gd.collisionInstance(levelUID)
+	if instance == nil {
+		return fmt.Errorf("level %s: could not find layer instance '%s'", level.ID, CollisionLayerID)
+	}
+	copy(layer.Grid, grid)
+	instance.IntGridCSV = make([]int64, len(grid))
+	for i, v := range grid {
+		instance.IntGridCSV[i] = int64(v)
+	}
+	return nil
+}
+
+// collisionInstance returns the collision layer instance of the level with the provided UID in the LDtk project, or
+// nil if it could not be found.
+func (gd *GameData) collisionInstance(levelUID UID) *ldtk.LayerInstance {
+	for _, project := range append([]*ldtk.LdtkJSON{gd.json}, gd.merged...) {
+		for i := range project.Levels {
+			lvl := &project.Levels[i]
+			if lvl.Uid != levelUID {
+				continue
+			}
+			for j := range lvl.LayerInstances {
+				if lvl.LayerInstances[j].Identifier == CollisionLayerID {
+					return &lvl.LayerInstances[j]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteLdtk writes the LDtk project this GameData was loaded from, including any patches, to the provided writer. If
+// several files were merged, only the first is written.
+func (gd *GameData) WriteLdtk(w io.Writer) error {
+	return ldtk.MarshalLdtkWriter(w, gd.json)
+}
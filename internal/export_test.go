@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"bytes"
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+	"slices"
+	"testing"
+)
+
+func TestExportIntGridRoundTrip(t *testing.T) {
+	gdat, err := LoadGameData()
+	if err != nil {
+		t.Fatalf("could not load game data: %v", err)
+	}
+	s := NewPlatformerScene(newTestGame(t), &gdat)
+	uid := gdat.LevelStart
+	if err := s.LoadLevel(uid); err != nil {
+		t.Fatalf("could not load level %d: %v", uid, err)
+	}
+	original := s.ExportIntGrid()
+	if !slices.Equal(original, s.level.layersByID[CollisionLayerID].Grid) {
+		t.Fatalf("ExportIntGrid() of an unmodified level does not match the level's IntGrid")
+	}
+
+	cell := slices.IndexFunc(s.intGridData, func(d IntGridData) bool { return d.isSolid() })
+	if cell < 0 {
+		t.Fatalf("level %d has no solid cells", uid)
+	}
+	s.setGridDataI(cell%s.cellsWide, cell/s.cellsWide, IntGridNothing)
+	modified := s.ExportIntGrid()
+	if modified[cell] != 0 {
+		t.Errorf("ExportIntGrid()[%d] = %d after emptying the cell; want 0", cell, modified[cell])
+	}
+
+	if err := gdat.PatchLevelIntGrid(uid, modified); err != nil {
+		t.Fatalf("PatchLevelIntGrid() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := gdat.WriteLdtk(&buf); err != nil {
+		t.Fatalf("WriteLdtk() error = %v", err)
+	}
+	json, err := ldtk.UnmarshalLdtkJSON(buf.Bytes())
+	if err != nil {
+		t.Fatalf("could not unmarshal written LDtk JSON: %v", err)
+	}
+	levels, err := LoadLevels(&json)
+	if err != nil {
+		t.Fatalf("could not load written levels: %v", err)
+	}
+	if got := levels[uid].layersByID[CollisionLayerID].Grid; !slices.Equal(got, modified) {
+		t.Errorf("written IntGrid differs from the exported grid at %d cells", countDiffs(got, modified))
+	}
+}
+
+func TestPatchLevelIntGridErrors(t *testing.T) {
+	gdat, err := LoadGameData()
+	if err != nil {
+		t.Fatalf("could not load game data: %v", err)
+	}
+	if err := gdat.PatchLevelIntGrid(-1, nil); err == nil {
+		t.Errorf("PatchLevelIntGrid() of an unknown level succeeded; want an error")
+	}
+	if err := gdat.PatchLevelIntGrid(gdat.LevelStart, []int{1}); err == nil {
+		t.Errorf("PatchLevelIntGrid() with a grid of the wrong size succeeded; want an error")
+	}
+}
+
+// countDiffs returns the number of indices at which a and b differ, counting any difference in length.
+func countDiffs(a, b []int) int {
+	result := max(len(a), len(b)) - min(len(a), len(b))
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if a[i] != b[i] {
+			result++
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"strings"
+)
+
+const (
+	fontFirstChar = 32  // fontFirstChar is the first ASCII character found in a BitmapFont's sprite sheet.
+	fontLastChar  = 126 // fontLastChar is the last ASCII character found in a BitmapFont's sprite sheet.
+)
+
+// defaultFontPath is the path to the default font's sprite sheet, relative to the gamedata embed folder.
+const defaultFontPath = "fonts/basic7x13.png"
+
+// BitmapFont draws monospaced text using glyphs from a sprite sheet. Glyphs for ASCII characters 32 through 126 are laid
+// out in order, left-to-right and top-to-bottom, with CharsPerRow glyphs in each row.
+type BitmapFont struct {
+	sheet       *ebiten.Image
+	GlyphW      int // GlyphW is the width of each glyph in pixels.
+	GlyphH      int // GlyphH is the height of each glyph in pixels.
+	CharsPerRow int // CharsPerRow is the number of glyphs in each row of the sprite sheet.
+}
+
+// LoadBitmapFont loads a BitmapFont from the sprite sheet found at the provided path, relative to the gamedata embed
+// folder.
+func LoadBitmapFont(path string, glyphW, glyphH, charsPerRow int) (*BitmapFont, error) {
+	img, err := loadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BitmapFont{
+		sheet:       ebiten.NewImageFromImage(img),
+		GlyphW:      glyphW,
+		GlyphH:      glyphH,
+		CharsPerRow: charsPerRow,
+	}, nil
+}
+
+// LoadDefaultFont loads the font used for all in-game text.
+func LoadDefaultFont() (*BitmapFont, error) {
+	return LoadBitmapFont(defaultFontPath, 7, 13, 16)
+}
+
+// DrawText draws the provided text to screen with its upper-left corner at (x, y). Newlines start a new line of text.
+// Characters which are not supported are drawn as '?'. If opts is non-nil, its GeoM is applied after positioning the
+// text and its ColorScale is used to tint each glyph.
+func (f *BitmapFont) DrawText(screen *ebiten.Image, text string, x, y int, opts *ebiten.DrawImageOptions) {
+	glyphOpts := ebiten.DrawImageOptions{}
+	cx, cy := x, y
+	for _, r := range text {
+		if r == '\n' {
+			cx, cy = x, cy+f.GlyphH
+			continue
+		}
+		glyphOpts.GeoM.Reset()
+		glyphOpts.GeoM.Translate(float64(cx), float64(cy))
+		if opts != nil {
+			glyphOpts.GeoM.Concat(opts.GeoM)
+			glyphOpts.ColorScale = opts.ColorScale
+		}
+		screen.DrawImage(f.glyph(r), &glyphOpts)
+		cx += f.GlyphW
+	}
+}
+
+// MeasureText returns the width and height in pixels of the provided text when drawn using DrawText.
+func (f *BitmapFont) MeasureText(text string) (w, h int) {
+	if text == "" {
+		return 0, 0
+	}
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		w = max(w, len([]rune(line))*f.GlyphW)
+	}
+	return w, len(lines) * f.GlyphH
+}
+
+// glyph returns the sub-image of the sprite sheet containing the glyph for the provided rune.
+func (f *BitmapFont) glyph(r rune) *ebiten.Image {
+	if r < fontFirstChar || r > fontLastChar {
+		r = '?'
+	}
+	idx := int(r - fontFirstChar)
+	x, y := (idx%f.CharsPerRow)*f.GlyphW, (idx/f.CharsPerRow)*f.GlyphH
+	return f.sheet.SubImage(image.Rect(x, y, x+f.GlyphW, y+f.GlyphH)).(*ebiten.Image) // safe; guaranteed per docs.
+}
@@ -0,0 +1,24 @@
+package internal
+
+import "testing"
+
+func TestMeasureText(t *testing.T) {
+	f, err := LoadDefaultFont()
+	if err != nil {
+		t.Fatalf("could not load font: %v", err)
+	}
+	tests := []struct {
+		text string
+		w, h int
+	}{
+		{text: "", w: 0, h: 0},
+		{text: "AB", w: 2 * f.GlyphW, h: f.GlyphH},
+		{text: "ABC\nD", w: 3 * f.GlyphW, h: 2 * f.GlyphH},
+		{text: "é", w: f.GlyphW, h: f.GlyphH},
+	}
+	for _, tt := range tests {
+		if w, h := f.MeasureText(tt.text); w != tt.w || h != tt.h {
+			t.Errorf("MeasureText(%q) = %d, %d; want %d, %d", tt.text, w, h, tt.w, tt.h)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"strings"
+)
+
+// TileSound returns the name of the sound made by stepping on the provided cell, read from the "sound" key in the custom
+// data of the top-most tile covering the cell. The empty string is returned if no tile sets a sound. Footsteps are not
+// played yet, since there are no sound effects under gamedata/sfx to play.
+func (s *PlatformerScene) TileSound(cell IVec2) string {
+	px := cell.Scale(s.cellSize)
+	for i := len(s.level.layers) - 1; i >= 0; i-- {
+		layer := s.level.layers[i]
+		if layer.TileSetUID == nil {
+			continue
+		}
+		for _, tile := range layer.Tiles {
+			if !(IRect{X: tile.PxCoords.X, Y: tile.PxCoords.Y, W: layer.GridSize, H: layer.GridSize}).Contains(px) {
+				continue
+			}
+			if sound := tileDataValue(s.gdat.TileCustomData(*layer.TileSetUID, tile.TileID), "sound"); sound != "" {
+				return sound
+			}
+		}
+	}
+	return ""
+}
+
+// tileDataValue returns the value of the provided key in tile custom data of the form "key=value", with one pair per
+// line or separated by semicolons. The empty string is returned if the key is not present.
+func tileDataValue(data, key string) string {
+	for _, pair := range strings.FieldsFunc(data, func(r rune) bool { return r == '\n' || r == ';' }) {
+		k, v, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package internal
+
+import "testing"
+
+func TestTileSound(t *testing.T) {
+	s := newTestScene(t,
+		"....",
+		"....",
+		"SS##",
+	)
+	layer := addTestTileset(s)
+	layer.Tiles = []Tile{testTile(0, 2, 2), testTile(1, 2, 2), testTile(2, 2, 0), testTile(3, 2, 1)}
+	s.gdat.TilesetMeta = map[UID]TilesetMetadata{
+		testTilesetUID: {CustomData: map[int]string{0: "sound=grass", 1: "damage=1", 2: "hazard=0;sound=stone"}},
+	}
+	tests := []struct {
+		cell IVec2
+		want string
+	}{
+		{IVec2{X: 0, Y: 2}, "stone"},
+		{IVec2{X: 1, Y: 2}, "stone"},
+		{IVec2{X: 2, Y: 2}, "grass"},
+		{IVec2{X: 3, Y: 2}, ""},
+		{IVec2{X: 0, Y: 1}, ""},
+	}
+	for _, tt := range tests {
+		if got := s.TileSound(tt.cell); got != tt.want {
+			t.Errorf("TileSound(%v) = %q; want %q", tt.cell, got, tt.want)
+		}
+	}
+	if got := s.gridDataI(0, 2); got != IntGridStone {
+		t.Errorf("cell (0, 2) = %v; want %v", got, IntGridStone)
+	}
+}
+
+func TestTileDataValue(t *testing.T) {
+	tests := []struct {
+		data, key, want string
+	}{
+		{"sound=stone", "sound", "stone"},
+		{"damage=1\nsound = grass ", "sound", "grass"},
+		{"damage=1;sound=dirt", "sound", "dirt"},
+		{"damage=1", "sound", ""},
+		{"", "sound", ""},
+	}
+	for _, tt := range tests {
+		if got := tileDataValue(tt.data, tt.key); got != tt.want {
+			t.Errorf("tileDataValue(%q, %q) = %q; want %q", tt.data, tt.key, got, tt.want)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package internal
 import (
 	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/kalexmills/asebiten"
+	"github.com/niftysoft/2d-platformer/internal/shader"
+	"log/slog"
+	"runtime"
 	"sync"
 )
 
@@ -11,9 +15,92 @@ import (
 var TPS float64
 var TPSOnce sync.Once
 
+const (
+	TPSLowerLimit = 30  // TPSLowerLimit is the lowest tick rate which may be configured.
+	TPSUpperLimit = 240 // TPSUpperLimit is the highest tick rate which may be configured.
+)
+
+// configuredTPS is the tick rate passed to SetTPS, or zero if the default tick rate is used.
+var configuredTPS int
+
+// SetTPS configures the number of ticks per second, capped by the MaxTPS option if it is set. A warning is logged when
+// the cap lowers the requested rate. It must be called before the game starts running.
+func SetTPS(n int) error {
+	if n < TPSLowerLimit || n > TPSUpperLimit {
+		return fmt.Errorf("tps must be in the range [%d, %d]; got %d", TPSLowerLimit, TPSUpperLimit, n)
+	}
+	configuredTPS = n
+	if options.MaxTPS != 0 && n > options.MaxTPS {
+		slog.Warn("tick rate capped by max tps", "requested", n, "max", options.MaxTPS)
+		configuredTPS = options.MaxTPS
+	}
+	ebiten.SetTPS(configuredTPS)
+	return nil
+}
+
+// Options configures how the game loop runs.
+type Options struct {
+	// MaxTPS caps the tick rate, including any rate configured with SetTPS. Ebiten does not report the display's
+	// refresh rate, so the cap is applied to the configured rate alone. Zero disables the cap.
+	MaxTPS int
+	// SleepOnIdle yields the processor at the end of each update in which no input was received and the current scene
+	// reports that nothing is animating. Disable it when high-precision frame timing is needed, since yielding may
+	// delay the start of the next tick.
+	SleepOnIdle bool
+	// ShaderEnabled post-processes the screen with scanlines and colour aberration, like an old CRT display.
+	ShaderEnabled bool
+	// Seed is combined with the ID of each level to seed its LevelRNG, so that a given seed always produces the same
+	// world.
+	Seed int64
+	// LevelSelect starts the game at the LevelSelectScene instead of the first level.
+	LevelSelect bool
+	// SweepMovement moves players along both axes in a single diagonal pass, so that they cannot clip through corners
+	// which lie between their start and end positions.
+	SweepMovement bool
+}
+
+// DefaultOptions are the Options used unless SetOptions is called.
+var DefaultOptions = Options{MaxTPS: 60, SleepOnIdle: true}
+
+// options are the Options the game runs with.
+var options = DefaultOptions
+
+// SetOptions configures the game loop. It must be called before the game starts running.
+func SetOptions(opts Options) error {
+	if opts.MaxTPS != 0 && (opts.MaxTPS < TPSLowerLimit || opts.MaxTPS > TPSUpperLimit) {
+		return fmt.Errorf("max tps must be 0 or in the range [%d, %d]; got %d", TPSLowerLimit, TPSUpperLimit,
+			opts.MaxTPS)
+	}
+	options = opts
+	if opts.MaxTPS == 0 {
+		return nil
+	}
+	if configuredTPS > opts.MaxTPS {
+		return SetTPS(configuredTPS) // logs that the configured rate was capped.
+	}
+	if configuredTPS == 0 && ebiten.DefaultTPS > opts.MaxTPS {
+		return SetTPS(opts.MaxTPS)
+	}
+	return nil
+}
+
 // Game implements ebiten.Game interface.
 type Game struct {
-	currScene Scene
+	scenes   []Scene        // scenes is a stack of scenes; only the top-most scene is updated and drawn.
+	save     *SaveData      // save is the player's progress.
+	settings *Settings      // settings holds the player's preferences.
+	font     *BitmapFont    // font is used to draw all in-game text.
+	bus      *EventBus      // bus dispatches game events between otherwise unrelated systems.
+	hud      *HUD           // hud is drawn over every scene.
+	audio    *AudioManager  // audio plays all sound effects.
+	rumble   *RumbleManager // rumble vibrates the player's gamepad.
+
+	keys []ebiten.Key // keys is the set of keys pressed during the last update.
+
+	crt       *ebiten.Shader // crt is the post-processing shader used when ShaderEnabled is set.
+	water     *ebiten.Shader // water is the post-processing shader used while the player is underwater.
+	offscreen *ebiten.Image  // offscreen is drawn to instead of the screen while the shader is in use.
+	ticks     int            // ticks is the number of times Update has been called.
 }
 
 func NewGame() (*Game, error) {
@@ -21,35 +108,153 @@ func NewGame() (*Game, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error loading game data: %v", err)
 	}
-	return &Game{
-		currScene: NewPlatformerScene(&data),
-	}, nil
+	font, err := LoadDefaultFont()
+	if err != nil {
+		return nil, fmt.Errorf("error loading font: %v", err)
+	}
+	bus := NewEventBus()
+	result := &Game{
+		save:     NewSaveData(),
+		settings: NewSettings(),
+		font:     font,
+		bus:      bus,
+		hud:      NewHUD(bus, font),
+		audio:    NewAudioManager(),
+	}
+	result.rumble = NewRumbleManager(bus, result.settings)
+	result.audio.ApplySettings(result.settings)
+	result.water, err = ebiten.NewShader(shader.Water)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling water shader: %v", err)
+	}
+	if options.ShaderEnabled {
+		result.crt, err = ebiten.NewShader(shader.CRT)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling crt shader: %v", err)
+		}
+	}
+	if options.LevelSelect {
+		result.PushScene(NewLevelSelectScene(result, &data))
+		return result, nil
+	}
+	scene := NewPlatformerScene(result, &data)
+	if err := scene.WatchGameData(); err != nil {
+		return nil, fmt.Errorf("error watching game data: %v", err)
+	}
+	result.PushScene(scene)
+	return result, nil
 }
 
 // Update proceeds the game state.
 // Update is called every tick (1/60 [s] by default).
 func (g *Game) Update() error {
 	asebiten.Update() // call once to update timing data.
+	g.ticks++
 	TPSOnce.Do(func() {
 		TPS = float64(ebiten.TPS())
+		if configuredTPS != 0 && ebiten.TPS() != configuredTPS {
+			fatal("tick rate does not match configuration", "configured", configuredTPS, "actual", ebiten.TPS())
+		}
 	})
-	return g.currScene.Update()
+	if err := g.currScene().Update(); err != nil {
+		return err
+	}
+	g.hud.Update()
+	g.audio.Update()
+	g.rumble.Update()
+	if options.SleepOnIdle && g.idle() {
+		runtime.Gosched()
+	}
+	return nil
+}
+
+// Idler is implemented by scenes which can report when nothing they draw is changing.
+type Idler interface {
+	// Idle returns true if the scene has nothing left to animate.
+	Idle() bool
+}
+
+// idle returns true if no input is held and the current scene reports that it is idle.
+func (g *Game) idle() bool {
+	g.keys = inpututil.AppendPressedKeys(g.keys[:0])
+	if len(g.keys) > 0 || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		return false
+	}
+	idler, ok := g.currScene().(Idler)
+	return ok && idler.Idle()
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
 func (g *Game) Draw(screen *ebiten.Image) {
-	// Write your game's rendering.
-	g.currScene.Draw(screen)
+	time := float32(g.ticks) / float32(ebiten.TPS())
+	if pp, ok := g.currScene().(PostProcessor); ok {
+		if sh, uniforms := pp.PostProcess(); sh != nil {
+			uniforms["Time"] = time
+			g.drawWithShader(screen, sh, uniforms)
+			return
+		}
+	}
+	if g.crt != nil {
+		g.drawWithShader(screen, g.crt, map[string]any{"Time": time})
+		return
+	}
+	g.currScene().Draw(screen)
+	g.hud.Draw(screen)
+}
+
+// PostProcessor is implemented by scenes which sometimes need the screen drawn through a shader of their own. The
+// scene's shader replaces any shader chosen by the game's Options.
+type PostProcessor interface {
+	// PostProcess returns the shader to draw the screen through and its uniforms, or a nil shader if none is needed.
+	// The Time uniform is set by the game.
+	PostProcess() (*ebiten.Shader, map[string]any)
+}
+
+// drawWithShader draws the current scene and HUD to an off-screen buffer, then draws the buffer to screen through the
+// provided shader.
+func (g *Game) drawWithShader(screen *ebiten.Image, sh *ebiten.Shader, uniforms map[string]any) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if g.offscreen == nil || g.offscreen.Bounds().Dx() != w || g.offscreen.Bounds().Dy() != h {
+		g.offscreen = ebiten.NewImage(w, h)
+	}
+	g.offscreen.Clear()
+	g.currScene().Draw(g.offscreen)
+	g.hud.Draw(g.offscreen)
+
+	opts := ebiten.DrawRectShaderOptions{}
+	opts.Images[0] = g.offscreen
+	opts.Uniforms = uniforms
+	screen.DrawRectShader(w, h, sh, &opts)
 }
 
 // Layout takes the outside size (e.g., the window size) and returns the (logical) screen size.
 // If you don't have to adjust the screen size with the outside size, just return a fixed size.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return g.currScene.Layout(outsideWidth, outsideHeight)
+	return g.currScene().Layout(outsideWidth, outsideHeight)
+}
+
+// currScene returns the scene at the top of the scene stack.
+func (g *Game) currScene() Scene {
+	return g.scenes[len(g.scenes)-1]
 }
 
-// ChangeScene sets the current scene to the provided Scene.
+// ChangeScene replaces the current scene with the provided Scene.
 func (g *Game) ChangeScene(s Scene) {
-	g.currScene = s
+	g.scenes[len(g.scenes)-1] = s
+}
+
+// PushScene pushes the provided Scene onto the scene stack, pausing the current scene until it is popped.
+func (g *Game) PushScene(s Scene) {
+	g.scenes = append(g.scenes, s)
+}
+
+// PopScene removes the current scene from the scene stack, resuming the scene beneath it. The root scene is never
+// popped.
+func (g *Game) PopScene() {
+	if len(g.scenes) <= 1 {
+		return
+	}
+	g.scenes[len(g.scenes)-1] = nil
+	g.scenes = g.scenes[:len(g.scenes)-1]
 }
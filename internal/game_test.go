@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"bytes"
+	"github.com/hajimehoshi/ebiten/v2"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetTPSScalesGravity(t *testing.T) {
+	s := newTestScene(t, grid(20, 20)...)
+	p := newTestPlayer(t, s, IVec2{X: 64, Y: 16})
+	prevTPS, prevConfigured := TPS, configuredTPS
+	defer func() {
+		TPS, configuredTPS = prevTPS, prevConfigured
+		ebiten.SetTPS(ebiten.DefaultTPS)
+	}()
+
+	// increment returns the change in the player's Y velocity over one frame of falling at the provided tick rate.
+	increment := func(tps int) float64 {
+		if err := SetTPS(tps); err != nil {
+			t.Fatalf("SetTPS(%d) returned error: %v", tps, err)
+		}
+		TPS = float64(ebiten.TPS())
+		p.SetPos(IVec2{X: 64, Y: 16})
+		p.Vel = Vec2{}
+		p.updateFalling(InputNone)
+		return p.Vel.Y
+	}
+	at30, at60 := increment(30), increment(60)
+	if math.Abs(at60-at30/2) > vecTolerance {
+		t.Errorf("gravity increment at 60 TPS = %v; want half of %v, the increment at 30 TPS", at60, at30)
+	}
+}
+
+func TestSetTPSLimits(t *testing.T) {
+	prevConfigured := configuredTPS
+	defer func() {
+		configuredTPS = prevConfigured
+		ebiten.SetTPS(ebiten.DefaultTPS)
+	}()
+	tests := []struct {
+		tps     int
+		wantErr bool
+	}{
+		{TPSLowerLimit - 1, true},
+		{TPSLowerLimit, false},
+		{TPSUpperLimit, false},
+		{TPSUpperLimit + 1, true},
+	}
+	for _, tt := range tests {
+		if err := SetTPS(tt.tps); (err != nil) != tt.wantErr {
+			t.Errorf("SetTPS(%d) = %v; want error %v", tt.tps, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMaxTPS(t *testing.T) {
+	prevOptions, prevConfigured, prevLogger := options, configuredTPS, slog.Default()
+	defer func() {
+		options, configuredTPS = prevOptions, prevConfigured
+		slog.SetDefault(prevLogger)
+		ebiten.SetTPS(ebiten.DefaultTPS)
+	}()
+	var logs bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	tests := []struct {
+		name    string
+		maxTPS  int
+		tps     int
+		want    int
+		wantLog bool
+	}{
+		{name: "capped by default", maxTPS: DefaultOptions.MaxTPS, tps: 120, want: 60, wantLog: true},
+		{name: "uncapped", maxTPS: 0, tps: 120, want: 120},
+		{name: "under the cap", maxTPS: 60, tps: 30, want: 30},
+		{name: "at the cap", maxTPS: 60, tps: 60, want: 60},
+		{name: "over the cap", maxTPS: 60, tps: 120, want: 60, wantLog: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			configuredTPS = 0
+			opts := DefaultOptions
+			opts.MaxTPS = tt.maxTPS
+			if err := SetOptions(opts); err != nil {
+				t.Fatalf("SetOptions(MaxTPS: %d) = %v", tt.maxTPS, err)
+			}
+			logs.Reset()
+			if err := SetTPS(tt.tps); err != nil {
+				t.Fatalf("SetTPS(%d) = %v", tt.tps, err)
+			}
+			if got := ebiten.TPS(); got != tt.want {
+				t.Errorf("ebiten.TPS() = %d after SetTPS(%d) with MaxTPS %d; want %d", got, tt.tps, tt.maxTPS, tt.want)
+			}
+			if logged := strings.Contains(logs.String(), "capped"); logged != tt.wantLog {
+				t.Errorf("capping was logged = %v; want %v; logs: %q", logged, tt.wantLog, logs.String())
+			}
+		})
+	}
+
+	for _, maxTPS := range []int{TPSLowerLimit - 1, TPSUpperLimit + 1, -1} {
+		if err := SetOptions(Options{MaxTPS: maxTPS}); err == nil {
+			t.Errorf("SetOptions(MaxTPS: %d) = nil; want an error", maxTPS)
+		}
+	}
+}
+
+func TestGameUpdateRespectsMaxTPS(t *testing.T) {
+	g := newTestGame(t)
+	prevOptions, prevConfigured, prevTPS, prevLogger := options, configuredTPS, TPS, slog.Default()
+	defer func() {
+		options, configuredTPS, TPS = prevOptions, prevConfigured, prevTPS
+		slog.SetDefault(prevLogger)
+		ebiten.SetTPS(ebiten.DefaultTPS)
+	}()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	configuredTPS = 0
+	if err := SetOptions(DefaultOptions); err != nil {
+		t.Fatalf("SetOptions(DefaultOptions) = %v", err)
+	}
+	if err := SetTPS(TPSUpperLimit); err != nil {
+		t.Fatalf("SetTPS(%d) = %v", TPSUpperLimit, err)
+	}
+	TPSOnce = sync.Once{} // let Update read the tick rate again.
+	for i := 0; i < 2*TPSUpperLimit; i++ {
+		if err := g.Update(); err != nil {
+			t.Fatalf("Update() = %v on tick %d", err, i)
+		}
+		if got := ebiten.TPS(); got != DefaultOptions.MaxTPS {
+			t.Fatalf("ebiten.TPS() = %d on tick %d; want %d", got, i, DefaultOptions.MaxTPS)
+		}
+		if TPS != float64(DefaultOptions.MaxTPS) {
+			t.Fatalf("TPS = %v on tick %d; want %d", TPS, i, DefaultOptions.MaxTPS)
+		}
+	}
+}
+
+// BenchmarkWaterShaderPass measures the full-screen water shader pass at 1280×720, which should add less than 3ms per
+// frame on a developer machine.
+func BenchmarkWaterShaderPass(b *testing.B) {
+	g := newTestGame(b)
+	src, screen := ebiten.NewImage(1280, 720), ebiten.NewImage(1280, 720)
+	opts := ebiten.DrawRectShaderOptions{}
+	opts.Images[0] = src
+	opts.Uniforms = map[string]any{"Time": float32(1), "Amplitude": float32(DefaultWaterAmplitude),
+		"Frequency": float32(DefaultWaterFrequency)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		screen.DrawRectShader(1280, 720, g.water, &opts)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !debug
+
+package internal
+
+// debugBuild is true when built with the debug tag, enabling development-only features.
+const debugBuild = false
+
+// watchGameData is a no-op outside of debug builds.
+func watchGameData(_ func()) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build debug
+
+package internal
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// debugBuild is true when built with the debug tag, enabling development-only features.
+const debugBuild = true
+
+// debugGameDataDir is the directory containing the gamedata folder on disk, relative to the working directory. Debug
+// builds read game data from disk instead of the embedded copy so that edits can be hot-reloaded.
+const debugGameDataDir = "internal"
+
+func init() {
+	gameDataFS = os.DirFS(debugGameDataDir)
+}
+
+// watchGameData calls onChange from a background goroutine each time the manifest or an LDtk file is written to disk.
+func watchGameData(onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(debugGameDataDir, "gamedata")
+	if err := watcher.Add(dir); err != nil { // watch the directory, since editors may replace the file on save.
+		watcher.Close()
+		return err
+	}
+	manifest := filepath.Join(dir, manifestPath)
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				name := filepath.Clean(event.Name)
+				if (name == manifest || filepath.Ext(name) == ".ldtk") && event.Has(fsnotify.Write|fsnotify.Create) {
+					onChange()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("error watching game data", "err", err)
+			}
+		}
+	}()
+	return nil
+}
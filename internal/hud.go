@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+const (
+	hudMargin   = 4 // hudMargin is the distance between the HUD and the edge of the screen.
+	hudIconSize = 8 // hudIconSize is the width and height of each HUD icon.
+
+	hudPopupFrames = 40  // hudPopupFrames is the number of frames each "+1" popup is shown for.
+	hudPopupSpeed  = 0.5 // hudPopupSpeed is how many pixels each popup rises per frame.
+)
+
+var hudPopupColor = color.RGBA{R: 0xff, G: 0xe0, B: 0x40, A: 0xff}
+
+// HUD draws the player's health, coins, keys, and the health of any active boss in screen-space. It keeps track of these by subscribing to events.
+type HUD struct {
+	font *BitmapFont
+
+	hp    int
+	maxHP int
+	coins int
+	keys  int
+
+	heart, emptyHeart, coin, key *ebiten.Image
+
+	minimap *Minimap      // minimap is the minimap for the current level; nil if no level is loaded.
+	bossBar BossHealthBar // bossBar shows the health of the active boss, if any.
+
+	popups  *ScreenParticleSystem // popups holds text effects shown over the HUD.
+	coinPos Vec2                  // coinPos is where the coin counter was last drawn, in screen coordinates.
+}
+
+// NewHUD constructs a HUD which subscribes to all events it needs from the provided EventBus.
+func NewHUD(bus *EventBus, font *BitmapFont) *HUD {
+	result := &HUD{
+		font:       font,
+		hp:         PlayerMaxHP,
+		maxHP:      PlayerMaxHP,
+		heart:      placeholderImage(hudIconSize, hudIconSize, colornames.Red),
+		emptyHeart: placeholderImage(hudIconSize, hudIconSize, colornames.Dimgray),
+		coin:       placeholderImage(hudIconSize, hudIconSize, colornames.Gold),
+		key:        placeholderImage(hudIconSize, hudIconSize, colornames.Lightskyblue),
+		popups:     NewScreenParticleSystem(font),
+	}
+	setHP := func(data any) {
+		if hp, ok := data.(int); ok {
+			result.hp = hp
+		}
+	}
+	bus.Subscribe(TopicPlayerDamaged, setHP)
+	bus.Subscribe(TopicPlayerRespawned, setHP)
+	bus.Subscribe(TopicCoinCollected, func(any) {
+		result.coins++
+		result.popups.Emit(result.coinPos, Vec2{Y: -hudPopupSpeed}, hudPopupFrames, "+1", 1, hudPopupColor)
+	})
+	bus.Subscribe(TopicKeyCollected, func(any) { result.keys++ })
+	bus.Subscribe(TopicBossHealthChanged, func(data any) {
+		if health, ok := data.(BossHealth); ok {
+			result.bossBar.health, result.bossBar.visible = health, true
+		}
+	})
+	bus.Subscribe(TopicBossDefeated, func(any) { result.bossBar.visible = false })
+	return result
+}
+
+// SetMinimap sets the minimap drawn in the corner of the screen.
+func (h *HUD) SetMinimap(m *Minimap) {
+	h.minimap = m
+}
+
+// Update animates any effects drawn over the HUD.
+func (h *HUD) Update() {
+	h.popups.Update()
+}
+
+// Draw draws the HUD to the provided screen.
+func (h *HUD) Draw(screen *ebiten.Image) {
+	if h.minimap != nil {
+		h.minimap.DrawTo(screen, screen.Bounds().Dx()-hudMargin, hudMargin)
+	}
+	h.bossBar.Draw(screen, hudMargin)
+
+	opts := ebiten.DrawImageOptions{}
+	y := screen.Bounds().Dy() - hudMargin - h.font.GlyphH
+
+	// draw health pips
+	for i := 0; i < h.maxHP; i++ {
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(float64(hudMargin+i*(hudIconSize+2)), float64(y+2))
+		if i < h.hp {
+			screen.DrawImage(h.heart, &opts)
+		} else {
+			screen.DrawImage(h.emptyHeart, &opts)
+		}
+	}
+
+	// draw coin counter
+	x := hudMargin + h.maxHP*(hudIconSize+2) + hudMargin
+	opts.GeoM.Reset()
+	opts.GeoM.Translate(float64(x), float64(y+2))
+	screen.DrawImage(h.coin, &opts)
+	text := fmt.Sprintf("x%d", h.coins)
+	h.font.DrawText(screen, text, x+hudIconSize+2, y, nil)
+	h.coinPos = Vec2{X: float64(x + hudIconSize + 2), Y: float64(y - h.font.GlyphH)}
+
+	// draw keys
+	w, _ := h.font.MeasureText(text)
+	x += hudIconSize + 2 + w + hudMargin
+	for i := 0; i < h.keys; i++ {
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(float64(x+i*(hudIconSize+2)), float64(y+2))
+		screen.DrawImage(h.key, &opts)
+	}
+
+	h.popups.Draw(screen)
+}
@@ -0,0 +1,21 @@
+package internal
+
+import "testing"
+
+func TestHUDCountsCoins(t *testing.T) {
+	font, err := LoadDefaultFont()
+	if err != nil {
+		t.Fatalf("could not load font: %v", err)
+	}
+	bus := NewEventBus()
+	hud := NewHUD(bus, font)
+	for i := 0; i < 3; i++ {
+		bus.Publish(TopicCoinCollected, nil)
+	}
+	if hud.coins != 3 {
+		t.Errorf("coins = %d after 3 coins were collected; want 3", hud.coins)
+	}
+	if hud.keys != 0 {
+		t.Errorf("keys = %d after only coins were collected; want 0", hud.keys)
+	}
+}
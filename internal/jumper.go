@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const (
+	JumpEnemyHP           = 2  // JumpEnemyHP is the health of each JumpEnemy.
+	JumpEnemyDetectRange  = 48 // JumpEnemyDetectRange is the default X distance at which a JumpEnemy leaps at the player.
+	JumpEnemyJumpForce    = 6  // JumpEnemyJumpForce is the upward velocity of a JumpEnemy's leap.
+	JumpEnemyLeapSpeed    = 2  // JumpEnemyLeapSpeed is the X velocity of a JumpEnemy's leap.
+	JumpEnemyJumpCooldown = 60 // JumpEnemyJumpCooldown is the number of frames a JumpEnemy waits after landing to leap again.
+)
+
+// JumpEnemyState is the state of a JumpEnemy.
+type JumpEnemyState byte
+
+const (
+	JumpEnemyPatrolling JumpEnemyState = iota // JumpEnemyPatrolling means the enemy is walking along its patrol.
+	JumpEnemyJumping                          // JumpEnemyJumping means the enemy is leaping toward the player.
+)
+
+// JumpEnemy patrols like a PatrolEnemy, but leaps toward the player when they come within range.
+type JumpEnemy struct {
+	PatrolEnemy
+	detectRange  int
+	jumpCooldown Cooldown // jumpCooldown must elapse after landing before the enemy may leap again.
+	state        JumpEnemyState
+}
+
+// NewJumpEnemy constructs a JumpEnemy from the provided entity. In addition to the fields used by NewPatrolEnemy, the
+// "detect_range" field gives the X distance in pixels at which the enemy leaps.
+func NewJumpEnemy(s *PlatformerScene, entity *Entity) (Enemy, error) {
+	patrol, err := NewPatrolEnemy(s, entity)
+	if err != nil {
+		return nil, err
+	}
+	result := &JumpEnemy{
+		PatrolEnemy:  *patrol.(*PatrolEnemy),
+		detectRange:  entity.FieldInt("detect_range"),
+		jumpCooldown: NewCooldown(JumpEnemyJumpCooldown),
+	}
+	result.HP = JumpEnemyHP
+	if result.detectRange <= 0 {
+		result.detectRange = JumpEnemyDetectRange
+	}
+	return result, nil
+}
+
+// Update patrols, leaping toward the player whenever they are in range and the enemy is able to jump.
+func (e *JumpEnemy) Update() {
+	switch e.state {
+	case JumpEnemyPatrolling:
+		e.jumpCooldown.Tick()
+		if e.playerInRange() && e.jumpCooldown.Ready() && e.onSolidGround() {
+			e.startJumping()
+			return
+		}
+		e.PatrolEnemy.Update()
+	case JumpEnemyJumping:
+		_, collidesY := e.move()
+		if collidesY.Colliding(ClipNone) && e.onSolidGround() {
+			e.jumpCooldown.Start()
+			e.state = JumpEnemyPatrolling
+		}
+	}
+}
+
+// playerInRange returns true if the nearest player is within the enemy's detection range along the X-axis.
+func (e *JumpEnemy) playerInRange() bool {
+	player := e.scene.nearestPlayer(e.Pos)
+	return player != nil && abs(player.Pos.X-e.Pos.X) < e.detectRange
+}
+
+// startJumping leaps toward the nearest player.
+func (e *JumpEnemy) startJumping() {
+	e.dir = sign(e.scene.nearestPlayer(e.Pos).Pos.X - e.Pos.X)
+	if e.dir == 0 {
+		e.dir = 1
+	}
+	e.Vel = Vec2{X: float64(e.dir) * JumpEnemyLeapSpeed, Y: -JumpEnemyJumpForce}
+	e.state = JumpEnemyJumping
+}
+
+// Draw draws the enemy as a placeholder rectangle.
+func (e *JumpEnemy) Draw(screen *ebiten.Image, camera IVec2) {
+	e.drawRect(screen, camera, color.RGBA{R: 0x30, G: 0xc0, B: 0x60, A: 0xff})
+}
@@ -0,0 +1,62 @@
+package internal
+
+import "testing"
+
+// newTestJumpEnemy spawns a 16x16 JumpEnemy standing on the floor of a scene created with grid, patrolling between the
+// provided X coordinates.
+func newTestJumpEnemy(t *testing.T, s *PlatformerScene, x, left, right int) *JumpEnemy {
+	t.Helper()
+	loadTestEntities(t, s, &Entity{
+		ID:       EtyJumpEnemy,
+		PxCoords: IVec2{X: x, Y: s.level.PxDims.H - testCellSize - 16},
+		Dim:      IDim{W: 16, H: 16},
+		Fields:   map[string]any{"patrol_left_x": float64(left), "patrol_right_x": float64(right)},
+	})
+	return s.enemies[len(s.enemies)-1].(*JumpEnemy)
+}
+
+func TestJumpEnemyPatrolReverses(t *testing.T) {
+	s := newTestScene(t, grid(40, 8)...)
+	newTestPlayer(t, s, IVec2{X: 560, Y: 64})
+	e := newTestJumpEnemy(t, s, 48, 32, 96)
+
+	reversals := 0
+	for i := 0; i < 600; i++ {
+		dir := e.dir
+		e.Update()
+		if e.state != JumpEnemyPatrolling {
+			t.Fatalf("enemy jumped at a player out of range")
+		}
+		if box := e.Hitbox(); box.X < 32-1 || box.X+box.W > 96+1 {
+			t.Fatalf("enemy hitbox %v left its patrol [32, 96]", box)
+		}
+		if e.dir != dir {
+			reversals++
+		}
+	}
+	if reversals < 2 {
+		t.Errorf("enemy reversed %d times in 600 frames; want it to walk back and forth", reversals)
+	}
+}
+
+func TestJumpEnemyLeapsAtPlayerInRange(t *testing.T) {
+	s := newTestScene(t, grid(40, 8)...)
+	p := newTestPlayer(t, s, IVec2{X: 64, Y: 64})
+	e := newTestJumpEnemy(t, s, 0, 0, 0)
+	e.Pos.X = p.Pos.X + JumpEnemyDetectRange - 1
+	e.Update()
+	if e.state != JumpEnemyJumping {
+		t.Fatalf("enemy did not leap at a player %d pixels away", JumpEnemyDetectRange-1)
+	}
+	if e.Vel.Y >= 0 || e.dir != -1 {
+		t.Errorf("enemy leapt with velocity %v and direction %d; want upward and toward the player", e.Vel, e.dir)
+	}
+
+	e = newTestJumpEnemy(t, s, 0, 0, 0)
+	e.Pos.X = p.Pos.X + JumpEnemyDetectRange
+	e.Update()
+	if e.state != JumpEnemyPatrolling {
+		t.Errorf("enemy leapt at a player %d pixels away; want only within %d", JumpEnemyDetectRange,
+			JumpEnemyDetectRange)
+	}
+}
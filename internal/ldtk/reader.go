@@ -10,3 +10,8 @@ func UnmarshalLdtkReader(r io.Reader) (LdtkJSON, error) {
 	err := json.NewDecoder(r).Decode(&result)
 	return result, err
 }
+
+// MarshalLdtkWriter writes the provided LDtk project to w as JSON.
+func MarshalLdtkWriter(w io.Writer, project *LdtkJSON) error {
+	return json.NewEncoder(w).Encode(project)
+}
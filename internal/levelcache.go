@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+)
+
+// MaxCachedLevels is the number of levels whose runtime state is kept once the player leaves them.
+const MaxCachedLevels = 5
+
+// LevelSnapshot holds the runtime state of a level which is lost when the level is reloaded from game data.
+type LevelSnapshot struct {
+	intGridData       []IntGridData      // intGridData is the level's collision grid, including any broken cells.
+	brokenCells       map[IVec2]bool     // brokenCells is the set of breakable cells destroyed in the level.
+	activeSwitches    map[uuid.UUID]bool // activeSwitches is the set of door switches the player has triggered.
+	destroyedEntities map[uuid.UUID]bool // destroyedEntities is the set of collected items and defeated enemies.
+}
+
+// LevelCache keeps a LevelSnapshot of the most recently visited levels, evicting the least recently used snapshot
+// once more than MaxCachedLevels are held.
+type LevelCache struct {
+	snapshots map[UID]*LevelSnapshot
+	recent    []UID // recent orders the UIDs of cached levels from least to most recently used.
+}
+
+// NewLevelCache constructs an empty LevelCache.
+func NewLevelCache() *LevelCache {
+	return &LevelCache{snapshots: make(map[UID]*LevelSnapshot)}
+}
+
+// Save stores the snapshot of the level with the provided UID, replacing any prior snapshot of the same level.
+func (c *LevelCache) Save(uid UID, snapshot *LevelSnapshot) {
+	c.snapshots[uid] = snapshot
+	c.touch(uid)
+	for len(c.recent) > MaxCachedLevels {
+		delete(c.snapshots, c.recent[0])
+		c.recent = c.recent[1:]
+	}
+}
+
+// Load returns the snapshot of the level with the provided UID, if one is cached.
+func (c *LevelCache) Load(uid UID) (*LevelSnapshot, bool) {
+	snapshot, ok := c.snapshots[uid]
+	if ok {
+		c.touch(uid)
+	}
+	return snapshot, ok
+}
+
+// touch marks the level with the provided UID as the most recently used.
+func (c *LevelCache) touch(uid UID) {
+	for i, id := range c.recent {
+		if id == uid {
+			c.recent = append(c.recent[:i], c.recent[i+1:]...)
+			break
+		}
+	}
+	c.recent = append(c.recent, uid)
+}
+
+// snapshot captures the runtime state of the current level.
+func (s *PlatformerScene) snapshot() *LevelSnapshot {
+	result := &LevelSnapshot{
+		intGridData:       append([]IntGridData(nil), s.intGridData...),
+		brokenCells:       make(map[IVec2]bool, len(s.brokenCells)),
+		activeSwitches:    make(map[uuid.UUID]bool, len(s.activeSwitches)),
+		destroyedEntities: make(map[uuid.UUID]bool, len(s.destroyedEntities)),
+	}
+	for cell := range s.brokenCells {
+		result.brokenCells[cell] = true
+	}
+	for iid := range s.activeSwitches {
+		result.activeSwitches[iid] = true
+	}
+	for iid := range s.destroyedEntities {
+		result.destroyedEntities[iid] = true
+	}
+	return result
+}
+
+// enterLevel saves the state of the current level to the level cache, then loads the level with the provided UID,
+// restoring any state cached from an earlier visit.
+func (s *PlatformerScene) enterLevel(uid UID) error {
+	if s.level != nil && s.level.UID != uid {
+		s.levelCache.Save(s.level.UID, s.snapshot())
+	}
+	if err := s.LoadLevel(uid); err != nil {
+		return err
+	}
+	if snapshot, ok := s.levelCache.Load(uid); ok {
+		s.restore(snapshot)
+	}
+	return nil
+}
+
+// restore applies the provided snapshot to the freshly loaded current level.
+func (s *PlatformerScene) restore(snapshot *LevelSnapshot) {
+	if len(snapshot.intGridData) == len(s.intGridData) { // the level's dimensions may have changed during hot reload.
+		copy(s.intGridData, snapshot.intGridData)
+		for cell := range snapshot.brokenCells {
+			s.brokenCells[cell] = true
+			s.retileAround(cell)
+			s.MarkDirty(IRect{X: cell.X * s.cellSize, Y: cell.Y * s.cellSize, W: s.cellSize, H: s.cellSize})
+		}
+		s.minimap = NewMinimap(s.intGridData, s.cellsWide, s.cellSize)
+		s.game.hud.SetMinimap(s.minimap)
+	}
+
+	for _, sw := range s.switches {
+		if !snapshot.activeSwitches[sw.IID] {
+			continue
+		}
+		s.activeSwitches[sw.IID] = true
+		for _, door := range s.doors {
+			if door.TriggerID == sw.FieldString("trigger_id") {
+				before := door.Bounds()
+				door.OpenNow()
+				s.markDoorDirty(before, door.Bounds())
+			}
+		}
+	}
+
+	for iid := range snapshot.destroyedEntities {
+		s.destroyedEntities[iid] = true
+	}
+	remaining := len(s.coins)
+	s.coins = s.removeDestroyed(s.coins)
+	s.coinCount = remaining - len(s.coins)
+	s.keyItems = s.removeDestroyed(s.keyItems)
+	s.powerUps = s.removeDestroyed(s.powerUps)
+	enemies := s.enemies[:0]
+	for _, enemy := range s.enemies {
+		if s.destroyedEntities[s.enemyIIDs[enemy]] {
+			s.RemoveMover(enemy)
+			continue
+		}
+		enemies = append(enemies, enemy)
+	}
+	s.enemies = enemies
+}
+
+// removeDestroyed returns the provided entities which have not been destroyed.
+func (s *PlatformerScene) removeDestroyed(entities []*Entity) []*Entity {
+	remaining := entities[:0]
+	for _, entity := range entities {
+		if !s.destroyedEntities[entity.IID] {
+			remaining = append(remaining, entity)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestLevelCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLevelCache()
+	for uid := UID(1); uid <= MaxCachedLevels; uid++ {
+		c.Save(uid, &LevelSnapshot{})
+	}
+	if _, ok := c.Load(1); !ok { // level 1 becomes the most recently used.
+		t.Fatalf("Load(1) found no snapshot; want the snapshot saved for level 1")
+	}
+	c.Save(MaxCachedLevels+1, &LevelSnapshot{})
+	c.Save(2, &LevelSnapshot{}) // replacing a cached snapshot evicts nothing.
+	for uid := UID(1); uid <= MaxCachedLevels+1; uid++ {
+		_, ok := c.Load(uid)
+		if want := uid != 3; ok != want { // level 3 was the least recently used when level 6 was saved.
+			t.Errorf("Load(%d) found a snapshot = %v; want %v", uid, ok, want)
+		}
+	}
+	if len(c.snapshots) != MaxCachedLevels {
+		t.Errorf("cache holds %d snapshots; want %d", len(c.snapshots), MaxCachedLevels)
+	}
+}
+
+func TestEnterLevelRestoresState(t *testing.T) {
+	s := newTestScene(t,
+		"..........",
+		"..........",
+		"......B...",
+		"##########",
+	)
+	registerTestLevel(s, 1)
+	p := newTestPlayer(t, s, IVec2{X: 32, Y: 16})
+	loadTestEntities(t, s,
+		&Entity{ID: EtyCoin, PxCoords: p.Hitbox().Center(), Dim: IDim{W: 8, H: 8}},
+		&Entity{ID: EtyCoin, PxCoords: IVec2{X: 128, Y: 16}, Dim: IDim{W: 8, H: 8}},
+	)
+	s.updateCollectibles()
+	broken := IVec2{X: 6, Y: 2}
+	if !s.breakCell(broken) {
+		t.Fatalf("breakCell(%v) = false; want the breakable cell destroyed", broken)
+	}
+
+	other := newTestScene(t, grid(10, 4)...)
+	registerTestLevel(other, 2)
+	s.gdat.Levels[2] = other.level
+	if err := s.enterLevel(2); err != nil {
+		t.Fatalf("enterLevel(2) error = %v", err)
+	}
+	if err := s.enterLevel(1); err != nil {
+		t.Fatalf("enterLevel(1) error = %v", err)
+	}
+	if got := s.gridDataI(broken.X, broken.Y); got != IntGridNothing {
+		t.Errorf("cell %v = %s after returning to the level; want it to stay broken", broken, got.Describe())
+	}
+	if s.coinCount != 1 || len(s.coins) != 1 {
+		t.Errorf("coinCount = %d with %d coins left after returning to the level; want 1 and 1", s.coinCount,
+			len(s.coins))
+	}
+}
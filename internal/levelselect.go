@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"log/slog"
+)
+
+const (
+	levelSelectX           = 24 // levelSelectX is the left edge of the level list, in pixels.
+	levelSelectY           = 32 // levelSelectY is the top edge of the first row of the level list, in pixels.
+	levelSelectRowHeight   = 15 // levelSelectRowHeight is the height of each row of the level list, in pixels.
+	levelSelectVisibleRows = 12 // levelSelectVisibleRows is the number of rows shown at once.
+)
+
+// LevelSelectScene lists every level in world order, allowing the player to start from any of them. Completed levels
+// are marked with a check along with the best time and the percentage of coins collected.
+type LevelSelectScene struct {
+	*BaseScene
+	gdat     *GameData
+	levels   []*Level
+	selected int // selected is the index of the highlighted level.
+	scroll   int // scroll is the index of the first visible level.
+}
+
+// NewLevelSelectScene constructs a new LevelSelectScene listing the levels of the provided GameData.
+func NewLevelSelectScene(g *Game, gdat *GameData) *LevelSelectScene {
+	return &LevelSelectScene{
+		BaseScene: NewBaseScene(g),
+		gdat:      gdat,
+		levels:    gdat.SortedLevels(),
+	}
+}
+
+// Update moves the selection with the arrow keys and starts the selected level once it is confirmed.
+func (s *LevelSelectScene) Update() error {
+	if len(s.levels) == 0 {
+		return nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		s.selected = posMod(s.selected-1, len(s.levels))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		s.selected = posMod(s.selected+1, len(s.levels))
+	}
+	s.scroll = min(s.scroll, s.selected)
+	s.scroll = max(s.scroll, s.selected-levelSelectVisibleRows+1)
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s.start(s.levels[s.selected])
+	}
+	return nil
+}
+
+// start replaces this scene with a new PlatformerScene playing the provided level.
+func (s *LevelSelectScene) start(level *Level) {
+	scene := NewPlatformerScene(s.game, s.gdat)
+	if err := scene.WatchGameData(); err != nil {
+		slog.Error("error watching game data", "err", err)
+	}
+	if err := scene.LoadLevel(level.UID); err != nil {
+		fatal("error loading level", "err", err)
+	}
+	s.game.ChangeScene(scene)
+}
+
+// Draw draws the visible portion of the level list, highlighting the selected level.
+func (s *LevelSelectScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	s.game.font.DrawText(screen, "SELECT LEVEL", levelSelectX, levelSelectY-2*levelSelectRowHeight, nil)
+	end := min(s.scroll+levelSelectVisibleRows, len(s.levels))
+	for i := s.scroll; i < end; i++ {
+		level := s.levels[i]
+		y := levelSelectY + (i-s.scroll)*levelSelectRowHeight
+		if i == s.selected {
+			w, _ := s.Layout(0, 0)
+			vector.DrawFilledRect(screen, levelSelectX-4, float32(y)-1, float32(w-2*levelSelectX+8), levelSelectRowHeight,
+				colornames.Darkslategray, false)
+		}
+		s.game.font.DrawText(screen, level.ID, levelSelectX+12, y, nil)
+
+		completion, ok := s.game.save.Completion[level.ID]
+		if !ok {
+			continue
+		}
+		drawCheck(screen, float32(levelSelectX), float32(y+levelSelectRowHeight/2))
+		coins := 100
+		if completion.TotalCoins > 0 {
+			coins = 100 * completion.CoinsCollected / completion.TotalCoins
+		}
+		s.game.font.DrawText(screen, fmt.Sprintf("%6.2fs %3d%%", completion.TimeSeconds, coins), 184, y, nil)
+	}
+}
+
+// drawCheck draws a small check mark with its left edge at x, vertically centered on y. The bitmap font only covers
+// ASCII, so the mark is drawn with lines instead of a glyph.
+func drawCheck(screen *ebiten.Image, x, y float32) {
+	vector.StrokeLine(screen, x, y, x+3, y+3, 1, colornames.Limegreen, false)
+	vector.StrokeLine(screen, x+3, y+3, x+8, y-3, 1, colornames.Limegreen, false)
+}
@@ -0,0 +1,257 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"math"
+)
+
+const (
+	DefaultDarkness     = 180 // DefaultDarkness is the alpha of the dark overlay in levels whose Darkness entity sets none.
+	PlayerLanternRadius = 40  // PlayerLanternRadius is the radius of the light carried by the player, in pixels.
+	TorchRadius         = 64  // TorchRadius is the radius of torches without a "radius" field, in pixels.
+	ExplosionLightScale = 2   // ExplosionLightScale is the radius of each explosion's light relative to its own radius.
+)
+
+var (
+	lanternColor   = color.RGBA{R: 0xff, G: 0xe8, B: 0xb0, A: 0xff}
+	torchColor     = color.RGBA{R: 0xff, G: 0xa0, B: 0x40, A: 0xff}
+	explosionColor = color.RGBA{R: 0xff, G: 0xc0, B: 0x40, A: 0xff}
+)
+
+// Light lifts the darkness in a circle of the provided radius around Pos. Intensity ranges from 0, which lifts none of
+// the darkness, to 1, which lifts all of it at the center of the light.
+type Light struct {
+	Pos       Vec2 // Pos is the center of the light in world coordinates.
+	Radius    float64
+	Color     color.RGBA // Color tints the area lit by the light.
+	Intensity float64
+}
+
+// lightKey identifies a cached light mask by its radius in pixels and intensity in percent.
+type lightKey struct {
+	radius, intensity int
+}
+
+// LightingSystem darkens the screen everywhere except around its lights. Static lights are baked into a lightmap
+// covering the whole level once it is loaded; dynamic lights are drawn on top each frame.
+type LightingSystem struct {
+	Darkness uint8 // Darkness is the alpha of the overlay drawn where there is no light. Zero disables lighting.
+
+	lights         []*Light // lights holds the dynamic lights, which may move or change each frame.
+	static         []Light  // static holds the lights baked into staticLightmap.
+	staticLightmap *ebiten.Image
+	staticGlow     *ebiten.Image // staticGlow holds the tint of every static light, baked alongside staticLightmap.
+	overlay        *ebiten.Image
+	masks          map[lightKey]*ebiten.Image // masks caches the image used to cut each size of light out of the overlay.
+	glows          map[lightKey]*ebiten.Image // glows caches the image used to tint each size of light.
+}
+
+// NewLightingSystem constructs a LightingSystem with no lights which is disabled until Darkness is set.
+func NewLightingSystem() *LightingSystem {
+	return &LightingSystem{masks: make(map[lightKey]*ebiten.Image), glows: make(map[lightKey]*ebiten.Image)}
+}
+
+// AddStatic adds a light which never moves or changes. Static lights are not drawn until BakeStatic is called.
+func (ls *LightingSystem) AddStatic(light Light) {
+	ls.static = append(ls.static, light)
+}
+
+// BakeStatic draws every static light into a lightmap covering the whole of the scene's current level.
+func (ls *LightingSystem) BakeStatic(scene *PlatformerScene) {
+	ls.staticLightmap, ls.staticGlow = nil, nil
+	if ls.Darkness == 0 || len(ls.static) == 0 {
+		return
+	}
+	dims := scene.level.PxDims
+	ls.staticLightmap = ebiten.NewImage(dims.W, dims.H)
+	ls.staticLightmap.Fill(color.RGBA{A: ls.Darkness})
+	ls.staticGlow = ebiten.NewImage(dims.W, dims.H)
+	for i := range ls.static {
+		ls.drawMask(ls.staticLightmap, &ls.static[i], IVec2{})
+		ls.drawGlow(ls.staticGlow, &ls.static[i], IVec2{})
+	}
+}
+
+// Add adds a dynamic light, returning a pointer which may be used to move or remove it.
+func (ls *LightingSystem) Add(light Light) *Light {
+	result := &light
+	ls.lights = append(ls.lights, result)
+	return result
+}
+
+// Remove removes the provided light. Lights which have already been removed are ignored.
+func (ls *LightingSystem) Remove(light *Light) {
+	for i, l := range ls.lights {
+		if l == light {
+			ls.lights = append(ls.lights[:i], ls.lights[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear removes all lights and disables lighting.
+func (ls *LightingSystem) Clear() {
+	ls.lights, ls.static = nil, nil
+	ls.staticLightmap, ls.staticGlow = nil, nil
+	ls.Darkness = 0
+}
+
+// Draw draws the dark overlay to screen with each light cut out of it, offset by the provided camera position.
+func (ls *LightingSystem) Draw(screen *ebiten.Image, camera IVec2) {
+	if ls.Darkness == 0 {
+		return
+	}
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if ls.overlay == nil || ls.overlay.Bounds().Dx() != w || ls.overlay.Bounds().Dy() != h {
+		ls.overlay = ebiten.NewImage(w, h)
+	}
+	ls.overlay.Fill(color.RGBA{A: ls.Darkness})
+
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(camera.X), float64(camera.Y))
+	if ls.staticLightmap != nil {
+		opts.Blend = ebiten.BlendCopy
+		ls.overlay.DrawImage(ls.staticLightmap, &opts)
+	}
+	for _, light := range ls.lights {
+		ls.drawMask(ls.overlay, light, camera)
+	}
+	screen.DrawImage(ls.overlay, nil)
+
+	if ls.staticGlow != nil {
+		opts.Blend = ebiten.BlendLighter
+		screen.DrawImage(ls.staticGlow, &opts)
+	}
+	for _, light := range ls.lights {
+		ls.drawGlow(screen, light, camera)
+	}
+}
+
+// drawMask cuts the provided light out of the darkness drawn on dst, offset by the provided camera position.
+func (ls *LightingSystem) drawMask(dst *ebiten.Image, light *Light, camera IVec2) {
+	key, ok := keyOf(light)
+	if !ok {
+		return
+	}
+	opts := lightOptions(light, camera, key.radius)
+	opts.Blend = ebiten.BlendDestinationIn
+	dst.DrawImage(ls.mask(key), &opts)
+}
+
+// drawGlow tints the area around the provided light on dst, offset by the provided camera position.
+func (ls *LightingSystem) drawGlow(dst *ebiten.Image, light *Light, camera IVec2) {
+	key, ok := keyOf(light)
+	if !ok {
+		return
+	}
+	opts := lightOptions(light, camera, key.radius)
+	opts.Blend = ebiten.BlendLighter
+	opts.ColorScale.ScaleWithColor(light.Color)
+	dst.DrawImage(ls.glow(key), &opts)
+}
+
+// keyOf returns the key of the cached images used to draw the provided light. ok is false if the light is too small
+// or dim to be drawn.
+func keyOf(light *Light) (key lightKey, ok bool) {
+	key = lightKey{radius: int(math.Ceil(light.Radius)), intensity: int(math.Round(light.Intensity * 100))}
+	return key, key.radius > 0 && key.intensity > 0
+}
+
+// lightOptions returns options which draw an image of the provided radius centered on light.
+func lightOptions(light *Light, camera IVec2, radius int) ebiten.DrawImageOptions {
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(light.Pos.X+float64(camera.X-radius), light.Pos.Y+float64(camera.Y-radius))
+	return opts
+}
+
+// mask returns an image whose alpha rises from 1-intensity at its center to 1 at its radius. Drawn with
+// BlendDestinationIn, it cuts a soft hole out of the overlay.
+func (ls *LightingSystem) mask(key lightKey) *ebiten.Image {
+	if img, ok := ls.masks[key]; ok {
+		return img
+	}
+	intensity := float64(key.intensity) / 100
+	img := radialGradient(key.radius, func(t float64) float64 { return 1 - intensity*(1-t) })
+	ls.masks[key] = img
+	return img
+}
+
+// glow returns a white image whose alpha falls from a quarter of the intensity at its center to zero at its radius,
+// used to tint the area around each light.
+func (ls *LightingSystem) glow(key lightKey) *ebiten.Image {
+	if img, ok := ls.glows[key]; ok {
+		return img
+	}
+	intensity := float64(key.intensity) / 100
+	img := radialGradient(key.radius, func(t float64) float64 { return intensity * (1 - t) / 4 })
+	ls.glows[key] = img
+	return img
+}
+
+// radialGradient returns a white square image of the provided radius whose alpha at each pixel is alpha(t), where t is
+// the pixel's distance from the center as a fraction of radius, clamped to 1.
+func radialGradient(radius int, alpha func(t float64) float64) *ebiten.Image {
+	size := 2 * radius
+	pix := make([]byte, 4*size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x-radius)+0.5, float64(y-radius)+0.5
+			t := min(math.Sqrt(dx*dx+dy*dy)/float64(radius), 1)
+			a := byte(255 * min(max(alpha(t), 0), 1))
+			i := 4 * (x + y*size)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = a, a, a, a // premultiplied white.
+		}
+	}
+	img := ebiten.NewImage(size, size)
+	img.WritePixels(pix)
+	return img
+}
+
+// updateLights moves each player's lantern to follow them.
+func (s *PlatformerScene) updateLights() {
+	for i, p := range s.players() {
+		s.lanterns[i].Pos = p.center()
+	}
+}
+
+// loadLights adds a lantern for each player and a static light for each torch and light in the level, then bakes the
+// static lights. Lighting is enabled only if the level contains a Darkness entity.
+func (s *PlatformerScene) loadLights(level *Level) error {
+	s.lighting.Clear()
+	s.lanterns = s.lanterns[:0]
+	for range s.players() {
+		lantern := s.lighting.Add(Light{Radius: PlayerLanternRadius, Color: lanternColor, Intensity: 1})
+		s.lanterns = append(s.lanterns, lantern)
+	}
+	for _, entity := range level.Entities {
+		switch entity.ID {
+		case EtyDarkness:
+			s.lighting.Darkness = DefaultDarkness
+			if alpha := entity.FieldInt("alpha"); alpha > 0 {
+				s.lighting.Darkness = uint8(min(alpha, 255))
+			}
+		case EtyTorch:
+			radius := entity.FieldFloat("radius")
+			if radius <= 0 {
+				radius = TorchRadius
+			}
+			s.lighting.AddStatic(Light{Pos: entity.PxBounds().Center().Vec2(), Radius: radius, Color: torchColor, Intensity: 1})
+		case EtyLight:
+			c, err := parseHexColor(entity.FieldString("color"))
+			if err != nil {
+				return fmt.Errorf("light %s: %w", entity.IID, err)
+			}
+			s.lighting.AddStatic(Light{
+				Pos:       entity.PxBounds().Center().Vec2(),
+				Radius:    entity.FieldFloat("radius"),
+				Color:     c,
+				Intensity: entity.FieldFloat("intensity"),
+			})
+		}
+	}
+	s.lighting.BakeStatic(s)
+	s.updateLights()
+	return nil
+}
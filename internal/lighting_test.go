@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"testing"
+)
+
+// BenchmarkLightingDraw measures drawing 20 dynamic lights onto a 320×180 render target.
+func BenchmarkLightingDraw(b *testing.B) {
+	ls := NewLightingSystem()
+	ls.Darkness = DefaultDarkness
+	for i := 0; i < 20; i++ {
+		ls.Add(Light{
+			Pos:       Vec2{X: float64(16 * i), Y: float64(90 + 40*(i%2) - 20)},
+			Radius:    TorchRadius,
+			Color:     torchColor,
+			Intensity: 1,
+		})
+	}
+	screen := ebiten.NewImage(320, 180)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ls.Draw(screen, IVec2{})
+	}
+}
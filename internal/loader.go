@@ -2,50 +2,156 @@ package internal
 
 import (
 	"embed"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/niftysoft/2d-platformer/internal/atlas"
 	"github.com/niftysoft/2d-platformer/internal/ldtk"
 	"image"
+	"image/color"
 	_ "image/png"
+	"io/fs"
+	"log/slog"
+	"math"
+	"strconv"
 )
 
 //go:embed gamedata
 var gameData embed.FS
 
+// gameDataFS is the filesystem game data is loaded from. Debug builds replace it to load game data from disk.
+var gameDataFS fs.FS = gameData
+
 // UID is an int64 that is used to represent a UID from LDtk.
 type UID = int64
 
 // GameData represents all the game data loaded from LDtk, including all loaded tilesets.
 type GameData struct {
-	json       *ldtk.LdtkJSON        // json is a straightforward representation of the LDtk JSON output.
-	Tilesets   map[UID]*ebiten.Image // Tilesets is a list of all images loaded as part of the tileset.
-	Levels     map[UID]*Level        // Levels is a list of levels by UID assigned in LDtk.
-	LevelsByID map[string]*Level     // LevelsByID references the same level constructs via the name provided in the LDtk editor.
+	json     *ldtk.LdtkJSON        // json is a straightforward representation of the LDtk JSON output.
+	merged   []*ldtk.LdtkJSON      // merged holds the LDtk projects of any other files merged into this GameData.
+	Tilesets map[UID]*ebiten.Image // Tilesets is a list of all images loaded as part of the tileset.
+	// Atlas holds every tileset packed into one image, so that tiles from different tilesets can be drawn in a single
+	// batch. Nil if the tilesets could not be packed.
+	Atlas *ebiten.Image
+	// AtlasOffsets holds the pixel offset of each tileset within Atlas, keyed by tileset UID.
+	AtlasOffsets map[UID]IVec2
+	// TilesetMeta holds the metadata set on each tileset in LDtk, keyed by tileset UID.
+	TilesetMeta map[UID]TilesetMetadata
+	Levels      map[UID]*Level    // Levels is a list of levels by UID assigned in LDtk.
+	LevelsByID  map[string]*Level // LevelsByID references the same level constructs via the name provided in the LDtk editor.
 
 	LevelStart UID // LevelStart is the UID of the level where the playerStart entity is found.
+
+	// IntGridMasks maps the IntGrid values of the collision layer to the CollideMask of each cell.
+	IntGridMasks map[int]CollideMask
+	// Enums holds the values of each enum defined in LDtk, keyed by the name of the enum.
+	Enums map[string][]LdtkEnum
+	// AutoTilers holds the auto-layer rules of each layer definition which has any, keyed by layer definition UID.
+	AutoTilers map[UID]*AutoTiler
+
+	worldLevelIndex map[IVec2]*Level // worldLevelIndex maps world cells to the level covering them.
+	worldCellSize   int              // worldCellSize is the size of each cell in worldLevelIndex, in pixels.
 }
 
-// ldtkPath is the path to the LDtk file representing all of this game's level data.
-const ldtkPath = "trash-knight-level-1.ldtk"
+// manifestPath is the path to the manifest listing the LDtk files which hold this game's level data, relative to the
+// gamedata embed folder.
+const manifestPath = "manifest.json"
 
-// LoadGameData loads all gamedata from the expected ldtkPath relative to the gamedata embed folder, including all
-// referenced tilesets, which are expected to be found under gamedata/atlas.
-func LoadGameData() (result GameData, err error) {
-	result.LevelStart = -1
+// Manifest lists the LDtk files which are merged to form the game's data.
+type Manifest struct {
+	Files []string `json:"files"` // Files holds the path of each LDtk file, relative to the gamedata embed folder.
+}
 
-	result.json, err = LoadLdtkJSON(ldtkPath)
+// LoadManifest loads the manifest from manifestPath.
+func LoadManifest() (Manifest, error) {
+	f, err := gameDataFS.Open("gamedata/" + manifestPath)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+	var result Manifest
+	if err := json.NewDecoder(f).Decode(&result); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", manifestPath, err)
+	}
+	return result, nil
+}
+
+// LevelMusicField is the identifier of the level field naming the track played in each level.
+const LevelMusicField = "music"
+
+// LoadGameData loads and merges the gamedata from each of the provided LDtk files, relative to the gamedata embed
+// folder, including all referenced tilesets, which are expected to be found under gamedata/atlas. If no paths are
+// provided, the files listed in the manifest are loaded.
+func LoadGameData(paths ...string) (GameData, error) {
+	if len(paths) == 0 {
+		manifest, err := LoadManifest()
+		if err != nil {
+			return GameData{}, err
+		}
+		paths = manifest.Files
+	}
+	if len(paths) == 0 {
+		return GameData{}, fmt.Errorf("no LDtk files to load")
+	}
+	result, err := loadGameDataFile(paths[0])
 	if err != nil {
 		return GameData{}, err
 	}
+	for _, path := range paths[1:] {
+		next, err := loadGameDataFile(path)
+		if err != nil {
+			return GameData{}, err
+		}
+		merged, err := MergeGameData(&result, &next)
+		if err != nil {
+			return GameData{}, fmt.Errorf("merging %s: %w", path, err)
+		}
+		result = *merged
+	}
+	if err := errors.Join(ValidateGameData(&result, enemyFactories)...); err != nil {
+		return GameData{}, err
+	}
+	result.buildWorldIndex(int(result.json.DefaultGridSize))
+	result.packAtlas()
+	return result, nil
+}
+
+// packAtlas packs every tileset into Atlas. If packing fails, tiles are drawn from their own tilesets instead.
+func (gd *GameData) packAtlas() {
+	img, offsets, err := atlas.PackTilesets(gd.Tilesets)
+	if err != nil {
+		slog.Warn("could not pack tilesets into an atlas", "err", err)
+		return
+	}
+	gd.Atlas = img
+	gd.AtlasOffsets = make(map[UID]IVec2, len(offsets))
+	for uid, offset := range offsets {
+		gd.AtlasOffsets[uid] = IVec2{X: offset.X, Y: offset.Y}
+	}
+}
+
+// loadGameDataFile loads the gamedata from a single LDtk file. The result is neither validated nor indexed.
+func loadGameDataFile(path string) (result GameData, err error) {
+	result.LevelStart = -1
+
+	result.json, err = LoadLdtkJSON(path)
+	if err != nil {
+		return GameData{}, fmt.Errorf("%s: %w", path, err)
+	}
 	result.Tilesets, err = LoadTilesets(result.json)
 	if err != nil {
 		return GameData{}, err
 	}
+	result.TilesetMeta = LoadTilesetMeta(result.json)
 	result.Levels, err = LoadLevels(result.json)
 	if err != nil {
 		return GameData{}, err
 	}
+	result.AutoTilers = LoadAutoTilers(result.json)
+	result.IntGridMasks = LoadIntGridMasks(result.json)
+	result.Enums = LoadEnums(result.json)
 	result.LevelsByID = make(map[string]*Level, len(result.Levels))
 	for uid, level := range result.Levels {
 		result.LevelsByID[level.ID] = level
@@ -55,17 +161,45 @@ func LoadGameData() (result GameData, err error) {
 			}
 		}
 	}
-	if result.LevelStart == -1 {
-		return GameData{}, errors.New("no player start found")
-	}
 	return result, nil
 }
 
+// intGridIdentifiers maps the identifiers of IntGrid values in LDtk to the CollideMask they represent.
+var intGridIdentifiers = map[string]CollideMask{
+	"Dirt":      CollideDirt,
+	"Ladder":    CollideLadder,
+	"Stone":     CollideStone,
+	"Magnet":    CollideMagnet,
+	"Breakable": CollideBreakable,
+	"Ice":       CollideIce,
+	"Bounce":    CollideBounce,
+}
+
+// LoadIntGridMasks maps each IntGrid value defined on the collision layer to a CollideMask by matching its identifier.
+// Values with unrecognized identifiers are left out of the map.
+func LoadIntGridMasks(json *ldtk.LdtkJSON) map[int]CollideMask {
+	result := make(map[int]CollideMask)
+	for _, layer := range json.Defs.Layers {
+		if layer.Identifier != CollisionLayerID {
+			continue
+		}
+		for _, value := range layer.IntGridValues {
+			if value.Identifier == nil {
+				continue
+			}
+			if mask, ok := intGridIdentifiers[*value.Identifier]; ok {
+				result[int(value.Value)] = mask
+			}
+		}
+	}
+	return result
+}
+
 // LoadLdtkJSON loads a LDtk file from the provided path, relative to the gamedata embed folder.
 //
 // https://ldtk.io/json/ for details on the spec.
 func LoadLdtkJSON(filename string) (*ldtk.LdtkJSON, error) {
-	f, err := gameData.Open("gamedata/" + filename)
+	f, err := gameDataFS.Open("gamedata/" + filename)
 	if err != nil {
 		return nil, err
 	}
@@ -98,16 +232,52 @@ func LoadTilesets(json *ldtk.LdtkJSON) (map[UID]*ebiten.Image, error) {
 	return result, nil
 }
 
-// LoadLevels loads all data for levels which are stored in the provided json into memory, keyed by UID.
+// TilesetMetadata holds the metadata set on a tileset in LDtk.
+type TilesetMetadata struct {
+	CustomData map[int]string // CustomData maps tile IDs to the custom data string set on each tile.
+}
+
+// LoadTilesetMeta loads the metadata of all tilesets defined in the provided LDTK file; keyed by UID.
+func LoadTilesetMeta(json *ldtk.LdtkJSON) map[UID]TilesetMetadata {
+	result := make(map[UID]TilesetMetadata, len(json.Defs.Tilesets))
+	for _, tileset := range json.Defs.Tilesets {
+		meta := TilesetMetadata{CustomData: make(map[int]string, len(tileset.CustomData))}
+		for _, data := range tileset.CustomData {
+			meta.CustomData[int(data.TileID)] = data.Data
+		}
+		result[tileset.Uid] = meta
+	}
+	return result
+}
+
+// TileCustomData returns the custom data set on the provided tile in LDtk, or the empty string if none was set.
+func (gd *GameData) TileCustomData(tilesetUID UID, tileID int) string {
+	return gd.TilesetMeta[tilesetUID].CustomData[tileID]
+}
+
+// LoadLevels loads all data for levels which are stored in the provided json into memory, keyed by UID. The tiles of
+// each auto-layer are regenerated from its rules.
 func LoadLevels(json *ldtk.LdtkJSON) (map[UID]*Level, error) {
+	tilers := LoadAutoTilers(json)
 	result := make(map[UID]*Level, len(json.Levels))
 	for _, lvl := range json.Levels {
+		bgColor, err := parseHexColor(lvl.BgColor)
+		if err != nil {
+			return nil, fmt.Errorf("level %s: %w", lvl.Identifier, err)
+		}
 		level := &Level{
+			UID:         lvl.Uid,
 			ID:          lvl.Identifier,
 			WorldCoords: IVec2{X: int(lvl.WorldX), Y: int(lvl.WorldY)},
 			PxDims:      IDim{W: int(lvl.PxWid), H: int(lvl.PxHei)},
+			BGColor:     bgColor,
 			layersByID:  make(map[string]*TileLayer),
 		}
+		for _, field := range lvl.FieldInstances {
+			if name, ok := field.Value.(string); ok && field.Identifier == LevelMusicField {
+				level.Music = name
+			}
+		}
 		n := len(lvl.LayerInstances)
 		level.layers = make([]*TileLayer, n)
 		for i, lay := range lvl.LayerInstances {
@@ -118,11 +288,24 @@ func LoadLevels(json *ldtk.LdtkJSON) (map[UID]*Level, error) {
 			// add all layer entities to level
 			level.Entities = append(level.Entities, layer.Entities...)
 		}
+		applyAutoTilers(level, tilers)
 		result[lvl.Uid] = level
 	}
 	return result, nil
 }
 
+// parseHexColor parses an opaque color in "#rrggbb" format.
+func parseHexColor(hex string) (color.RGBA, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid color: %q", hex)
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color: %q: %w", hex, err)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
 func loadLayer(layer *ldtk.LayerInstance) *TileLayer {
 	result := &TileLayer{
 		ID:         layer.Identifier,
@@ -163,17 +346,22 @@ func loadTiles(out *TileLayer, tiles []ldtk.TileInstance) {
 
 func loadEntities(out *TileLayer, entities []ldtk.EntityInstance) {
 	for _, entity := range entities {
+		fields := make(map[string]any, len(entity.FieldInstances))
+		for _, field := range entity.FieldInstances {
+			fields[field.Identifier] = field.Value
+		}
 		out.Entities = append(out.Entities, &Entity{
 			ID:       entity.Identifier,
 			IID:      uuid.MustParse(entity.Iid), // safe per spec
 			PxCoords: IVec2{X: int(entity.Px[0]), Y: int(entity.Px[1])},
 			Dim:      IDim{W: int(entity.Width), H: int(entity.Height)},
+			Fields:   fields,
 		})
 	}
 }
 
 func loadImage(path string) (image.Image, error) {
-	f, err := gameData.Open("gamedata/" + path)
+	f, err := gameDataFS.Open("gamedata/" + path)
 	if err != nil {
 		return nil, err
 	}
@@ -184,12 +372,51 @@ func loadImage(path string) (image.Image, error) {
 
 // Level stores a layer of tiles together along with all collision elements needed.
 type Level struct {
+	UID         UID                   // UID is the unique identifier assigned to this level by LDtk.
 	ID          string                // ID is the user-friendly level identifier specified in the LDtk editor.
 	layers      []*TileLayer          // layers is the list of layers in draw order.
 	layersByID  map[string]*TileLayer // layersByID maps string IDs set by the user in LDtk to layers.
 	WorldCoords IVec2                 // WorldCoords represents the level's world coordinates in pixels.
 	PxDims      IDim                  // PxDims represents the dimensions of the level in pixels.
+	BGColor     color.RGBA            // BGColor is the color drawn behind all tiles in the level.
 	Entities    []*Entity             // Entities is the union of all entities found in all layers in this level.
+	Music       string                // Music is the name of the track played in this level, set by its "music" field.
+
+	tilePxIndex   map[IVec2][]Tile // tilePxIndex maps the pixel coordinates of each grid cell to the tiles drawn there.
+	tileGridSizes []int            // tileGridSizes is the distinct grid sizes of the layers in tilePxIndex.
+}
+
+// indexTile adds the provided tile from a layer with the provided grid size to the index used by TileAt.
+func (l *Level) indexTile(tile Tile, gridSize int) {
+	if l.tilePxIndex == nil {
+		l.tilePxIndex = make(map[IVec2][]Tile)
+	}
+	key := IVec2{X: floorDiv(tile.PxCoords.X, gridSize) * gridSize, Y: floorDiv(tile.PxCoords.Y, gridSize) * gridSize}
+	l.tilePxIndex[key] = append(l.tilePxIndex[key], tile)
+	for _, size := range l.tileGridSizes {
+		if size == gridSize {
+			return
+		}
+	}
+	l.tileGridSizes = append(l.tileGridSizes, gridSize)
+}
+
+// TileAt returns every tile, outside the collision layer, whose cell contains the provided point in world coordinates.
+// Tiles from layers sharing a grid size are returned in draw order. The index is built when the level's background is
+// loaded.
+func (l *Level) TileAt(worldPx IVec2) []Tile {
+	local := worldPx.Sub(l.WorldCoords)
+	var result []Tile
+	for _, size := range l.tileGridSizes {
+		key := IVec2{X: floorDiv(local.X, size) * size, Y: floorDiv(local.Y, size) * size}
+		result = append(result, l.tilePxIndex[key]...)
+	}
+	return result
+}
+
+// WorldRect returns the bounds of this level in world coordinates.
+func (l *Level) WorldRect() IRect {
+	return IRect{X: l.WorldCoords.X, Y: l.WorldCoords.Y, W: l.PxDims.W, H: l.PxDims.H}
 }
 
 // A TileLayer can contain entities, tiles, or an integer Grid. When a TileLayer contains entities it will never
@@ -207,12 +434,47 @@ type TileLayer struct {
 	Entities   []*Entity // Entities is the list of entities found on this layer.
 }
 
+// ForEachCell calls f for each cell of this layer's int Grid, in row-major order.
+func (l *TileLayer) ForEachCell(f func(cx, cy int, val int)) {
+	for idx, val := range l.Grid {
+		f(idx%l.CellDims.W, idx/l.CellDims.W, val)
+	}
+}
+
+// CellAt returns the value of the int Grid at the provided cell coordinates, or zero if the cell is out of bounds.
+func (l *TileLayer) CellAt(cx, cy int) int {
+	idx, ok := l.cellIdx(cx, cy)
+	if !ok {
+		return 0
+	}
+	return l.Grid[idx]
+}
+
+// SetCellAt sets the value of the int Grid at the provided cell coordinates. If the cell is out of bounds, this func is
+// a no-op.
+func (l *TileLayer) SetCellAt(cx, cy, val int) {
+	if idx, ok := l.cellIdx(cx, cy); ok {
+		l.Grid[idx] = val
+	}
+}
+
+// cellIdx returns the index of the provided cell in Grid, returning false if the cell is out of bounds.
+func (l *TileLayer) cellIdx(cx, cy int) (int, bool) {
+	if cx < 0 || cx >= l.CellDims.W || cy < 0 || cy >= l.CellDims.H {
+		return 0, false
+	}
+	idx := cx + cy*l.CellDims.W
+	return idx, idx < len(l.Grid)
+}
+
 // Entity represents raw entity data loaded from LDtk.
 type Entity struct {
 	ID       string    // ID is the unique identifier corresponding to the entity's type.
 	IID      uuid.UUID // IID is the instance identifier of this particular entity.
 	PxCoords IVec2     // PxCoords are the pixel coordinates of this entity.
 	Dim      IDim      // Dim is the dimensions of the entity in pixel coordinates.
+	// Fields holds the raw values of all custom fields set on this entity in LDtk, keyed by field identifier.
+	Fields map[string]any
 }
 
 // Tile represents one tile to be drawn in this layer.
@@ -220,26 +482,34 @@ type Tile struct {
 	PxCoords  IVec2 // PxCoords is the pixel coordinates of the tile in its layer.
 	SrcCoords IVec2 // SrcCoords are the pixel coordinates of the tile in its tileset.
 	TileID    int   // TileID is the ID of this tile in its tileset.
-	// FlipBits represents whether the tile is flipped horizontally or vertically or both.
-	// 0 = no flip; 1 = x flip only; 2 = y flip only; 3 = x and y flip.
+	// FlipBits represents how the tile is transformed; see TileFlipX, TileFlipY, and TileRotate90. Flips are applied
+	// before rotation, giving 8 distinct transforms.
 	FlipBits byte
 }
 
-// GeoM retrieves the world matrix for this tile.
+// Bits of Tile.FlipBits.
+const (
+	TileFlipX    = 0x1 // TileFlipX flips the tile horizontally.
+	TileFlipY    = 0x2 // TileFlipY flips the tile vertically.
+	TileRotate90 = 0x4 // TileRotate90 rotates the tile 90 degrees clockwise, after any flips.
+)
+
+// GeoM retrieves the world matrix for this tile. Flips and rotation are applied about the center of the tile, so that
+// it stays within its cell.
 func (t Tile) GeoM(gridSize int) ebiten.GeoM {
 	result := ebiten.GeoM{}
-	switch t.FlipBits {
-	case 0x1: // X flip
+	half := float64(gridSize) / 2
+	result.Translate(-half, -half)
+	if t.FlipBits&TileFlipX != 0 {
 		result.Scale(-1, 1)
-		result.Translate(float64(gridSize), 0)
-	case 0x2: // Y flip
+	}
+	if t.FlipBits&TileFlipY != 0 {
 		result.Scale(1, -1)
-		result.Translate(0, float64(gridSize))
-	case 0x3: // X and Y flip
-		result.Scale(-1, -1)
-		result.Translate(float64(gridSize), float64(gridSize))
 	}
-	result.Translate(float64(t.PxCoords.X), float64(t.PxCoords.Y))
+	if t.FlipBits&TileRotate90 != 0 {
+		result.Rotate(math.Pi / 2)
+	}
+	result.Translate(half+float64(t.PxCoords.X), half+float64(t.PxCoords.Y))
 	return result
 }
 
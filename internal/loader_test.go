@@ -0,0 +1,193 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		hex     string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{hex: "#ff8800", want: color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}},
+		{hex: "#000000", want: color.RGBA{A: 0xff}},
+		{hex: "#A0b1C2", want: color.RGBA{R: 0xa0, G: 0xb1, B: 0xc2, A: 0xff}},
+		{hex: "ff8800", wantErr: true},
+		{hex: "#ff88", wantErr: true},
+		{hex: "#gg8800", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseHexColor(tt.hex)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHexColor(%q) error = %v; want error %v", tt.hex, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseHexColor(%q) = %v; want %v", tt.hex, got, tt.want)
+		}
+	}
+}
+
+func TestTileLayerCells(t *testing.T) {
+	l := &TileLayer{CellDims: IDim{W: 3, H: 2}, Grid: []int{1, 2, 3, 4, 5, 6}}
+	var visited []IVec2
+	l.ForEachCell(func(cx, cy, val int) {
+		if want := cx + cy*3 + 1; val != want {
+			t.Errorf("ForEachCell visited (%d, %d) with %d; want %d", cx, cy, val, want)
+		}
+		visited = append(visited, IVec2{X: cx, Y: cy})
+	})
+	if len(visited) != 6 || visited[3] != (IVec2{X: 0, Y: 1}) {
+		t.Errorf("ForEachCell visited %v; want every cell in row-major order", visited)
+	}
+	for _, cell := range []IVec2{{X: -1}, {X: 3}, {Y: -1}, {Y: 2}, {X: 5, Y: 5}} {
+		if got := l.CellAt(cell.X, cell.Y); got != 0 {
+			t.Errorf("CellAt(%d, %d) = %d; want 0 out of bounds", cell.X, cell.Y, got)
+		}
+	}
+	l.SetCellAt(2, 1, 9)
+	l.SetCellAt(3, 1, 9) // out of bounds; no-op.
+	if got := l.CellAt(2, 1); got != 9 {
+		t.Errorf("CellAt(2, 1) = %d after SetCellAt; want 9", got)
+	}
+	if got := l.CellAt(0, 1); got != 4 {
+		t.Errorf("out of bounds SetCellAt changed CellAt(0, 1) to %d", got)
+	}
+}
+
+// testIntGridJSON returns a LDtk project whose collision layer defines the provided IntGrid identifiers, numbered from 1
+// in the order given.
+func testIntGridJSON(t *testing.T, identifiers ...string) *ldtk.LdtkJSON {
+	t.Helper()
+	values := ""
+	for i, id := range identifiers {
+		if i > 0 {
+			values += ","
+		}
+		values += fmt.Sprintf(`{"identifier":%q,"value":%d}`, id, i+1)
+	}
+	json, err := ldtk.UnmarshalLdtkJSON([]byte(fmt.Sprintf(
+		`{"defs":{"layers":[{"identifier":%q,"intGridValues":[%s]}]}}`, CollisionLayerID, values)))
+	if err != nil {
+		t.Fatalf("could not unmarshal LDtk JSON: %v", err)
+	}
+	return &json
+}
+
+func TestLoadIntGridMasks(t *testing.T) {
+	ordered := LoadIntGridMasks(testIntGridJSON(t, "Dirt", "Ladder", "Stone", "Unknown"))
+	reordered := LoadIntGridMasks(testIntGridJSON(t, "Stone", "Unknown", "Dirt", "Ladder"))
+
+	if len(ordered) != 3 || len(reordered) != 3 {
+		t.Errorf("got %d and %d masks; want 3, ignoring unknown identifiers", len(ordered), len(reordered))
+	}
+	if got := reordered[3]; got != ordered[1] {
+		t.Errorf("reordered Dirt = %v; want %v", got.Describe(), ordered[1].Describe())
+	}
+	if got := reordered[1]; got != ordered[3] {
+		t.Errorf("reordered Stone = %v; want %v", got.Describe(), ordered[3].Describe())
+	}
+	if got := reordered[4]; got != ordered[2] {
+		t.Errorf("reordered Ladder = %v; want %v", got.Describe(), ordered[2].Describe())
+	}
+}
+
+func TestTileCustomData(t *testing.T) {
+	json, err := ldtk.UnmarshalLdtkJSON([]byte(`{"defs":{"tilesets":[
+		{"uid":7,"customData":[{"tileId":3,"data":"sound=grass"},{"tileId":5,"data":"damage=1"}]},
+		{"uid":8,"customData":[]}
+	]}}`))
+	if err != nil {
+		t.Fatalf("could not unmarshal LDtk JSON: %v", err)
+	}
+	gd := GameData{TilesetMeta: LoadTilesetMeta(&json)}
+	tests := []struct {
+		uid    UID
+		tileID int
+		want   string
+	}{
+		{7, 3, "sound=grass"},
+		{7, 5, "damage=1"},
+		{7, 4, ""},
+		{8, 3, ""},
+		{9, 3, ""},
+	}
+	for _, tt := range tests {
+		if got := gd.TileCustomData(tt.uid, tt.tileID); got != tt.want {
+			t.Errorf("TileCustomData(%d, %d) = %q; want %q", tt.uid, tt.tileID, got, tt.want)
+		}
+	}
+}
+
+func TestLevelTileAt(t *testing.T) {
+	s := newTestScene(t, grid(4, 4)...)
+	layer := addTestTileset(s)
+	layer.Tiles = []Tile{testTile(0, 0, 0), testTile(1, 0, 1), testTile(1, 1, 2)}
+	collision := *layer
+	collision.ID = CollisionLayerID
+	collision.Tiles = []Tile{testTile(0, 0, 2)}
+	s.level.layers = append(s.level.layers, &collision)
+	s.level.WorldCoords = IVec2{X: 1000, Y: -500}
+	if err := s.loadBackground(s.level); err != nil {
+		t.Fatalf("loadBackground() = %v", err)
+	}
+
+	for cy := 0; cy < 2; cy++ {
+		for cx := 0; cx < 2; cx++ {
+			var want []Tile
+			for _, tile := range layer.Tiles {
+				if tile.PxCoords == (IVec2{X: cx * testCellSize, Y: cy * testCellSize}) {
+					want = append(want, tile)
+				}
+			}
+			for _, off := range []IVec2{{}, {X: testCellSize - 1}, {Y: testCellSize - 1}, {X: 7, Y: 9}} {
+				pt := s.level.WorldCoords.Add(IVec2{X: cx*testCellSize + off.X, Y: cy*testCellSize + off.Y})
+				got := s.level.TileAt(pt)
+				if len(got) != len(want) || (len(want) > 0 && got[0] != want[0]) {
+					t.Errorf("TileAt(%v) = %v; want %v", pt, got, want)
+				}
+			}
+		}
+	}
+	if got := s.level.TileAt(IVec2{X: 10, Y: 10}); len(got) != 0 {
+		t.Errorf("TileAt() in level-local rather than world coordinates = %v; want no tiles", got)
+	}
+}
+
+func TestTileGeoM(t *testing.T) {
+	const size = 16
+	origin := IVec2{X: 32, Y: 16}
+	tests := []struct {
+		flipBits                  byte
+		wantTopLeft, wantTopRight IVec2 // wantTopLeft and wantTopRight are where the top corners of the tile are drawn.
+	}{
+		{0, IVec2{X: 0, Y: 0}, IVec2{X: 15, Y: 0}},
+		{TileFlipX, IVec2{X: 15, Y: 0}, IVec2{X: 0, Y: 0}},
+		{TileFlipY, IVec2{X: 0, Y: 15}, IVec2{X: 15, Y: 15}},
+		{TileFlipX | TileFlipY, IVec2{X: 15, Y: 15}, IVec2{X: 0, Y: 15}},
+		{TileRotate90, IVec2{X: 15, Y: 0}, IVec2{X: 15, Y: 15}},
+		{TileRotate90 | TileFlipX, IVec2{X: 15, Y: 15}, IVec2{X: 15, Y: 0}},
+		{TileRotate90 | TileFlipY, IVec2{X: 0, Y: 0}, IVec2{X: 0, Y: 15}},
+		{TileRotate90 | TileFlipX | TileFlipY, IVec2{X: 0, Y: 15}, IVec2{X: 0, Y: 0}},
+	}
+	// corner returns the pixel of the cell which the center of the source pixel (x, y) is drawn to.
+	corner := func(geoM ebiten.GeoM, x, y int) IVec2 {
+		dx, dy := geoM.Apply(float64(x)+0.5, float64(y)+0.5)
+		return IVec2{X: int(math.Floor(dx)), Y: int(math.Floor(dy))}.Sub(origin)
+	}
+	for _, tt := range tests {
+		geoM := Tile{PxCoords: origin, FlipBits: tt.flipBits}.GeoM(size)
+		if got := corner(geoM, 0, 0); got != tt.wantTopLeft {
+			t.Errorf("FlipBits %03b: top-left pixel drawn at %v; want %v", tt.flipBits, got, tt.wantTopLeft)
+		}
+		if got := corner(geoM, size-1, 0); got != tt.wantTopRight {
+			t.Errorf("FlipBits %03b: top-right pixel drawn at %v; want %v", tt.flipBits, got, tt.wantTopRight)
+		}
+	}
+}
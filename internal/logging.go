@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"log/slog"
+	"os"
+)
+
+// SetupLogger installs the default logger, writing to stderr at the provided level. format is either "text" or "json";
+// any other value falls back to "text".
+func SetupLogger(level slog.Level, format string) {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// fatal logs msg and the provided attributes as an error and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestSetupLogger(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	tests := []struct {
+		level  slog.Level
+		format string
+		json   bool
+	}{
+		{slog.LevelDebug, "text", false},
+		{slog.LevelInfo, "json", true},
+		{slog.LevelWarn, "", false},
+	}
+	for _, tt := range tests {
+		SetupLogger(tt.level, tt.format)
+		handler := slog.Default().Handler()
+		if _, ok := handler.(*slog.JSONHandler); ok != tt.json {
+			t.Errorf("SetupLogger(%v, %q) installed %T", tt.level, tt.format, handler)
+		}
+		if !handler.Enabled(context.Background(), tt.level) {
+			t.Errorf("SetupLogger(%v, %q) does not log at %v", tt.level, tt.format, tt.level)
+		}
+		if handler.Enabled(context.Background(), tt.level-1) {
+			t.Errorf("SetupLogger(%v, %q) logs below %v", tt.level, tt.format, tt.level)
+		}
+	}
+}
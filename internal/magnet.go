@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+const MagnetRange = 5     // MagnetRange is the distance in cells within which powered magnets attract metallic crates.
+const MagnetForce = 0.25  // MagnetForce is the acceleration in pixels per frame^2 powered magnets apply to metallic crates.
+const CrateFriction = 0.8 // CrateFriction multiplies the X velocity of crates resting on solid ground each frame.
+
+var (
+	crateColor        = color.RGBA{R: 0x8b, G: 0x5a, B: 0x2b, A: 0xff}
+	metalCrateColor   = color.RGBA{R: 0x90, G: 0x98, B: 0xa8, A: 0xff}
+	crateOutlineColor = color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}
+)
+
+// Crate is a movable box which falls under gravity and collides with the level. Magnetic crates are pulled toward
+// nearby powered magnets.
+type Crate struct {
+	Actor
+	Pos      IVec2 // Pos is the top-left corner of the crate's hitbox in pixel coordinates.
+	Vel      Vec2
+	Dim      IDim
+	Magnetic bool // Magnetic is set for metal crates which are attracted by magnets.
+	subX     float64
+	subY     float64
+}
+
+// NewCrate constructs a Crate from the provided entity. The crate is magnetic if its "magnetic" field is set.
+func NewCrate(s *PlatformerScene, entity *Entity) *Crate {
+	return &Crate{
+		Actor:    Actor{scene: s},
+		Pos:      entity.PxCoords,
+		Dim:      entity.Dim,
+		Magnetic: entity.FieldBool("magnetic"),
+	}
+}
+
+// Hitbox returns the crate's hitbox in pixel coordinates.
+func (c *Crate) Hitbox() IRect {
+	return IRect{X: c.Pos.X, Y: c.Pos.Y, W: c.Dim.W, H: c.Dim.H}
+}
+
+// Update applies gravity and moves the crate by its velocity, stopping it along any axis it collides on.
+func (c *Crate) Update() {
+	c.Vel.Y = min(c.Vel.Y+Gravity/TPS, PlayerTerminalVelocity)
+
+	var whole float64
+	whole, c.subY = splitSubpixel(c.Vel.Y + c.subY)
+	dy, collidesY := c.MoveY(c.Hitbox(), whole, ClipNone)
+	c.Pos.Y += dy
+	if collidesY.Colliding(ClipNone) {
+		c.Vel.Y, c.subY = 0, 0
+		c.Vel.X = SnapToZero(c.Vel.X*CrateFriction, 0)
+	}
+	whole, c.subX = splitSubpixel(c.Vel.X + c.subX)
+	dx, collidesX := c.MoveX(c.Hitbox(), whole, ClipNone)
+	c.Pos.X += dx
+	if collidesX.Colliding(ClipNone) {
+		c.Vel.X, c.subX = 0, 0
+	}
+}
+
+// Draw draws the crate as a placeholder rectangle, offset by the provided camera position.
+func (c *Crate) Draw(screen *ebiten.Image, camera IVec2) {
+	fill := crateColor
+	if c.Magnetic {
+		fill = metalCrateColor
+	}
+	box := c.Hitbox().Add(camera)
+	vector.DrawFilledRect(screen, float32(box.X), float32(box.Y), float32(box.W), float32(box.H), fill, false)
+	vector.StrokeRect(screen, float32(box.X), float32(box.Y), float32(box.W), float32(box.H), 1, crateOutlineColor,
+		false)
+}
+
+// loadMagnets records the location of every magnet cell in the current level.
+func (s *PlatformerScene) loadMagnets() {
+	s.magnetCells = nil
+	s.forAllGridData(func(cx, cy int, dat IntGridData) {
+		if dat == IntGridMagnet {
+			s.magnetCells = append(s.magnetCells, IVec2{X: cx, Y: cy})
+		}
+	})
+}
+
+// magnetsPowered returns true while any switch with its "magnet" field set is pressed.
+func (s *PlatformerScene) magnetsPowered() bool {
+	for _, powered := range s.magnetState {
+		if powered {
+			return true
+		}
+	}
+	return false
+}
+
+// updateMagnets records which magnet switches either player is pressing, then accelerates each magnetic crate toward the closest
+// magnet cell within MagnetRange while the magnets are powered.
+func (s *PlatformerScene) updateMagnets() {
+	for _, sw := range s.switches {
+		if sw.FieldBool("magnet") && len(s.players()) > 0 {
+			s.magnetState[sw.IID] = s.playerTouching(sw.PxBounds()) != nil
+		}
+	}
+	if !s.magnetsPowered() {
+		return
+	}
+	size := float64(s.cellSize)
+	for _, crate := range s.crates {
+		if !crate.Magnetic {
+			continue
+		}
+		center := crate.Hitbox().Center().Vec2()
+		var (
+			dir  Vec2
+			best = MagnetRange * size
+		)
+		for _, cell := range s.magnetCells {
+			toward := Vec2{X: (float64(cell.X) + 0.5) * size, Y: (float64(cell.Y) + 0.5) * size}.Sub(center)
+			if dist := toward.Mag(); dist <= best {
+				dir, best = toward.Normalize(), dist
+			}
+		}
+		crate.Vel = crate.Vel.Add(dir.Scale(MagnetForce))
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestMagnetPullsCrate(t *testing.T) {
+	rows := grid(10, 6)
+	rows[4] = "......M..."
+	s := newTestScene(t, rows...)
+	s.loadMagnets()
+	metal := &Entity{ID: EtyCrate, PxCoords: IVec2{X: 3 * testCellSize, Y: 4 * testCellSize},
+		Dim: IDim{W: testCellSize, H: testCellSize}, Fields: map[string]any{"magnetic": true}}
+	wood := &Entity{ID: EtyCrate, PxCoords: IVec2{X: 1 * testCellSize, Y: 4 * testCellSize},
+		Dim: IDim{W: testCellSize, H: testCellSize}}
+	loadTestEntities(t, s, metal, wood)
+	s.magnetState[uuid.New()] = true
+	crate, woodCrate := s.crates[0], s.crates[1]
+
+	var prevVel float64
+	for i := 0; i < 3; i++ {
+		s.updateMagnets()
+		crate.Update()
+		if crate.Vel.X <= prevVel {
+			t.Fatalf("crate velocity is %v after %d frames; want it to keep increasing from %v", crate.Vel.X, i+1, prevVel)
+		}
+		prevVel = crate.Vel.X
+	}
+	for i := 0; i < 2*int(TPS); i++ {
+		s.updateMagnets()
+		crate.Update()
+		woodCrate.Update()
+	}
+	if got, want := crate.Hitbox().X+crate.Hitbox().W, 6*testCellSize; got != want {
+		t.Errorf("crate stopped with its right edge at %d; want %d, against the magnet", got, want)
+	}
+	if crate.Vel.X != 0 {
+		t.Errorf("crate velocity is %v against the magnet; want 0", crate.Vel.X)
+	}
+	if got, want := woodCrate.Pos, wood.PxCoords; got != want {
+		t.Errorf("wooden crate moved to %v; want it to stay at %v", got, want)
+	}
+}
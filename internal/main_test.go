@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"github.com/niftysoft/2d-platformer/internal/testutil"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	code, err := testutil.RunTests(m)
+	if err != nil {
+		// the default logger discards everything, so report the failure to stderr directly.
+		slog.New(slog.NewTextHandler(os.Stderr, nil)).Error("could not run tests", "err", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+// testCellSize is the size of each cell in scenes created by newTestScene.
+const testCellSize = 16
+
+var (
+	testGame     *Game
+	testGameErr  error
+	testGameOnce sync.Once
+)
+
+// newTestGame returns the Game shared by every test. Only one Game is ever created, since its audio context may only
+// be created once per process.
+func newTestGame(t testing.TB) *Game {
+	t.Helper()
+	testGameOnce.Do(func() {
+		TPSOnce.Do(func() { TPS = 60 })
+		testGame, testGameErr = NewGame()
+	})
+	if testGameErr != nil {
+		t.Fatalf("could not create game: %v", testGameErr)
+	}
+	return testGame
+}
+
+// testCells maps the characters used by newTestScene to the IntGridData of each cell.
+var testCells = map[rune]IntGridData{
+	'.': IntGridNothing,
+	'#': IntGridDirt,
+	'H': IntGridLadder,
+	'S': IntGridStone,
+	'M': IntGridMagnet,
+	'B': IntGridBreakable,
+	'I': IntGridIce,
+	'O': IntGridBounce,
+	'-': IntGridOneWay,
+}
+
+// newTestScene returns a PlatformerScene whose level is the provided grid of cells, one string per row, using the
+// characters of testCells. The level has no neighbours and no entities; add them with loadTestEntities.
+func newTestScene(t testing.TB, rows ...string) *PlatformerScene {
+	t.Helper()
+	g := newTestGame(t)
+	s := NewPlatformerScene(g, &GameData{})
+	s.loaded = true
+	s.cellSize = testCellSize
+	var grid []IntGridData
+	for _, row := range rows {
+		if len(row) != len(rows[0]) {
+			t.Fatalf("row %q is not %d cells wide", row, len(rows[0]))
+		}
+		for _, r := range row {
+			dat, ok := testCells[r]
+			if !ok {
+				t.Fatalf("unknown cell %q in row %q", r, row)
+			}
+			grid = append(grid, dat)
+		}
+	}
+	s.SetIntGrid(grid, len(rows[0]))
+	s.level = &Level{ID: "Test", PxDims: IDim{W: len(rows[0]) * testCellSize, H: len(rows) * testCellSize}}
+	s.openEdges = make(map[IVec2]bool)
+	s.brokenCells = make(map[IVec2]bool)
+	s.retiled = make(map[*TileLayer]map[IVec2][]Tile)
+	s.minimap = NewMinimap(s.intGridData, s.cellsWide, s.cellSize)
+	loadTestEntities(t, s)
+	return s
+}
+
+// loadTestEntities adds the provided entities to the level of a scene created by newTestScene, then reloads all of
+// the level's entities as LoadLevel would.
+func loadTestEntities(t testing.TB, s *PlatformerScene, entities ...*Entity) {
+	t.Helper()
+	for _, e := range entities {
+		if e.IID == (uuid.UUID{}) {
+			e.IID = uuid.New()
+		}
+	}
+	s.level.Entities = append(s.level.Entities, entities...)
+	if err := s.loadEntities(s.level); err != nil {
+		t.Fatalf("could not load entities: %v", err)
+	}
+	if err := s.loadLights(s.level); err != nil {
+		t.Fatalf("could not load lights: %v", err)
+	}
+}
+
+// newTestPlayer spawns the player at the provided position in a scene created by newTestScene.
+func newTestPlayer(t testing.TB, s *PlatformerScene, pos IVec2) *Player {
+	t.Helper()
+	loadTestEntities(t, s, &Entity{ID: EtyPlayer, PxCoords: pos})
+	return s.player
+}
+
+// updatePlayer runs one frame of the player's state machine with the provided input held, bypassing the keyboard.
+func updatePlayer(p *Player, input PlayerInput) {
+	p.input = input
+	p.states.Update()
+	p.prevInput = input
+}
+
+// setFeet moves the player so that the bottom-center of their hitbox lies on the provided point.
+func setFeet(p *Player, pt IVec2) {
+	hb := p.Hitbox()
+	p.Pos = p.Pos.Add(pt.Sub(IVec2{X: hb.X + hb.W/2, Y: hb.Y + hb.H}))
+}
+
+// grid returns the rows of a grid of the provided width and height which is empty but for a floor of dirt along its
+// bottom row.
+func grid(w, h int) []string {
+	rows := make([]string, h)
+	for i := range rows {
+		rows[i] = strings.Repeat(".", w)
+	}
+	rows[h-1] = strings.Repeat("#", w)
+	return rows
+}
+
+// registerTestLevel adds the level of a scene created by newTestScene to the scene's game data under the provided UID,
+// so that it can be reloaded with LoadLevel.
+func registerTestLevel(s *PlatformerScene, uid UID) {
+	s.level.UID = uid
+	s.level.layers = []*TileLayer{s.collisionLayer}
+	s.level.layersByID = map[string]*TileLayer{CollisionLayerID: s.collisionLayer}
+	if s.gdat.Levels == nil {
+		s.gdat.Levels = make(map[UID]*Level)
+	}
+	s.gdat.Levels[uid] = s.level
+}
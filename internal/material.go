@@ -0,0 +1,47 @@
+package internal
+
+// A Material describes how actors interact with the surface of a cell.
+type Material struct {
+	Friction    float64 // Friction multiplies X velocity while an actor slows down on this material.
+	Restitution float64 // Restitution is the fraction of an actor's landing speed it bounces back with.
+}
+
+var (
+	DefaultMaterial = Material{Friction: Friction}                   // DefaultMaterial is used for all ordinary surfaces.
+	IceMaterial     = Material{Friction: IceFriction}                // IceMaterial is a slippery surface which is hard to stop on.
+	BounceMaterial  = Material{Friction: Friction, Restitution: 0.6} // BounceMaterial is a springy surface which bounces actors that land on it.
+)
+
+// IceFriction multiplies X velocity while an actor slows down on ice.
+const IceFriction = 0.98
+
+// minBounceSpeed is the minimum speed an actor must land with before it bounces off a material with restitution.
+const minBounceSpeed = 1
+
+// MaterialRegistry maps individual CollideMask bits to the Material used for cells with that bit set.
+type MaterialRegistry map[CollideMask]Material
+
+// NewMaterialRegistry constructs a MaterialRegistry containing the materials for all built-in cell types.
+func NewMaterialRegistry() MaterialRegistry {
+	return MaterialRegistry{
+		CollideDirt:   DefaultMaterial,
+		CollideStone:  DefaultMaterial,
+		CollideIce:    IceMaterial,
+		CollideBounce: BounceMaterial,
+	}
+}
+
+// Lookup returns the Material for the provided mask. If several registered bits are set in the mask, the material for
+// the lowest bit is used. DefaultMaterial is returned if no registered bits are set.
+func (r MaterialRegistry) Lookup(mask CollideMask) Material {
+	mask &= 0x3fffffff // unset flags.
+	for bit := CollideMask(1); bit != 0 && bit <= mask; bit <<= 1 {
+		if mask&bit == 0 {
+			continue
+		}
+		if material, ok := r[bit]; ok {
+			return material
+		}
+	}
+	return DefaultMaterial
+}
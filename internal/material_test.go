@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaterialRegistryLookup(t *testing.T) {
+	r := NewMaterialRegistry()
+	tests := []struct {
+		mask CollideMask
+		want Material
+	}{
+		{mask: CollideNone, want: DefaultMaterial},
+		{mask: CollideDirt, want: DefaultMaterial},
+		{mask: CollideIce, want: IceMaterial},
+		{mask: CollideBounce, want: BounceMaterial},
+		{mask: CollideBounce | CollidedOneWay, want: BounceMaterial},
+		{mask: CollideIce | CollideBounce, want: IceMaterial},
+	}
+	for _, tt := range tests {
+		if got := r.Lookup(tt.mask); got != tt.want {
+			t.Errorf("Lookup(%s) = %+v; want %+v", tt.mask.Describe(), got, tt.want)
+		}
+	}
+}
+
+// slideDistance returns how far the player slides after walking right across a floor made of the provided cell for the
+// provided number of frames, then releasing the controls.
+// slideDistance returns how far the player slides on a floor of the provided cell after letting go of the walk keys
+// while moving right at the provided speed. The returned states are those the player passed through while sliding.
+func slideDistance(t *testing.T, floor rune, speed float64) (int, map[PlayerState]bool) {
+	s := newTestScene(t,
+		"..............................",
+		"..............................",
+		"..............................",
+		"..............................",
+		"SSSSSSSSSSSSSSSSSSSSSSSSSSSSSS",
+	)
+	for i := range s.intGridData[4*s.cellsWide:] {
+		s.intGridData[4*s.cellsWide+i] = testCells[floor]
+	}
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 2 * testCellSize, Y: 4 * testCellSize})
+	p.states.Set(PlayerStateWalking)
+	p.Vel.X = speed
+
+	start := p.Pos.X
+	states := make(map[PlayerState]bool)
+	for i := 0; i < 300 && p.state() != PlayerStateIdle; i++ {
+		updatePlayer(p, 0)
+		states[p.state()] = true
+	}
+	if p.state() != PlayerStateIdle {
+		t.Fatalf("player on %q is still moving after 300 frames; state is %v", floor, p.state())
+	}
+	return p.Pos.X - start, states
+}
+
+func TestIceSlidesFurther(t *testing.T) {
+	for _, floor := range []rune{'#', 'S'} {
+		other, _ := slideDistance(t, floor, PlayerMaxWalkSpeed)
+		ice, states := slideDistance(t, 'I', PlayerMaxWalkSpeed)
+		if ice <= other {
+			t.Errorf("player slid %d pixels on ice and %d on %q from the same speed; want ice to slide further", ice,
+				other, floor)
+		}
+		if !states[PlayerStateSkidding] {
+			t.Errorf("player passed through states %v sliding on ice; want %v among them", states, PlayerStateSkidding)
+		}
+	}
+}
+
+func TestBounceReflectsVelocity(t *testing.T) {
+	s := newTestScene(t,
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"..........",
+		"OOOOOOOOOO",
+	)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 2 * testCellSize})
+	p.states.Set(p.startFalling(PlayerMaxWalkSpeed))
+	p.Vel.Y = 4
+	for i := 0; i < 60; i++ {
+		before := p.Vel.Y
+		updatePlayer(p, 0)
+		if p.Vel.Y >= 0 {
+			continue
+		}
+		landing := min(before+Gravity/TPS, p.effectiveTerminalVelocity)
+		if want := -landing * BounceMaterial.Restitution; math.Abs(p.Vel.Y-want) > vecTolerance {
+			t.Errorf("Vel.Y = %v after landing at %v; want %v", p.Vel.Y, landing, want)
+		}
+		if p.state() != PlayerStateFalling {
+			t.Errorf("state = %v after bouncing; want %v", p.state(), PlayerStateFalling)
+		}
+		return
+	}
+	t.Fatalf("player did not bounce; Vel.Y = %v, state = %v", p.Vel.Y, p.state())
+}
@@ -24,6 +24,37 @@ func (r IRect) Add(pos IVec2) IRect {
 	return IRect{X: r.X + pos.X, Y: r.Y + pos.Y, W: r.W, H: r.H}
 }
 
+// Overlaps returns true if this rectangle and other share any area. Rectangles which only touch along an edge do not
+// overlap.
+func (r IRect) Overlaps(other IRect) bool {
+	return r.X < other.X+other.W && other.X < r.X+r.W && r.Y < other.Y+other.H && other.Y < r.Y+r.H
+}
+
+// Contains returns true if the provided point lies within this rectangle. Points on the right and bottom edges are not
+// contained.
+func (r IRect) Contains(pt IVec2) bool {
+	return r.X <= pt.X && pt.X < r.X+r.W && r.Y <= pt.Y && pt.Y < r.Y+r.H
+}
+
+// Intersection returns the region shared by this rectangle and other. If the rectangles do not overlap, false is
+// returned.
+func (r IRect) Intersection(other IRect) (IRect, bool) {
+	if !r.Overlaps(other) {
+		return IRect{}, false
+	}
+	x1, y1 := max(r.X, other.X), max(r.Y, other.Y)
+	x2, y2 := min(r.X+r.W, other.X+other.W), min(r.Y+r.H, other.Y+other.H)
+	return IRect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}, true
+}
+
+// Center returns the center of this rectangle, rounded toward the upper-left.
+func (r IRect) Center() IVec2 { return IVec2{X: r.X + r.W/2, Y: r.Y + r.H/2} }
+
+// Expand grows each edge of this rectangle outward by n. Negative values shrink the rectangle.
+func (r IRect) Expand(n int) IRect {
+	return IRect{X: r.X - n, Y: r.Y - n, W: r.W + 2*n, H: r.H + 2*n}
+}
+
 // Rect is a floating-point rectangle.
 type Rect struct {
 	X, Y, W, H float64
@@ -35,6 +66,37 @@ func (r Rect) Dim() Dim { return Dim{W: r.W, H: r.H} }
 // Vec2 returns the upper-left coordinate of this rectangle.
 func (r Rect) Vec2() Vec2 { return Vec2{X: r.X, Y: r.Y} }
 
+// Overlaps returns true if this rectangle and other share any area. Rectangles which only touch along an edge do not
+// overlap.
+func (r Rect) Overlaps(other Rect) bool {
+	return r.X < other.X+other.W && other.X < r.X+r.W && r.Y < other.Y+other.H && other.Y < r.Y+r.H
+}
+
+// Contains returns true if the provided point lies within this rectangle. Points on the right and bottom edges are not
+// contained.
+func (r Rect) Contains(pt Vec2) bool {
+	return r.X <= pt.X && pt.X < r.X+r.W && r.Y <= pt.Y && pt.Y < r.Y+r.H
+}
+
+// Intersection returns the region shared by this rectangle and other. If the rectangles do not overlap, false is
+// returned.
+func (r Rect) Intersection(other Rect) (Rect, bool) {
+	if !r.Overlaps(other) {
+		return Rect{}, false
+	}
+	x1, y1 := max(r.X, other.X), max(r.Y, other.Y)
+	x2, y2 := min(r.X+r.W, other.X+other.W), min(r.Y+r.H, other.Y+other.H)
+	return Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}, true
+}
+
+// Center returns the center of this rectangle.
+func (r Rect) Center() Vec2 { return Vec2{X: r.X + r.W/2, Y: r.Y + r.H/2} }
+
+// Expand grows each edge of this rectangle outward by n. Negative values shrink the rectangle.
+func (r Rect) Expand(n float64) Rect {
+	return Rect{X: r.X - n, Y: r.Y - n, W: r.W + 2*n, H: r.H + 2*n}
+}
+
 // IDim is integer width and height.
 type IDim struct{ W, H int }
 
@@ -48,6 +110,25 @@ func (v IVec2) Vec2() Vec2 { return Vec2{X: float64(v.X), Y: float64(v.Y)} }
 
 func (v IVec2) Scale(a int) IVec2 { return IVec2{X: v.X * a, Y: v.Y * a} }
 
+// Add returns the sum of this vector and other.
+func (v IVec2) Add(other IVec2) IVec2 { return IVec2{X: v.X + other.X, Y: v.Y + other.Y} }
+
+// Sub returns the difference of this vector and other.
+func (v IVec2) Sub(other IVec2) IVec2 { return IVec2{X: v.X - other.X, Y: v.Y - other.Y} }
+
+// CardinalDir returns the unit cardinal vector closest to the direction of this vector. Ties between the X and Y axes
+// favor the X axis. The zero vector is returned unchanged.
+func (v IVec2) CardinalDir() IVec2 {
+	switch {
+	case v.X == 0 && v.Y == 0:
+		return IVec2{}
+	case abs(v.X) >= abs(v.Y):
+		return IVec2{X: sign(v.X)}
+	default:
+		return IVec2{Y: sign(v.Y)}
+	}
+}
+
 // Vec2 is floating-point 2D-coordinates.
 type Vec2 struct{ X, Y float64 }
 
@@ -55,3 +136,46 @@ type Vec2 struct{ X, Y float64 }
 func (v Vec2) Mag() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y)
 }
+
+// Normalize returns a unit vector pointing in the same direction as this vector. The zero vector is returned unchanged.
+func (v Vec2) Normalize() Vec2 {
+	mag := v.Mag()
+	if mag == 0 {
+		return Vec2{}
+	}
+	return Vec2{X: v.X / mag, Y: v.Y / mag}
+}
+
+// Dot returns the dot product of this vector with other.
+func (v Vec2) Dot(other Vec2) float64 { return v.X*other.X + v.Y*other.Y }
+
+// Scale returns this vector multiplied by the scalar f.
+func (v Vec2) Scale(f float64) Vec2 { return Vec2{X: v.X * f, Y: v.Y * f} }
+
+// Add returns the sum of this vector and other.
+func (v Vec2) Add(other Vec2) Vec2 { return Vec2{X: v.X + other.X, Y: v.Y + other.Y} }
+
+// Sub returns the difference of this vector and other.
+func (v Vec2) Sub(other Vec2) Vec2 { return Vec2{X: v.X - other.X, Y: v.Y - other.Y} }
+
+// Angle returns the angle of this vector in radians, measured from the positive X axis and wrapped to [0, 2π).
+func (v Vec2) Angle() float64 {
+	result := math.Atan2(v.Y, v.X)
+	if result < 0 {
+		result += 2 * math.Pi
+	}
+	return result
+}
+
+// Vec2FromAngle returns a vector with the provided angle, in radians from the positive X axis, and magnitude.
+func Vec2FromAngle(angle, magnitude float64) Vec2 {
+	return Vec2{X: magnitude * math.Cos(angle), Y: magnitude * math.Sin(angle)}
+}
+
+// IVec2 returns this vector rounded to the nearest whole pixel.
+func (v Vec2) IVec2() IVec2 { return IVec2{X: int(math.Round(v.X)), Y: int(math.Round(v.Y))} }
+
+// Lerp linearly interpolates between this vector and other. t = 0 returns this vector and t = 1 returns other.
+func (v Vec2) Lerp(other Vec2, t float64) Vec2 {
+	return Vec2{X: v.X + (other.X-v.X)*t, Y: v.Y + (other.Y-v.Y)*t}
+}
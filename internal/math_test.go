@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+// vecTolerance is the largest difference allowed between floating-point results and their expected values.
+const vecTolerance = 1e-9
+
+// vecNear returns true if each component of a and b differs by at most vecTolerance.
+func vecNear(a, b Vec2) bool {
+	return math.Abs(a.X-b.X) <= vecTolerance && math.Abs(a.Y-b.Y) <= vecTolerance
+}
+
+func TestVec2Normalize(t *testing.T) {
+	tests := []struct {
+		v    Vec2
+		want Vec2
+	}{
+		{v: Vec2{}, want: Vec2{}},
+		{v: Vec2{X: 3, Y: 4}, want: Vec2{X: 0.6, Y: 0.8}},
+		{v: Vec2{X: -5}, want: Vec2{X: -1}},
+		{v: Vec2{Y: 1e-3}, want: Vec2{Y: 1}},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Normalize(); !vecNear(got, tt.want) {
+			t.Errorf("%v.Normalize() = %v; want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVec2Dot(t *testing.T) {
+	tests := []struct {
+		v, other Vec2
+		want     float64
+	}{
+		{v: Vec2{}, other: Vec2{X: 3, Y: 4}, want: 0},
+		{v: Vec2{X: 1, Y: 2}, other: Vec2{X: 3, Y: 4}, want: 11},
+		{v: Vec2{X: 1}, other: Vec2{Y: 1}, want: 0},
+		{v: Vec2{X: -2, Y: 1}, other: Vec2{X: 3, Y: -1}, want: -7},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Dot(tt.other); got != tt.want {
+			t.Errorf("%v.Dot(%v) = %v; want %v", tt.v, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestVec2Scale(t *testing.T) {
+	tests := []struct {
+		v    Vec2
+		f    float64
+		want Vec2
+	}{
+		{v: Vec2{}, f: 5, want: Vec2{}},
+		{v: Vec2{X: 1, Y: -2}, f: 0, want: Vec2{}},
+		{v: Vec2{X: 1, Y: -2}, f: 3, want: Vec2{X: 3, Y: -6}},
+		{v: Vec2{X: 4, Y: 8}, f: -0.5, want: Vec2{X: -2, Y: -4}},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Scale(tt.f); got != tt.want {
+			t.Errorf("%v.Scale(%v) = %v; want %v", tt.v, tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestVec2AddSub(t *testing.T) {
+	tests := []struct {
+		v, other Vec2
+		sum      Vec2
+		diff     Vec2
+	}{
+		{v: Vec2{}, other: Vec2{}, sum: Vec2{}, diff: Vec2{}},
+		{v: Vec2{X: 1, Y: 2}, other: Vec2{}, sum: Vec2{X: 1, Y: 2}, diff: Vec2{X: 1, Y: 2}},
+		{v: Vec2{X: 1, Y: 2}, other: Vec2{X: 3, Y: -4}, sum: Vec2{X: 4, Y: -2}, diff: Vec2{X: -2, Y: 6}},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Add(tt.other); got != tt.sum {
+			t.Errorf("%v.Add(%v) = %v; want %v", tt.v, tt.other, got, tt.sum)
+		}
+		if got := tt.v.Sub(tt.other); got != tt.diff {
+			t.Errorf("%v.Sub(%v) = %v; want %v", tt.v, tt.other, got, tt.diff)
+		}
+	}
+}
+
+func TestIVec2AddSub(t *testing.T) {
+	tests := []struct {
+		v, other IVec2
+		sum      IVec2
+		diff     IVec2
+	}{
+		{v: IVec2{}, other: IVec2{}, sum: IVec2{}, diff: IVec2{}},
+		{v: IVec2{}, other: IVec2{X: 5, Y: -3}, sum: IVec2{X: 5, Y: -3}, diff: IVec2{X: -5, Y: 3}},
+		{v: IVec2{X: 2, Y: 7}, other: IVec2{X: 1, Y: 9}, sum: IVec2{X: 3, Y: 16}, diff: IVec2{X: 1, Y: -2}},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Add(tt.other); got != tt.sum {
+			t.Errorf("%v.Add(%v) = %v; want %v", tt.v, tt.other, got, tt.sum)
+		}
+		if got := tt.v.Sub(tt.other); got != tt.diff {
+			t.Errorf("%v.Sub(%v) = %v; want %v", tt.v, tt.other, got, tt.diff)
+		}
+	}
+}
+
+func TestIRectOverlaps(t *testing.T) {
+	r := IRect{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name  string
+		other IRect
+		want  bool
+	}{
+		{name: "touching right edge", other: IRect{X: 10, Y: 0, W: 5, H: 5}, want: false},
+		{name: "touching bottom edge", other: IRect{X: 0, Y: 10, W: 5, H: 5}, want: false},
+		{name: "touching corner", other: IRect{X: 10, Y: 10, W: 5, H: 5}, want: false},
+		{name: "contained", other: IRect{X: 2, Y: 2, W: 3, H: 3}, want: true},
+		{name: "containing", other: IRect{X: -5, Y: -5, W: 20, H: 20}, want: true},
+		{name: "partial", other: IRect{X: 8, Y: -2, W: 5, H: 5}, want: true},
+		{name: "disjoint", other: IRect{X: 20, Y: 20, W: 5, H: 5}, want: false},
+	}
+	for _, tt := range tests {
+		if got := r.Overlaps(tt.other); got != tt.want {
+			t.Errorf("%s: Overlaps(%v) = %v; want %v", tt.name, tt.other, got, tt.want)
+		}
+		if got := tt.other.Overlaps(r); got != tt.want {
+			t.Errorf("%s: Overlaps is not symmetric for %v", tt.name, tt.other)
+		}
+	}
+}
+
+func TestIRectContains(t *testing.T) {
+	r := IRect{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		pt   IVec2
+		want bool
+	}{
+		{pt: IVec2{}, want: true},
+		{pt: IVec2{X: 9, Y: 9}, want: true},
+		{pt: IVec2{X: 10, Y: 5}, want: false},
+		{pt: IVec2{X: 5, Y: 10}, want: false},
+		{pt: IVec2{X: -1, Y: 5}, want: false},
+	}
+	for _, tt := range tests {
+		if got := r.Contains(tt.pt); got != tt.want {
+			t.Errorf("Contains(%v) = %v; want %v", tt.pt, got, tt.want)
+		}
+	}
+}
+
+func TestIRectIntersection(t *testing.T) {
+	r := IRect{X: 0, Y: 0, W: 10, H: 10}
+	tests := []struct {
+		name   string
+		other  IRect
+		want   IRect
+		wantOK bool
+	}{
+		{name: "touching edge", other: IRect{X: 10, Y: 0, W: 5, H: 5}},
+		{name: "contained", other: IRect{X: 2, Y: 3, W: 4, H: 5}, want: IRect{X: 2, Y: 3, W: 4, H: 5}, wantOK: true},
+		{name: "partial", other: IRect{X: 8, Y: -2, W: 5, H: 5}, want: IRect{X: 8, Y: 0, W: 2, H: 3}, wantOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := r.Intersection(tt.other)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: Intersection(%v) = %v, %v; want %v, %v", tt.name, tt.other, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIRectCenterExpand(t *testing.T) {
+	r := IRect{X: 2, Y: 4, W: 5, H: 6}
+	if got, want := r.Center(), (IVec2{X: 4, Y: 7}); got != want {
+		t.Errorf("Center() = %v; want %v", got, want)
+	}
+	if got, want := r.Expand(2), (IRect{X: 0, Y: 2, W: 9, H: 10}); got != want {
+		t.Errorf("Expand(2) = %v; want %v", got, want)
+	}
+	if got, want := r.Expand(-1), (IRect{X: 3, Y: 5, W: 3, H: 4}); got != want {
+		t.Errorf("Expand(-1) = %v; want %v", got, want)
+	}
+}
+
+func TestRectOverlapsIntersection(t *testing.T) {
+	r := Rect{X: 0, Y: 0, W: 1, H: 1}
+	if r.Overlaps(Rect{X: 1, Y: 0, W: 1, H: 1}) {
+		t.Error("rects touching along an edge overlap")
+	}
+	if !r.Contains(Vec2{X: 0.5, Y: 0.999}) || r.Contains(Vec2{X: 1, Y: 0.5}) {
+		t.Error("Contains does not include only the upper-left edges")
+	}
+	got, ok := r.Intersection(Rect{X: 0.5, Y: 0.25, W: 1, H: 0.5})
+	if want := (Rect{X: 0.5, Y: 0.25, W: 0.5, H: 0.5}); !ok || got != want {
+		t.Errorf("Intersection = %v, %v; want %v, true", got, ok, want)
+	}
+	if got, want := r.Center(), (Vec2{X: 0.5, Y: 0.5}); got != want {
+		t.Errorf("Center() = %v; want %v", got, want)
+	}
+	if got, want := r.Expand(0.5), (Rect{X: -0.5, Y: -0.5, W: 2, H: 2}); got != want {
+		t.Errorf("Expand(0.5) = %v; want %v", got, want)
+	}
+}
+
+func TestVec2Angle(t *testing.T) {
+	tests := []struct {
+		v    Vec2
+		want float64
+	}{
+		{v: Vec2{X: 1}, want: 0},
+		{v: Vec2{X: 1, Y: 1}, want: math.Pi / 4},
+		{v: Vec2{Y: 1}, want: math.Pi / 2},
+		{v: Vec2{X: -1, Y: 1}, want: 3 * math.Pi / 4},
+		{v: Vec2{X: -1}, want: math.Pi},
+		{v: Vec2{X: -1, Y: -1}, want: 5 * math.Pi / 4},
+		{v: Vec2{Y: -1}, want: 3 * math.Pi / 2},
+		{v: Vec2{X: 1, Y: -1}, want: 7 * math.Pi / 4},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Angle(); math.Abs(got-tt.want) > vecTolerance {
+			t.Errorf("%v.Angle() = %v; want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVec2FromAngle(t *testing.T) {
+	tests := []struct {
+		angle, mag float64
+		want       Vec2
+	}{
+		{angle: 0, mag: 2, want: Vec2{X: 2}},
+		{angle: math.Pi / 2, mag: 3, want: Vec2{Y: 3}},
+		{angle: math.Pi, mag: 1, want: Vec2{X: -1}},
+		{angle: math.Pi / 4, mag: 0, want: Vec2{}},
+	}
+	for _, tt := range tests {
+		if got := Vec2FromAngle(tt.angle, tt.mag); !vecNear(got, tt.want) {
+			t.Errorf("Vec2FromAngle(%v, %v) = %v; want %v", tt.angle, tt.mag, got, tt.want)
+		}
+	}
+}
+
+func TestIVec2CardinalDir(t *testing.T) {
+	tests := []struct {
+		v, want IVec2
+	}{
+		{v: IVec2{}, want: IVec2{}},
+		{v: IVec2{X: 5, Y: 2}, want: IVec2{X: 1}},
+		{v: IVec2{X: -1, Y: 7}, want: IVec2{Y: 1}},
+		{v: IVec2{X: -3, Y: -3}, want: IVec2{X: -1}},
+		{v: IVec2{Y: -2}, want: IVec2{Y: -1}},
+	}
+	for _, tt := range tests {
+		if got := tt.v.CardinalDir(); got != tt.want {
+			t.Errorf("%v.CardinalDir() = %v; want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVec2Lerp(t *testing.T) {
+	a, b := Vec2{X: 0, Y: 10}, Vec2{X: 10, Y: -10}
+	tests := []struct {
+		t    float64
+		want Vec2
+	}{
+		{t: 0, want: a},
+		{t: 0.5, want: Vec2{X: 5, Y: 0}},
+		{t: 1, want: b},
+	}
+	for _, tt := range tests {
+		if got := a.Lerp(b, tt.t); !vecNear(got, tt.want) {
+			t.Errorf("Lerp(%v, %v, %v) = %v; want %v", a, b, tt.t, got, tt.want)
+		}
+	}
+}
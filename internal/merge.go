@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/niftysoft/2d-platformer/internal/ldtk"
+)
+
+// MergeGameData combines the tilesets, levels, and definitions of the provided GameData into a new GameData. Returns an
+// error if both define a tileset, level, or auto-layer with the same UID, a level with the same ID, or map the same
+// IntGrid value to different cell types. The LDtk project of a is kept as the project written by WriteLdtk; neither
+// input is modified.
+//
+// The result is neither validated nor indexed by world position; LoadGameData does both after merging every file.
+func MergeGameData(a, b *GameData) (*GameData, error) {
+	result := &GameData{
+		json:       a.json,
+		merged:     append(append([]*ldtk.LdtkJSON{}, a.merged...), b.json),
+		LevelStart: a.LevelStart,
+	}
+	if result.LevelStart == -1 {
+		result.LevelStart = b.LevelStart
+	}
+	var err error
+	if result.Tilesets, err = mergeMaps(a.Tilesets, b.Tilesets, "tileset"); err != nil {
+		return nil, err
+	}
+	if result.TilesetMeta, err = mergeMaps(a.TilesetMeta, b.TilesetMeta, "tileset"); err != nil {
+		return nil, err
+	}
+	if result.Levels, err = mergeMaps(a.Levels, b.Levels, "level"); err != nil {
+		return nil, err
+	}
+	if result.LevelsByID, err = mergeMaps(a.LevelsByID, b.LevelsByID, "level"); err != nil {
+		return nil, err
+	}
+	if result.AutoTilers, err = mergeMaps(a.AutoTilers, b.AutoTilers, "auto-layer"); err != nil {
+		return nil, err
+	}
+	result.IntGridMasks = make(map[int]CollideMask, len(a.IntGridMasks)+len(b.IntGridMasks))
+	for value, mask := range a.IntGridMasks {
+		result.IntGridMasks[value] = mask
+	}
+	for value, mask := range b.IntGridMasks {
+		if prev, ok := result.IntGridMasks[value]; ok && prev != mask {
+			return nil, fmt.Errorf("IntGrid value %d is both %s and %s", value, prev.Describe(), mask.Describe())
+		}
+		result.IntGridMasks[value] = mask
+	}
+	result.Enums = make(map[string][]LdtkEnum, len(a.Enums)+len(b.Enums))
+	for name, values := range a.Enums {
+		result.Enums[name] = append([]LdtkEnum{}, values...)
+	}
+	for name, values := range b.Enums {
+		for _, value := range values {
+			if !containsEnum(result.Enums[name], value) {
+				result.Enums[name] = append(result.Enums[name], value)
+			}
+		}
+	}
+	return result, nil
+}
+
+// mergeMaps returns a new map holding every entry of a and b, or an error naming the kind of entry if both hold the
+// same key.
+func mergeMaps[K comparable, V any](a, b map[K]V, kind string) (map[K]V, error) {
+	result := make(map[K]V, len(a)+len(b))
+	for k, v := range a {
+		result[k] = v
+	}
+	for k, v := range b {
+		if _, ok := result[k]; ok {
+			return nil, fmt.Errorf("duplicate %s: %v", kind, k)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// containsEnum returns true if values contains value.
+func containsEnum(values []LdtkEnum, value LdtkEnum) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"testing"
+)
+
+// testMergeData returns GameData holding a single level with the provided UID and ID.
+func testMergeData(uid UID, id string) *GameData {
+	level := &Level{UID: uid, ID: id}
+	return &GameData{
+		LevelStart: -1,
+		Levels:     map[UID]*Level{uid: level},
+		LevelsByID: map[string]*Level{id: level},
+	}
+}
+
+func TestMergeGameData(t *testing.T) {
+	a, b := testMergeData(1, "Cave"), testMergeData(2, "Forest")
+	b.LevelStart = 2
+	merged, err := MergeGameData(a, b)
+	if err != nil {
+		t.Fatalf("MergeGameData() error = %v", err)
+	}
+	for uid, id := range map[UID]string{1: "Cave", 2: "Forest"} {
+		if level := merged.Levels[uid]; level == nil || level.ID != id {
+			t.Errorf("merged Levels[%d] = %v; want level %s", uid, level, id)
+		}
+		if level := merged.LevelsByID[id]; level == nil || level.UID != uid {
+			t.Errorf("merged LevelsByID[%q] = %v; want level %d", id, level, uid)
+		}
+	}
+	if merged.LevelStart != 2 {
+		t.Errorf("merged LevelStart = %d; want 2, taken from the only file with a player", merged.LevelStart)
+	}
+}
+
+func TestMergeGameDataConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *GameData
+	}{
+		{name: "duplicate UID", a: testMergeData(1, "Cave"), b: testMergeData(1, "Forest")},
+		{name: "duplicate ID", a: testMergeData(1, "Cave"), b: testMergeData(2, "Cave")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MergeGameData(tt.a, tt.b); err == nil {
+				t.Errorf("MergeGameData() succeeded; want an error")
+			}
+		})
+	}
+}
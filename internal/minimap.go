@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+const (
+	MinimapMaxW    = 64  // MinimapMaxW is the maximum width of the minimap on screen, in pixels.
+	MinimapMaxH    = 48  // MinimapMaxH is the maximum height of the minimap on screen, in pixels.
+	minimapOpacity = 0.5 // minimapOpacity is the opacity used when drawing the minimap.
+)
+
+// Minimap is a scaled-down view of the current level's IntGrid, with one pixel per cell.
+type Minimap struct {
+	img       *ebiten.Image
+	dot       *ebiten.Image
+	scale     float64 // scale is the factor by which img is scaled to fit within MinimapMaxW x MinimapMaxH.
+	cellSize  int
+	playerPos IVec2 // playerPos is the player's position in level coordinates.
+}
+
+// NewMinimap constructs a Minimap from the provided grid data.
+func NewMinimap(grid []IntGridData, cellsWide, cellSize int) *Minimap {
+	cellsHigh := len(grid) / cellsWide
+	result := &Minimap{
+		img:      ebiten.NewImage(cellsWide, cellsHigh),
+		dot:      ebiten.NewImage(2, 2),
+		scale:    min(float64(MinimapMaxW)/float64(cellsWide), float64(MinimapMaxH)/float64(cellsHigh)),
+		cellSize: cellSize,
+	}
+	result.dot.Fill(color.White)
+	for i, dat := range grid {
+		result.img.Set(i%cellsWide, i/cellsWide, minimapColor(dat))
+	}
+	return result
+}
+
+// SetPlayerPos sets the position of the player, in level coordinates.
+func (m *Minimap) SetPlayerPos(pos IVec2) {
+	m.playerPos = pos
+}
+
+// DrawTo draws the minimap to screen with its upper-right corner at (right, top).
+func (m *Minimap) DrawTo(screen *ebiten.Image, right, top int) {
+	w := float64(m.img.Bounds().Dx()) * m.scale
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(m.scale, m.scale)
+	opts.GeoM.Translate(float64(right)-w, float64(top))
+	opts.ColorScale.ScaleAlpha(minimapOpacity)
+	screen.DrawImage(m.img, &opts)
+
+	// draw player dot
+	dotX := float64(right) - w + float64(m.playerPos.X)/float64(m.cellSize)*m.scale - 1
+	dotY := float64(top) + float64(m.playerPos.Y)/float64(m.cellSize)*m.scale - 1
+	opts = ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(dotX, dotY)
+	screen.DrawImage(m.dot, &opts)
+}
+
+// minimapColor returns the color used to draw a cell with the provided data on the minimap.
+func minimapColor(dat IntGridData) color.Color {
+	if dat.isLadder() {
+		return colornames.Yellow
+	}
+	if dat.isOneWay() { // one-way platforms are drawn lighter, since they can be jumped through.
+		return colornames.Burlywood
+	}
+	switch dat & 0x3fffffff { // unset flags.
+	case IntGridStone:
+		return colornames.Gray
+	case IntGridDirt:
+		return colornames.Saddlebrown
+	case IntGridBreakable:
+		return colornames.Sandybrown
+	case IntGridMagnet:
+		return colornames.Steelblue
+	case IntGridIce:
+		return colornames.Lightcyan
+	case IntGridBounce:
+		return colornames.Limegreen
+	}
+	return color.Transparent
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"golang.org/x/image/colornames"
+	"image/color"
+	"testing"
+)
+
+func TestMinimapColor(t *testing.T) {
+	tests := []struct {
+		dat  IntGridData
+		want color.Color
+	}{
+		{dat: IntGridNothing, want: color.Transparent},
+		{dat: IntGridDirt, want: colornames.Saddlebrown},
+		{dat: IntGridDirt | IntGridOneWay, want: colornames.Burlywood},
+		{dat: IntGridLadder, want: colornames.Yellow},
+		{dat: IntGridLadderTop, want: colornames.Yellow},
+		{dat: IntGridLadderBottom, want: colornames.Yellow},
+		{dat: IntGridStone, want: colornames.Gray},
+		{dat: IntGridMagnet, want: colornames.Steelblue},
+		{dat: IntGridBreakable, want: colornames.Sandybrown},
+		{dat: IntGridIce, want: colornames.Lightcyan},
+		{dat: IntGridBounce, want: colornames.Limegreen},
+	}
+	for _, tt := range tests {
+		if got := minimapColor(tt.dat); got != tt.want {
+			t.Errorf("minimapColor(%s) = %v; want %v", tt.dat.Describe(), got, tt.want)
+		}
+	}
+}
+
+func TestMinimapPixels(t *testing.T) {
+	grid := []IntGridData{IntGridNothing, IntGridStone, IntGridLadder, IntGridIce}
+	m := NewMinimap(grid, 2, testCellSize)
+	for i, dat := range grid {
+		r, g, b, a := m.img.At(i%2, i/2).RGBA()
+		wr, wg, wb, wa := minimapColor(dat).RGBA()
+		if r>>8 != wr>>8 || g>>8 != wg>>8 || b>>8 != wb>>8 || a>>8 != wa>>8 {
+			t.Errorf("pixel for %s is %v; want %v", dat.Describe(), m.img.At(i%2, i/2), minimapColor(dat))
+		}
+	}
+}
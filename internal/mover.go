@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Mover is any actor which moves through a PlatformerScene under its own control, such as the player, enemies, and
+// crates. Systems which act on every moving actor should range over PlatformerScene.movers rather than over each
+// concrete type.
+type Mover interface {
+	// Update updates the mover this frame.
+	Update()
+	// Draw draws the mover to screen, offset by the provided camera position.
+	Draw(screen *ebiten.Image, camera IVec2)
+	// Hitbox returns the mover's hitbox in pixel coordinates.
+	Hitbox() IRect
+}
+
+// AddMover registers the provided mover to be updated and drawn each frame.
+func (s *PlatformerScene) AddMover(m Mover) {
+	s.movers = append(s.movers, m)
+}
+
+// RemoveMover stops updating and drawing the provided mover.
+func (s *PlatformerScene) RemoveMover(m Mover) {
+	for i, other := range s.movers {
+		if other == m {
+			s.movers = append(s.movers[:i], s.movers[i+1:]...)
+			return
+		}
+	}
+}
+
+// updateMovers updates every registered mover in the order they were registered.
+func (s *PlatformerScene) updateMovers() {
+	for _, m := range s.movers {
+		m.Update()
+	}
+}
+
+// drawMovers draws every registered mover in the order they were registered.
+func (s *PlatformerScene) drawMovers(screen *ebiten.Image) {
+	for _, m := range s.movers {
+		m.Draw(screen, s.camera.Offset().IVec2())
+	}
+}
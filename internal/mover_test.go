@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"testing"
+)
+
+// countingMover is a Mover which counts how many times it was updated.
+type countingMover struct {
+	updates int
+}
+
+func (m *countingMover) Update()                       { m.updates++ }
+func (m *countingMover) Draw(_ *ebiten.Image, _ IVec2) {}
+func (m *countingMover) Hitbox() IRect                 { return IRect{} }
+
+func TestMoverUpdatedOncePerFrame(t *testing.T) {
+	s := newTestScene(t, grid(8, 4)...)
+	newTestPlayer(t, s, IVec2{X: 16, Y: 16})
+	m := &countingMover{}
+	s.AddMover(m)
+
+	const frames = 3
+	for i := 0; i < frames; i++ {
+		if err := s.Update(); err != nil {
+			t.Fatalf("Update() = %v", err)
+		}
+	}
+	if m.updates != frames {
+		t.Errorf("mover was updated %d times in %d frames; want %d", m.updates, frames, frames)
+	}
+
+	s.RemoveMover(m)
+	if err := s.Update(); err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if m.updates != frames {
+		t.Errorf("mover was updated %d times after being removed; want it to stay at %d", m.updates, frames)
+	}
+}
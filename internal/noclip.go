@@ -0,0 +1,6 @@
+//go:build !debug
+
+package internal
+
+// updateNoclip is a no-op outside of debug builds.
+func (s *PlatformerScene) updateNoclip() {}
@@ -0,0 +1,27 @@
+//go:build debug
+
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"log/slog"
+)
+
+// updateNoclip toggles noclip on the player when F4 is pressed. While noclip is enabled the player flies freely through
+// walls; when it is disabled the player falls from wherever they were left.
+func (s *PlatformerScene) updateNoclip() {
+	if s.player == nil || !inpututil.IsKeyJustPressed(ebiten.KeyF4) {
+		return
+	}
+	p := s.player
+	p.noclip = !p.noclip
+	p.Vel = Vec2{}
+	p.resetSubpixels()
+	slog.Debug("noclip toggled", "enabled", p.noclip)
+	if p.noclip {
+		p.states.Set(PlayerStateFlying)
+		return
+	}
+	p.states.Set(p.startFalling(PlayerMaxWalkSpeed))
+}
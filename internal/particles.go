@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// Particle is a purely visual speck which moves in world coordinates, ignoring collisions.
+type Particle struct {
+	Pos, Vel      Vec2
+	Life, MaxLife int // Life is the number of frames remaining before the particle disappears.
+	Color         color.RGBA
+}
+
+// ParticleSystem updates and draws particles in world coordinates.
+type ParticleSystem struct {
+	particles []Particle
+	rng       *rand.Rand // rng chooses the direction and speed of each particle.
+}
+
+// NewParticleSystem constructs an empty ParticleSystem which draws randomness from the provided source.
+func NewParticleSystem(rng *rand.Rand) *ParticleSystem {
+	return &ParticleSystem{rng: rng}
+}
+
+// Burst spawns n particles at pos, moving outward in random directions at up to the provided speed.
+func (ps *ParticleSystem) Burst(pos Vec2, n int, speed float64, life int, c color.RGBA) {
+	for i := 0; i < n; i++ {
+		ps.particles = append(ps.particles, Particle{
+			Pos:     pos,
+			Vel:     Vec2FromAngle(ps.rng.Float64()*2*math.Pi, speed*(0.5+ps.rng.Float64()/2)),
+			Life:    life,
+			MaxLife: life,
+			Color:   c,
+		})
+	}
+}
+
+// Update moves each particle and removes those which have expired.
+func (ps *ParticleSystem) Update() {
+	remaining := ps.particles[:0]
+	for _, p := range ps.particles {
+		p.Life--
+		if p.Life <= 0 {
+			continue
+		}
+		p.Pos = p.Pos.Add(p.Vel)
+		remaining = append(remaining, p)
+	}
+	ps.particles = remaining
+}
+
+// Draw draws each particle to screen, offset by the provided camera position. Particles fade out as they expire.
+func (ps *ParticleSystem) Draw(screen *ebiten.Image, camera IVec2) {
+	for _, p := range ps.particles {
+		c := fadeRGBA(p.Color, float32(p.Life)/float32(p.MaxLife))
+		x, y := float32(p.Pos.X)+float32(camera.X), float32(p.Pos.Y)+float32(camera.Y)
+		vector.DrawFilledRect(screen, x, y, 1, 1, c, false)
+	}
+}
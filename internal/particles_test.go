@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestParticleFades(t *testing.T) {
+	ps := NewParticleSystem(rand.New(rand.NewSource(1)))
+	ps.Burst(Vec2{X: 2, Y: 2}, 1, 0, 4, color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+	ps.particles[0].Life = 2
+	screen := ebiten.NewImage(4, 4)
+	ps.Draw(screen, IVec2{})
+
+	want := color.RGBA{R: 0x7f, G: 0x40, A: 0x7f}
+	if got := color.RGBAModel.Convert(screen.At(2, 2)).(color.RGBA); got != want {
+		t.Errorf("pixel of a particle at half life = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+// Package pathfind finds paths through grids of cells.
+package pathfind
+
+import (
+	"container/heap"
+)
+
+// Grid is a rectangular grid of cells laid out as idx = x + y*Wide.
+type Grid struct {
+	Wide, High int
+	Walkable   func(idx int) bool // Walkable returns true if the cell with the provided index can be moved through.
+}
+
+// Heuristic estimates the cost of the cheapest path between the cells with the provided indices. A* only finds the
+// shortest path if the heuristic never overestimates.
+type Heuristic func(a, b int) float64
+
+// Manhattan returns a Heuristic measuring the Manhattan distance between cells of a grid with the provided width.
+func Manhattan(wide int) Heuristic {
+	return func(a, b int) float64 {
+		dx, dy := a%wide-b%wide, a/wide-b/wide
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		return float64(dx + dy)
+	}
+}
+
+// AStar returns the shortest path between the cells start and goal which moves only through walkable cells, using the
+// Manhattan distance as its heuristic. See AStarWith.
+func AStar(grid Grid, start, goal int) ([]int, bool) {
+	return AStarWith(grid, start, goal, Manhattan(grid.Wide))
+}
+
+// AStarWith returns the shortest path between the cells start and goal which moves only through walkable cells, one
+// cardinal step at a time. The path includes both start and goal. ok is false if either end lies outside the grid or
+// is not walkable, or if no path exists.
+func AStarWith(grid Grid, start, goal int, h Heuristic) (path []int, ok bool) {
+	size := grid.Wide * grid.High
+	if start < 0 || start >= size || goal < 0 || goal >= size || !grid.Walkable(start) || !grid.Walkable(goal) {
+		return nil, false
+	}
+	cameFrom := make(map[int]int)
+	cost := map[int]float64{start: 0}
+	open := &openSet{{cell: start, priority: h(start, goal)}}
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(node)
+		if curr.cell == goal {
+			return reconstruct(cameFrom, start, goal), true
+		}
+		if curr.priority > cost[curr.cell]+h(curr.cell, goal) {
+			continue // a cheaper route to this cell was queued after this one.
+		}
+		for _, next := range grid.neighbours(curr.cell) {
+			nextCost := cost[curr.cell] + 1
+			if prev, seen := cost[next]; seen && prev <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			cameFrom[next] = curr.cell
+			heap.Push(open, node{cell: next, priority: nextCost + h(next, goal)})
+		}
+	}
+	return nil, false
+}
+
+// neighbours returns the walkable cells sharing an edge with the provided cell.
+func (g Grid) neighbours(idx int) []int {
+	result := make([]int, 0, 4)
+	x, y := idx%g.Wide, idx/g.Wide
+	if x > 0 && g.Walkable(idx-1) {
+		result = append(result, idx-1)
+	}
+	if x < g.Wide-1 && g.Walkable(idx+1) {
+		result = append(result, idx+1)
+	}
+	if y > 0 && g.Walkable(idx-g.Wide) {
+		result = append(result, idx-g.Wide)
+	}
+	if y < g.High-1 && g.Walkable(idx+g.Wide) {
+		result = append(result, idx+g.Wide)
+	}
+	return result
+}
+
+// reconstruct follows cameFrom back from goal to start, returning the path from start to goal.
+func reconstruct(cameFrom map[int]int, start, goal int) []int {
+	path := []int{goal}
+	for cell := goal; cell != start; {
+		cell = cameFrom[cell]
+		path = append(path, cell)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// node is a cell in the open set, ordered by the estimated cost of the cheapest path through it.
+type node struct {
+	cell     int
+	priority float64
+}
+
+// openSet is a binary min-heap of nodes implementing heap.Interface.
+type openSet []node
+
+func (o openSet) Len() int           { return len(o) }
+func (o openSet) Less(i, j int) bool { return o[i].priority < o[j].priority }
+func (o openSet) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o *openSet) Push(x any)        { *o = append(*o, x.(node)) }
+func (o *openSet) Pop() any {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return n
+}
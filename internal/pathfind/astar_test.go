@@ -0,0 +1,71 @@
+package pathfind
+
+import (
+	"testing"
+)
+
+// testGrid returns a Grid built from the provided rows, in which '#' marks a cell which is not walkable.
+func testGrid(rows ...string) Grid {
+	return Grid{
+		Wide:     len(rows[0]),
+		High:     len(rows),
+		Walkable: func(idx int) bool { return rows[idx/len(rows[0])][idx%len(rows[0])] != '#' },
+	}
+}
+
+func TestAStar(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        []string
+		start, goal int
+		wantLen     int // wantLen is the number of cells in the path, including both ends.
+		wantOK      bool
+	}{
+		{name: "open", rows: []string{"....", "....", "...."}, start: 0, goal: 11, wantLen: 6, wantOK: true},
+		{name: "same cell", rows: []string{"...."}, start: 2, goal: 2, wantLen: 1, wantOK: true},
+		{name: "around wall", rows: []string{".#..", ".#..", "...."}, start: 0, goal: 2, wantLen: 7, wantOK: true},
+		{name: "walled off", rows: []string{".#..", ".#..", ".#.."}, start: 0, goal: 2},
+		{name: "solid goal", rows: []string{"...#"}, start: 0, goal: 3},
+		{name: "outside grid", rows: []string{"...."}, start: 0, goal: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := testGrid(tt.rows...)
+			path, ok := AStar(grid, tt.start, tt.goal)
+			if ok != tt.wantOK {
+				t.Fatalf("AStar() ok = %v; want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if len(path) != tt.wantLen {
+				t.Errorf("AStar() = %v; want a path of %d cells", path, tt.wantLen)
+			}
+			if path[0] != tt.start || path[len(path)-1] != tt.goal {
+				t.Errorf("AStar() = %v; want a path from %d to %d", path, tt.start, tt.goal)
+			}
+			for i := 1; i < len(path); i++ {
+				if !isNeighbour(grid, path[i-1], path[i]) || !grid.Walkable(path[i]) {
+					t.Errorf("AStar() = %v; step %d -> %d is not a walkable cardinal step", path, path[i-1], path[i])
+				}
+			}
+		})
+	}
+}
+
+// isNeighbour returns true if the cells a and b share an edge.
+func isNeighbour(g Grid, a, b int) bool {
+	dx, dy := a%g.Wide-b%g.Wide, a/g.Wide-b/g.Wide
+	return dx*dx+dy*dy == 1
+}
+
+// BenchmarkAStar measures finding a path from corner to corner of an open 100×100 grid.
+func BenchmarkAStar(b *testing.B) {
+	grid := Grid{Wide: 100, High: 100, Walkable: func(int) bool { return true }}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, ok := AStar(grid, 0, 100*100-1); !ok {
+			b.Fatal("AStar() found no path")
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const (
+	PatrolEnemyHP    = 2   // PatrolEnemyHP is the health of each PatrolEnemy.
+	PatrolEnemySpeed = 0.5 // PatrolEnemySpeed is how quickly a PatrolEnemy walks.
+)
+
+// PatrolEnemy walks back and forth between two X coordinates, turning around at walls and ledges.
+type PatrolEnemy struct {
+	enemyBody
+	patrolLeft, patrolRight int // patrolLeft and patrolRight bound the X coordinates the enemy walks between.
+	dir                     int // dir is 1 when walking right, or -1 when walking left.
+}
+
+// NewPatrolEnemy constructs a PatrolEnemy from the provided entity. The "patrol_left_x" and "patrol_right_x" fields
+// give the patrol bounds in pixels; if either is unset the enemy patrols until it reaches a wall or ledge.
+func NewPatrolEnemy(s *PlatformerScene, entity *Entity) (Enemy, error) {
+	result := &PatrolEnemy{
+		enemyBody:   newEnemyBody(s, entity, PatrolEnemyHP),
+		patrolLeft:  entity.FieldInt("patrol_left_x"),
+		patrolRight: entity.FieldInt("patrol_right_x"),
+		dir:         1,
+	}
+	if result.patrolRight <= result.patrolLeft {
+		result.patrolLeft, result.patrolRight = 0, s.level.PxDims.W
+	}
+	return result, nil
+}
+
+// Update walks the enemy along its patrol.
+func (e *PatrolEnemy) Update() {
+	e.Vel.X = float64(e.dir) * PatrolEnemySpeed
+	collidesX, _ := e.move()
+	if !e.onSolidGround() {
+		return
+	}
+	if collidesX.Colliding(ClipNone) || e.atLedge() || e.atPatrolBound() {
+		e.dir = -e.dir
+	}
+}
+
+// atLedge returns true if there is no solid ground just ahead of the enemy.
+func (e *PatrolEnemy) atLedge() bool {
+	ahead := e.Hitbox().Add(IVec2{X: e.dir * e.Dim.W, Y: 1})
+	return !e.Collides(ahead).Colliding(ClipNone)
+}
+
+// atPatrolBound returns true if the enemy has reached the edge of its patrol in the direction it is walking.
+func (e *PatrolEnemy) atPatrolBound() bool {
+	box := e.Hitbox()
+	return (e.dir > 0 && box.X+box.W >= e.patrolRight) || (e.dir < 0 && box.X <= e.patrolLeft)
+}
+
+// Draw draws the enemy as a placeholder rectangle.
+func (e *PatrolEnemy) Draw(screen *ebiten.Image, camera IVec2) {
+	e.drawRect(screen, camera, color.RGBA{R: 0xc0, G: 0x30, B: 0xc0, A: 0xff})
+}
@@ -2,13 +2,29 @@ package internal
 
 import (
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/colornames"
-	"log"
+	"image/color"
+	"log/slog"
 	"math"
+	"math/bits"
 	"strings"
+	"time"
+)
+
+const (
+	PlayerStartingLives = 3  // PlayerStartingLives is the number of lives the player starts the game with.
+	FadeFrames          = 30 // FadeFrames is the number of frames taken to fade in from black after respawning.
+	// OneWayThreshold is the fraction of the bottom edge of a hitbox which must rest on one-way platforms before they
+	// are treated as solid.
+	OneWayThreshold = 0.5
+
+	MinPixelScale  = 0.5  // MinPixelScale is the furthest the camera zooms out to keep both co-op players in view.
+	CoopZoomMargin = 32   // CoopZoomMargin is the space in pixels kept between each co-op player and the screen edge.
+	CoopZoomRate   = 0.05 // CoopZoomRate is the fraction of the remaining zoom applied each frame.
 )
 
 // LayerID identifies a specific layer from LDtk Level data by ID.
@@ -24,27 +40,114 @@ type PlatformerScene struct {
 	*BaseScene
 	gdat *GameData
 
-	camera IRect        // camera is the region of the screen being rendered.
-	keys   []ebiten.Key // keys is the set of keys currently pressed.
-
-	loaded      bool
-	background  *ebiten.Image
-	player      *Player
-	cellSize    int // width and height of each cell in the collision mask
-	intGridData []IntGridData
-	cellsWide   int
-	debug       bool
-	underCursor IntGridData
+	camera WorldCamera // camera follows the player through the level.
+	// PixelScale is the number of screen pixels per world pixel. It drops below 1 to zoom out when co-op players are
+	// far apart.
+	PixelScale float64
+	keys       []ebiten.Key // keys is the set of keys currently pressed.
+
+	loaded         bool
+	level          *Level // level is the currently loaded level.
+	background     *ebiten.Image
+	player         *Player
+	player2        *Player // player2 is the second player in co-op, or nil if no level has spawned one.
+	cellSize       int     // width and height of each cell in the collision mask
+	intGridData    []IntGridData
+	collisionLayer *TileLayer // collisionLayer is the layer intGridData was loaded from.
+	cellsWide      int
+	debug          bool
+	debugGrid      bool          // debugGrid draws cell boundaries and coordinates while debug is set; toggled by F3.
+	gridLineV      *ebiten.Image // gridLineV is the vertical line drawn along each column of the debug grid.
+	gridLineH      *ebiten.Image // gridLineH is the horizontal line drawn along each row of the debug grid.
+	underCursor    IntGridData
+	minimap        *Minimap
+	particles      *ParticleSystem
+	projectiles    *ProjectileSystem
+	explosions     *ExplosionSystem
+	materials      MaterialRegistry // materials maps cell types to the Material used for their surfaces.
+
+	animatedTiles []TileAnim // animatedTiles is the list of all animated tiles in the current level.
+	dirtyRegions  []IRect    // dirtyRegions is the list of regions of the background which must be redrawn.
+
+	coins       []*Entity     // coins is the list of coins in the current level which have not yet been collected.
+	keyItems    []*Entity     // keyItems is the list of keys in the current level which have not yet been collected.
+	powerUps    []*Entity     // powerUps is the list of power-ups in the current level which have not yet been collected.
+	exits       []*Entity     // exits is the list of exits in the current level.
+	dialogues   []*Entity     // dialogues is the list of dialogue triggers in the current level.
+	switches    []*Entity     // switches is the list of door switches in the current level.
+	doors       []*SliderDoor // doors is the list of sliding doors in the current level.
+	windZones   []WindZone    // windZones is the list of wind zones in the current level.
+	waterZones  []WaterZone   // waterZones is the list of water zones in the current level.
+	activeWater *WaterZone    // activeWater is the water zone containing the player, or nil if they are not in water.
+	lighting    *LightingSystem
+	lanterns    []*Light      // lanterns holds the light carried by each player, in the same order as players().
+	rng         *LevelRNG     // rng drives random events in the level, such as particles and item drops.
+	zipLines    []ZipLine     // zipLines is the list of zip lines in the current level.
+	zipLineTile *ebiten.Image // zipLineTile is the tile drawn along each zip line.
+	ropeAnchors []RopeAnchor  // ropeAnchors is the list of rope anchors in the current level.
+	escalators  []Escalator   // escalators is the list of escalators in the current level.
+	crates      []*Crate      // crates is the list of crates in the current level.
+	teleporters []Teleporter  // teleporters is the list of teleporters in the current level.
+	magnetCells []IVec2       // magnetCells holds the coordinates of each magnet cell in the current level.
+
+	magnetState map[uuid.UUID]bool // magnetState records whether each magnet switch is currently pressed.
+	openEdges   map[IVec2]bool     // openEdges is the set of cardinal directions in which the level has a neighbour.
+	entityIndex entityIndex        // entityIndex finds the entities of the current level by position.
+	movers      []Mover            // movers is the list of every moving actor in the current level, player first.
+	brokenCells map[IVec2]bool     // brokenCells is the set of breakable cells destroyed in the current level.
+	// retiled holds the tiles of each auto-layer cell recomputed since the level was loaded, replacing those of the layer.
+	retiled     map[*TileLayer]map[IVec2][]Tile
+	cameraZones []*CameraZone // cameraZones is the list of camera zones in the current level.
+	enemies     []Enemy       // enemies is the list of living enemies in the current level.
+
+	levelCache        *LevelCache         // levelCache holds the runtime state of recently visited levels.
+	activeSwitches    map[uuid.UUID]bool  // activeSwitches is the set of door switches triggered in the current level.
+	destroyedEntities map[uuid.UUID]bool  // destroyedEntities is the set of collected items and defeated enemies.
+	enemyIIDs         map[Enemy]uuid.UUID // enemyIIDs maps each enemy to the IID of the entity it was spawned from.
+
+	// entityFactory maps entity IDs to the factory used to construct enemies of that type.
+	entityFactory map[string]EntityFactory
+
+	activeCameraZone *CameraZone        // activeCameraZone is the zone the camera is clamped to, or nil to use the level.
+	touching         map[uuid.UUID]bool // touching is the set of triggers the player touched last frame.
+	coinCount        int                // coinCount is the number of coins collected in the current level.
+	totalCoins       int                // totalCoins is the number of coins found in the current level.
+	elapsedSeconds   float64            // elapsedSeconds is the time spent in the current level.
+
+	spawn       IVec2 // spawn is where the player respawns in the current level.
+	spawn2      IVec2 // spawn2 is where the second player respawns in the current level.
+	lives       int   // lives is the number of times the player may die before restarting the game.
+	fadeFrames  int   // fadeFrames is the number of frames remaining in the current fade from black.
+	flashFrames int   // flashFrames is the number of frames remaining in the current flash from white.
+
+	frame     int         // frame is the number of frames this scene has been updated for.
+	sequences []*Sequence // sequences holds every running Sequence.
+	console   *Console    // console runs debug commands typed by the developer in debug builds.
+
+	reloads chan *GameData // reloads receives freshly loaded game data whenever the LDtk file changes in debug builds.
 }
 
-func NewPlatformerScene(gdat *GameData) *PlatformerScene {
+func NewPlatformerScene(game *Game, gdat *GameData) *PlatformerScene {
 	result := &PlatformerScene{
-		BaseScene: &BaseScene{},
-		gdat:      gdat,
-		debug:     true,
+		BaseScene:  NewBaseScene(game),
+		gdat:       gdat,
+		debug:      true,
+		materials:  NewMaterialRegistry(),
+		lives:      PlayerStartingLives,
+		PixelScale: 1,
+		lighting:   NewLightingSystem(),
+		rng:        NewLevelRNG(),
+		levelCache: NewLevelCache(),
+		reloads:    make(chan *GameData, 1),
+		console:    NewConsole(),
 	}
-	w, h := result.Layout(0, 0) // use base scene's layout options for the screen.
-	result.camera = IRect{X: 0, Y: 0, W: w, H: h}
+	result.particles = NewParticleSystem(result.rng.Rand)
+	result.projectiles = NewProjectileSystem(result)
+	result.explosions = NewExplosionSystem(result)
+	result.registerEnemyFactories()
+	result.registerConsoleCommands()
+	w, h := result.BaseScene.Layout(0, 0) // use base scene's layout options for the screen.
+	result.camera = WorldCamera{Size: IDim{W: w, H: h}, LerpFactor: 1}
 	result.background = ebiten.NewImage(w, h)
 	return result
 }
@@ -54,100 +157,519 @@ func (s *PlatformerScene) Update() error {
 	if !s.loaded { // TODO: consider doing this async
 		timeit("loading level", func() {
 			if err := s.LoadLevel(s.gdat.LevelStart); err != nil {
-				log.Fatal(err)
+				fatal("error loading level", "err", err)
 			}
 		})
 	}
+	if s.updateConsole() { // the game is paused while the console is open so typing does not move the player.
+		return nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF3) {
+		s.debugGrid = !s.debugGrid
+	}
+	s.updateNoclip()
 	// update under cursor for debug draw
 	x, y := ebiten.CursorPosition()
-	x -= s.camera.X
-	y -= s.camera.Y
-	s.underCursor = s.gridData(float64(x), float64(y))
+	cursor := s.camera.ScreenToWorld(IVec2{X: x, Y: y}.Vec2())
+	s.underCursor = s.gridData(cursor.X, cursor.Y)
+
+	select {
+	case gdat := <-s.reloads:
+		if err := s.Reload(gdat); err != nil {
+			slog.Error("error reloading game data", "err", err)
+		}
+	default:
+	}
+
+	s.frame++
+	s.elapsedSeconds += 1.0 / TPS
+	if s.fadeFrames > 0 {
+		s.fadeFrames--
+	}
+	if s.flashFrames > 0 {
+		s.flashFrames--
+	}
+	s.updateTileAnims()
+	s.updateSequences()
 
 	if s.player != nil {
-		s.player.Update()
+		s.updateDoors()
+		s.updateWind()
+		s.updateEscalators()
+		s.updateWater()
 	}
+	s.updateMovers()
+	s.updateEnemies()
+	s.updateMagnets()
+	s.particles.Update()
+	s.projectiles.Update()
+	s.explosions.Update()
+	s.updateLights()
+	s.updateLevelBounds()
 	s.updateCamera()
+	if s.player != nil {
+		s.minimap.SetPlayerPos(s.player.Pos)
+	}
+	s.updateCollectibles()
+	s.updateSwitches()
+	s.updateTeleporters()
+	s.updateDialogues()
+	s.updateExits()
+	s.flushDirtyRegions()
+
+	return nil
+}
+
+// updateCollectibles collects any coins, keys, or power-ups either player is touching. Coins and keys are shared, but
+// power-ups only affect the player who collected them.
+func (s *PlatformerScene) updateCollectibles() {
+	s.coins = s.collect(s.coins, func(coin *Entity, _ *Player) {
+		s.coinCount++
+		s.game.bus.Publish(TopicCoinCollected, coin)
+	})
+	s.keyItems = s.collect(s.keyItems, func(key *Entity, _ *Player) {
+		s.game.bus.Publish(TopicKeyCollected, key)
+	})
+	s.powerUps = s.collect(s.powerUps, func(powerUp *Entity, p *Player) {
+		switch powerUp.ID {
+		case EtyPowerUpDoubleJump:
+			p.MaxAirJumps = 2
+			p.airJumpsLeft = p.MaxAirJumps
+		}
+	})
+}
+
+// collect calls onCollect for each entity a player is touching along with the player touching it, returning the
+// entities which remain uncollected.
+func (s *PlatformerScene) collect(entities []*Entity, onCollect func(*Entity, *Player)) []*Entity {
+	remaining := entities[:0]
+	for _, entity := range entities {
+		if p := s.playerTouching(entity.PxBounds()); p != nil {
+			s.destroyedEntities[entity.IID] = true
+			onCollect(entity, p)
+			continue
+		}
+		remaining = append(remaining, entity)
+	}
+	return remaining
+}
+
+// updateDialogues shows the dialogue for any trigger either player has just started touching. Paragraphs of dialogue
+// separated by a blank line are shown one after another in separate boxes.
+func (s *PlatformerScene) updateDialogues() {
+	for _, dialogue := range s.dialogues {
+		wasTouching := s.touching[dialogue.IID]
+		s.touching[dialogue.IID] = s.playerTouching(dialogue.PxBounds()) != nil
+		if wasTouching || !s.touching[dialogue.IID] {
+			continue
+		}
+		triggerOnce := dialogue.FieldBool("trigger_once")
+		if triggerOnce && s.game.save.TriggeredDialogues[dialogue.IID] {
+			continue
+		}
+		if triggerOnce {
+			s.game.save.TriggeredDialogues[dialogue.IID] = true
+		}
+		s.RunSequence(s.dialogueChain(dialogue.FieldString("text")))
+		return
+	}
+}
+
+// dialogueChain returns a Sequence showing each paragraph of the provided text in its own dialogue box. The scene is
+// paused while each box is shown, so the next box appears as soon as the previous one is closed.
+func (s *PlatformerScene) dialogueChain(text string) *Sequence {
+	var steps []Step
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		paragraph := paragraph
+		steps = append(steps, func() int {
+			s.game.PushScene(NewDialogueScene(s.game, paragraph, s.Draw))
+			return 1
+		})
+	}
+	return NewSequence(steps...)
+}
+
+// updateExits checks whether either player has reached an exit, and if so shows the results of the current level
+// before loading the next.
+func (s *PlatformerScene) updateExits() {
+	for _, exit := range s.exits {
+		if s.playerTouching(exit.PxBounds()) == nil {
+			continue
+		}
+		next, ok := s.gdat.LevelsByID[exit.FieldString("level")]
+		if !ok {
+			slog.Error("exit refers to unknown level", "exit", exit.IID, "level", exit.FieldString("level"))
+			continue
+		}
+		completionData := LevelCompletion{
+			CoinsCollected: s.coinCount,
+			TotalCoins:     s.totalCoins,
+			TimeSeconds:    s.elapsedSeconds,
+			Reached:        true,
+		}
+		s.game.save.RecordCompletion(s.level.ID, completionData)
+		s.game.PushScene(NewResultsScene(s.game, completionData, func() {
+			if err := s.enterLevel(next.UID); err != nil {
+				fatal("error loading level", "err", err)
+			}
+		}))
+		return
+	}
+}
+
+// updateLevelBounds moves the players into a neighbouring level once either leaves the bounds of the current level. In
+// co-op, the other player is brought along to where the first left.
+func (s *PlatformerScene) updateLevelBounds() {
+	bounds := IRect{W: s.level.PxDims.W, H: s.level.PxDims.H}
+	for _, p := range s.players() {
+		if !bounds.Contains(p.Hitbox().Center()) {
+			s.leaveLevel(p)
+			return
+		}
+	}
+}
+
+// leaveLevel transitions into the level the provided player has left the current level toward, if any.
+func (s *PlatformerScene) leaveLevel(leaver *Player) {
+	center := leaver.Hitbox().Center()
+	bounds := IRect{W: s.level.PxDims.W, H: s.level.PxDims.H}
+	next, ok := s.gdat.LevelAt(s.LocalToWorld(center))
+	if !ok {
+		next, ok = s.gdat.AdjacentLevel(s.level, center.Sub(bounds.Center()).CardinalDir())
+	}
+	if !ok {
+		return
+	}
+	worldPos := s.LocalToWorld(leaver.Pos)
+	state, vel := leaver.state(), leaver.Vel
+	wipe := NewWipeTransition(center.Sub(bounds.Center()).CardinalDir().X, WipeSpeed)
+	wipe.Color = s.level.BGColor
+	s.game.PlayTransition(wipe, s.Draw, func() {
+		if err := s.enterLevel(next.UID); err != nil {
+			fatal("error loading level", "err", err)
+		}
+		for _, p := range s.players() {
+			p.SetPos(s.WorldToLocal(worldPos))
+			p.states.Set(state)
+			p.Vel = vel
+		}
+		s.spawn = s.WorldToLocal(worldPos)
+		if s.player2 != nil {
+			s.spawn2 = s.spawn
+		}
+		s.updateCamera()
+	})
+}
+
+// onPlayerDeath is called once the player has died. A life is lost and the player respawns after a fade. If no lives
+// remain, the game restarts from the first level.
+func (s *PlatformerScene) onPlayerDeath() {
+	s.lives--
+	s.fadeFrames = FadeFrames
+	if s.lives <= 0 {
+		s.lives = PlayerStartingLives
+		s.levelCache = NewLevelCache() // the game restarts from scratch.
+		if err := s.LoadLevel(s.gdat.LevelStart); err != nil {
+			fatal("error loading level", "err", err)
+		}
+	}
+	s.RespawnPlayer()
+}
+
+// onPlayer2Death is called once the second player has died. They respawn immediately without costing a life.
+func (s *PlatformerScene) onPlayer2Death() {
+	s.player2.HP = PlayerMaxHP
+	s.player2.Vel = Vec2{}
+	s.player2.SetPos(s.spawn2)
+	s.player2.states.Set(s.player2.startIdling())
+}
+
+// players returns every player in the scene, the first player first.
+func (s *PlatformerScene) players() []*Player {
+	result := make([]*Player, 0, 2)
+	for _, p := range []*Player{s.player, s.player2} {
+		if p != nil {
+			result = append(result, p)
+		}
+	}
+	return result
+}
 
+// playerTouching returns the first player whose hitbox overlaps the provided region, or nil if neither does.
+func (s *PlatformerScene) playerTouching(region IRect) *Player {
+	for _, p := range s.players() {
+		if p.Hitbox().Overlaps(region) {
+			return p
+		}
+	}
 	return nil
 }
 
-// updateCamera updates the camera.
+// nearestPlayer returns the player closest to the provided point along the X-axis, or nil if no player has spawned.
+func (s *PlatformerScene) nearestPlayer(pt IVec2) *Player {
+	var result *Player
+	for _, p := range s.players() {
+		if result == nil || abs(p.Pos.X-pt.X) < abs(result.Pos.X-pt.X) {
+			result = p
+		}
+	}
+	return result
+}
+
+// RespawnPlayer restores the player to full health at the spawn point of the current level.
+func (s *PlatformerScene) RespawnPlayer() {
+	s.player.HP = PlayerMaxHP
+	s.player.Vel = Vec2{}
+	s.player.SetPos(s.spawn)
+	s.player.states.Set(s.player.startIdling())
+	s.game.bus.Publish(TopicPlayerRespawned, s.player.HP)
+}
+
+// updateCamera centers the camera on the player, keeping it within the bounds of the active camera zone or level. In
+// co-op, the camera centers on the midpoint between both players and zooms out to keep them both in view. The camera
+// stays put in levels without a player.
 func (s *PlatformerScene) updateCamera() {
-	s.camera.X = s.camera.W/2 - s.player.Pos.X
-	s.camera.Y = s.camera.H/2 - s.player.Pos.Y
+	if s.player == nil {
+		return
+	}
+	target, center := s.player.Pos, s.player.Hitbox().Center()
+	if s.player2 != nil {
+		sum := s.player.Pos.Add(s.player2.Pos)
+		target = IVec2{X: sum.X / 2, Y: sum.Y / 2}
+		sum = center.Add(s.player2.Hitbox().Center())
+		center = IVec2{X: sum.X / 2, Y: sum.Y / 2}
+	}
+	s.updateCameraZone(center)
+	s.updateZoom()
+	s.camera.Bounds = s.cameraBounds()
+	s.camera.Target(target.Vec2())
+	s.camera.Update()
+	s.game.audio.SetListener(s.player.center())
+}
+
+// updateZoom eases PixelScale towards the largest scale which keeps both players on screen, then resizes the camera to
+// match.
+func (s *PlatformerScene) updateZoom() {
+	target := 1.0
+	if s.player2 != nil {
+		w, h := s.BaseScene.Layout(0, 0)
+		dist := s.player.Pos.Sub(s.player2.Pos)
+		spanX, spanY := float64(abs(dist.X)+2*CoopZoomMargin), float64(abs(dist.Y)+2*CoopZoomMargin)
+		target = max(min(1, min(float64(w)/spanX, float64(h)/spanY)), MinPixelScale)
+	}
+	s.PixelScale += (target - s.PixelScale) * CoopZoomRate
+	s.camera.Size.W, s.camera.Size.H = s.Layout(0, 0)
+}
+
+// Layout maps the window size to the screen size, shrinking the screen as PixelScale drops so that more of the level is
+// visible.
+func (s *PlatformerScene) Layout(w, h int) (int, int) {
+	bw, bh := s.BaseScene.Layout(w, h)
+	return int(float64(bw) / s.PixelScale), int(float64(bh) / s.PixelScale)
+}
+
+// Idle returns true once the player is standing still and no effects, enemies, or sequences remain in the level.
+func (s *PlatformerScene) Idle() bool {
+	if s.player == nil || s.player.state() != PlayerStateIdle || s.player.Vel != (Vec2{}) {
+		return false
+	}
+	return len(s.enemies) == 0 && len(s.animatedTiles) == 0 && len(s.sequences) == 0 &&
+		len(s.particles.particles) == 0 && s.projectiles.active == 0 && len(s.explosions.explosions) == 0 &&
+		s.fadeFrames == 0 && s.flashFrames == 0
 }
 
 // Draw draws this scene to the provided Image.
 func (s *PlatformerScene) Draw(screen *ebiten.Image) {
 	// draw background
 	opts := ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(s.camera.X), float64(s.camera.Y))
+	offset := s.camera.Offset()
+	opts.GeoM.Translate(offset.X, offset.Y)
 	screen.DrawImage(s.background, &opts)
 
-	// draw player sprite
-	opts.GeoM.Translate(float64(s.player.Pos.X), float64(s.player.Pos.Y))
-	s.player.sprite.DrawTo(screen, &opts)
-	//screen.DrawImage(s.player.sprite, &opts)
+	s.drawZipLines(screen)
+	s.drawEscalators(screen)
+
+	// draw the player, enemies, and crates
+	s.drawMovers(screen)
+
+	// draw particles, projectiles, and explosions
+	s.particles.Draw(screen, offset.IVec2())
+	s.projectiles.Draw(screen, offset.IVec2())
+	s.explosions.Draw(screen, offset.IVec2())
+
+	// draw darkness and lights
+	s.lighting.Draw(screen, offset.IVec2())
+
+	// draw fade
+	if s.fadeFrames > 0 {
+		vector.DrawFilledRect(screen, 0, 0, float32(s.camera.Size.W), float32(s.camera.Size.H),
+			color.RGBA{A: uint8(255 * s.fadeFrames / FadeFrames)}, false)
+	}
+
+	s.drawTeleportFlash(screen)
 
 	// draw player state
 	if s.debug {
 		s.drawDebug(screen)
 	}
+	s.console.Draw(screen, s.game.font)
 }
 
+// debugStateHistoryLines is the number of recent player states listed by drawDebug.
+const debugStateHistoryLines = 10
+
 // drawDebug draws a bunch of platformer-related debug messages to the screen.
 func (s *PlatformerScene) drawDebug(screen *ebiten.Image) {
+	s.drawWindDebug(screen)
+	if s.debugGrid {
+		s.drawDebugGrid(screen)
+	}
+
+	// print FPS
+	s.game.font.DrawText(screen, fmt.Sprintf("%.0f", ebiten.ActualFPS()), 300, 0, nil)
+
+	// print IntGridData under cursor
+	s.game.font.DrawText(screen, fmt.Sprintf("0x%x %s", s.underCursor, s.underCursor.Describe()), 0, 227, nil)
+
+	if s.player != nil {
+		s.drawPlayerDebug(screen)
+	}
+}
+
+// drawPlayerDebug draws the player's hitbox, state, and collision data to the screen.
+func (s *PlatformerScene) drawPlayerDebug(screen *ebiten.Image) {
 	var lines []string
 
 	// print rectangle over hitbox
-	box := s.player.Hitbox()
-	box.X += s.camera.X
-	box.Y += s.camera.Y
+	box := s.player.Hitbox().Add(s.camera.Offset().IVec2())
 	vector.StrokeRect(screen, float32(box.X), float32(box.Y), float32(box.W), float32(box.H), 2, colornames.Green, true)
 
-	// print FPS
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%.0f", ebiten.ActualFPS()), 300, 0)
-
 	// print player state and position
-	lines = append(lines, fmt.Sprintf("Player state: %s", s.player.state))
+	lines = append(lines, fmt.Sprintf("Player state: %s", s.player.state()))
 	lines = append(lines, fmt.Sprintf("Pos: (%d, %d); Vel: (%.2f, %.2f)",
 		s.player.Pos.X, s.player.Pos.Y, s.player.Vel.X, s.player.Vel.Y))
 
-	ebitenutil.DebugPrint(screen, strings.Join(lines, "\n"))
+	s.game.font.DrawText(screen, strings.Join(lines, "\n"), 0, 0, nil)
 
-	// print IntGridData under cursor
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("0x%x", s.underCursor), 0, 220)
+	// print recent player states, newest last
+	history := s.player.StateHistory()
+	history = history[max(len(history)-debugStateHistoryLines, 0):]
+	for i, state := range history {
+		label := state.String()
+		w, _ := s.game.font.MeasureText(label)
+		s.game.font.DrawText(screen, label, s.camera.Size.W-w, (i+1)*s.game.font.GlyphH, nil)
+	}
 
 	// print Player colliding data
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("0x%x", s.player.colliding), 0, 210)
+	s.game.font.DrawText(screen, fmt.Sprintf("0x%x %s", s.player.colliding, s.player.colliding.Describe()), 0, 214, nil)
 }
 
 // LoadLevel loads a level by its UID, unloading the currently loaded level and the background. No foreground or
 // parallaxing layers are loaded.
 func (s *PlatformerScene) LoadLevel(id UID) error {
-	log.Printf("loading level ID %d", id)
+	slog.Info("loading level", "uid", id)
 	s.loaded = true
 
 	level, ok := s.gdat.Levels[id]
 	if !ok {
 		return fmt.Errorf("no level found with id: %d", id)
 	}
-
-	if err := s.loadBackground(level); err != nil {
+	s.level = level
+	s.rng.Reset(level.ID)
+	s.animatedTiles = nil
+	s.dirtyRegions = nil
+	s.brokenCells = make(map[IVec2]bool)
+	s.retiled = make(map[*TileLayer]map[IVec2][]Tile)
+
+	profile := &TimingNode{Label: "LoadLevel"}
+	start := time.Now()
+	if err := profile.Time("loadBackground", func() error { return s.loadBackground(level) }); err != nil {
 		return err
 	}
-	if err := s.loadCells(level); err != nil {
+	if err := profile.Time("loadCells", func() error { return s.loadCells(level, s.gdat.IntGridMasks) }); err != nil {
 		return err
 	}
-	if err := s.loadEntities(level); err != nil {
+	s.loadMagnets()
+	s.loadOpenEdges(level)
+	s.loadDebugGrid()
+	s.minimap = NewMinimap(s.intGridData, s.cellsWide, s.cellSize)
+	s.game.hud.SetMinimap(s.minimap)
+	if err := profile.Time("loadEntities", func() error { return s.loadEntities(level) }); err != nil {
 		return err
 	}
-	s.processLadders()
+	if err := profile.Time("loadLights", func() error { return s.loadLights(level) }); err != nil {
+		return err
+	}
+	TimeitContext("processLadders", profile, s.processLadders)
 	//s.processOneWay()
+	TimeitContext("processMaterials", profile, s.processMaterials)
+	s.game.audio.PlayMusic(level.Music)
+	profile.Duration = time.Since(start)
+	slog.Debug("level load profile", "level", level.ID, "total", profile.Duration.Round(time.Microsecond),
+		"stages", profile.String())
+	slog.Info("loaded level", "uid", id, "level", level.ID)
 	return nil
 }
 
+// Reload swaps in the provided game data and reloads the current level, preserving the player's position, health, and
+// progress through the level.
+func (s *PlatformerScene) Reload(gdat *GameData) error {
+	s.gdat = gdat
+	if s.level == nil || s.player == nil {
+		s.loaded = false
+		return nil
+	}
+	p := *s.player
+	snapshot, elapsed := s.snapshot(), s.elapsedSeconds
+	if err := s.LoadLevel(s.level.UID); err != nil {
+		return err
+	}
+	s.restore(snapshot) // keeps collected coins from respawning, which also recounts coinCount.
+	s.player.SetPos(p.Pos)
+	s.player.states.Set(p.state())
+	s.player.Vel, s.player.HP = p.Vel, p.HP
+	s.player.MaxAirJumps, s.player.airJumpsLeft = p.MaxAirJumps, p.airJumpsLeft
+	s.elapsedSeconds = elapsed
+	return nil
+}
+
+// WatchGameData reloads the current level whenever the LDtk file changes on disk. It is a no-op outside of debug builds.
+func (s *PlatformerScene) WatchGameData() error {
+	if !debugBuild {
+		return nil
+	}
+	return watchGameData(func() {
+		gdat, err := LoadGameData()
+		if err != nil {
+			slog.Error("error loading game data", "err", err)
+			return
+		}
+		select { // drop the reload if one is already pending.
+		case s.reloads <- &gdat:
+		default:
+		}
+	})
+}
+
+// LocalToWorld converts the provided point from the coordinates of the current level to world coordinates.
+func (s *PlatformerScene) LocalToWorld(local IVec2) IVec2 {
+	if s.level == nil {
+		return local
+	}
+	return local.Add(s.level.WorldCoords)
+}
+
+// WorldToLocal converts the provided point from world coordinates to the coordinates of the current level.
+func (s *PlatformerScene) WorldToLocal(world IVec2) IVec2 {
+	if s.level == nil {
+		return world
+	}
+	return world.Sub(s.level.WorldCoords)
+}
+
 // loadBackground loads the background for the level, returning any fatal errors.
 func (s *PlatformerScene) loadBackground(level *Level) error {
 	// TODO: probably store the old level's background somewhere in case we end up splattering on it.
@@ -155,8 +677,10 @@ func (s *PlatformerScene) loadBackground(level *Level) error {
 
 	// paint a (fresh) background.
 	s.background = ebiten.NewImage(level.PxDims.W, level.PxDims.H)
+	s.background.Fill(level.BGColor)
+	level.tilePxIndex, level.tileGridSizes = nil, nil
 
-	log.Printf("loading level '%s'", level.ID)
+	slog.Info("loading level background", "level", level.ID)
 	opts := ebiten.DrawImageOptions{} // shared for fewer allocations
 	for _, layer := range level.layers {
 		if layer.TileSetUID == nil {
@@ -168,6 +692,9 @@ func (s *PlatformerScene) loadBackground(level *Level) error {
 		}
 		for _, tile := range layer.Tiles {
 			s.drawTile(tileset, layer, tile, &opts)
+			if layer.ID != CollisionLayerID {
+				level.indexTile(tile, layer.GridSize)
+			}
 		}
 	}
 	return nil
@@ -175,38 +702,157 @@ func (s *PlatformerScene) loadBackground(level *Level) error {
 
 // loadEntities loads all entities associated with the provided Level, returning any fatal errors.
 func (s *PlatformerScene) loadEntities(level *Level) error {
-	var err error
+	var (
+		err       error
+		spawn2    IVec2
+		hasSpawn2 bool
+	)
+	s.coins, s.keyItems, s.powerUps, s.exits, s.dialogues = nil, nil, nil, nil, nil
+	s.switches, s.doors, s.windZones, s.cameraZones, s.enemies = nil, nil, nil, nil, nil
+	s.sequences = nil
+	s.waterZones, s.activeWater = nil, nil
+	s.game.bus.Publish(TopicBossDefeated, nil) // hide the health bar of any boss in the previous level.
+	s.zipLines, s.ropeAnchors, s.escalators, s.crates = nil, nil, nil, nil
+	s.magnetState = make(map[uuid.UUID]bool)
+	s.activeSwitches = make(map[uuid.UUID]bool)
+	s.destroyedEntities = make(map[uuid.UUID]bool)
+	s.enemyIIDs = make(map[Enemy]uuid.UUID)
+	s.activeCameraZone = nil
+	s.projectiles.Clear()
+	s.explosions.Clear()
+	s.touching = make(map[uuid.UUID]bool)
+	s.entityIndex = entityIndex(level.Entities)
+	s.movers = nil
+	var teleporters []*Entity
 	for _, entity := range level.Entities {
+		if !s.gdat.isEnumValue(EntityTypeEnum, entity.ID) {
+			slog.Warn("entity is not a value of enum", "entity", entity.ID, "enum", EntityTypeEnum)
+		}
 		switch entity.ID {
+		case EtyCoin:
+			s.coins = append(s.coins, entity)
+		case EtyKey:
+			s.keyItems = append(s.keyItems, entity)
+		case EtyPowerUpDoubleJump:
+			s.powerUps = append(s.powerUps, entity)
+		case EtyExit:
+			s.exits = append(s.exits, entity)
+		case EtyDialogue:
+			s.dialogues = append(s.dialogues, entity)
+		case EtySwitch:
+			s.switches = append(s.switches, entity)
+		case EtyCamera:
+			s.cameraZones = append(s.cameraZones, NewCameraZone(entity))
+		case EtyWind:
+			s.windZones = append(s.windZones, NewWindZone(entity))
+		case EtyWater:
+			s.waterZones = append(s.waterZones, NewWaterZone(entity))
+		case EtyDarkness, EtyTorch, EtyLight:
+			// handled by loadLights.
+		case EtyZipLine:
+			s.zipLines = append(s.zipLines, NewZipLine(entity, s.cellSize))
+		case EtyRopeAnchor:
+			s.ropeAnchors = append(s.ropeAnchors, NewRopeAnchor(entity))
+		case EtyEscalator:
+			s.escalators = append(s.escalators, NewEscalator(entity))
+		case EtyCrate:
+			crate := NewCrate(s, entity)
+			s.crates = append(s.crates, crate)
+			s.AddMover(crate)
+		case EtyTeleporter:
+			teleporters = append(teleporters, entity)
+		case EtySliderDoor:
+			s.doors = append(s.doors, NewSliderDoor(entity, s.cellSize))
 		case EtyPlayer:
 			if s.player == nil {
 				s.player, err = NewPlayer(s)
 				if err != nil {
 					return err
 				}
+				s.player.OnDeath = s.onPlayerDeath
 			}
+			s.spawn = entity.PxCoords
 			s.player.SetPos(entity.PxCoords)
 			s.player.startIdling()
+		case EtyPlayer2:
+			if s.player2 == nil {
+				s.player2, err = NewPlayer(s)
+				if err != nil {
+					return err
+				}
+				s.player2.controls = Player2Controls
+				s.player2.OnDeath = s.onPlayer2Death
+			}
+			spawn2, hasSpawn2 = entity.PxCoords, true
+		default:
+			spawned, err := s.spawnEnemy(entity)
+			if err != nil {
+				return err
+			}
+			if !spawned {
+				slog.Warn("no handler for entity", "entity", entity.ID)
+			}
+		}
+	}
+	if s.player2 != nil {
+		if !hasSpawn2 {
+			spawn2 = s.spawn // levels without their own second spawn start both players together.
 		}
+		s.spawn2 = spawn2
+		s.player2.SetPos(spawn2)
+		s.player2.startIdling()
+		s.movers = append([]Mover{s.player2}, s.movers...)
+	}
+	if s.player != nil {
+		s.movers = append([]Mover{s.player}, s.movers...) // the player moves before everything else.
 	}
+	s.loadTeleporters(level, teleporters)
+	s.loadDoorTiles()
+	s.coinCount, s.totalCoins = 0, len(s.coins)
+	s.elapsedSeconds = 0
 	return nil
 }
 
-// loadCells loads all cell data associated with the provided Level, returning any fatal errors.
-func (s *PlatformerScene) loadCells(level *Level) error {
+// loadCells loads all cell data associated with the provided Level, returning any fatal errors. masks maps the IntGrid
+// values found in the level to the CollideMask of each cell; values missing from masks are used as-is.
+func (s *PlatformerScene) loadCells(level *Level, masks map[int]CollideMask) error {
 	collisionGrid, ok := level.layersByID[CollisionLayerID]
 	if !ok || len(collisionGrid.Grid) == 0 {
 		return fmt.Errorf("could not find layer with ID '%s'", CollisionLayerID)
 	}
+	s.collisionLayer = collisionGrid
 	s.cellSize = collisionGrid.GridSize
 	s.cellsWide = collisionGrid.CellDims.W
 	s.intGridData = make([]IntGridData, len(collisionGrid.Grid))
 	for i, d := range collisionGrid.Grid {
-		s.intGridData[i] = IntGridData(d)
+		if mask, ok := masks[d]; ok {
+			s.intGridData[i] = intGridDataFor(mask)
+		} else {
+			s.intGridData[i] = IntGridData(d)
+		}
 	}
 	return nil
 }
 
+// SetIntGrid replaces the IntGrid of the current level with the provided grid data, which must be laid out as
+// idx = x + y*cellsWide, and detects ladders and one-way platforms in the new data.
+func (s *PlatformerScene) SetIntGrid(grid []IntGridData, cellsWide int) {
+	s.collisionLayer = &TileLayer{
+		ID:       CollisionLayerID,
+		GridSize: s.cellSize,
+		CellDims: IDim{W: cellsWide, H: len(grid) / cellsWide},
+		Grid:     make([]int, len(grid)),
+	}
+	for i, d := range grid {
+		s.collisionLayer.Grid[i] = int(d)
+	}
+	s.intGridData = grid
+	s.cellsWide = cellsWide
+	s.processLadders()
+	s.processOneWay()
+	s.loadMagnets()
+}
+
 // processLadders detects ladder tops and bottoms and sets flags appropriately.
 func (s *PlatformerScene) processLadders() {
 	s.forAllGridData(func(cx int, cy int, dat IntGridData) {
@@ -244,9 +890,38 @@ func (s *PlatformerScene) processOneWay() {
 	return
 }
 
+// tileMaterials maps the values of the "material" key in tile custom data to the cell type of solid cells drawn with
+// that tile.
+var tileMaterials = map[string]IntGridData{
+	"ice":    IntGridIce,
+	"bounce": IntGridBounce,
+}
+
+// processMaterials turns solid cells drawn with a tile whose custom data sets a material, e.g. "material=ice", into
+// cells of the type found in tileMaterials.
+func (s *PlatformerScene) processMaterials() {
+	for _, layer := range s.level.layers {
+		if layer.TileSetUID == nil {
+			continue
+		}
+		for _, tile := range layer.Tiles {
+			dat, ok := tileMaterials[tileDataValue(s.gdat.TileCustomData(*layer.TileSetUID, tile.TileID), "material")]
+			if !ok {
+				continue
+			}
+			cx, cy := s.screenToCell(float64(tile.PxCoords.X), float64(tile.PxCoords.Y))
+			if s.gridDataI(cx, cy).isSolid() {
+				s.setGridDataI(cx, cy, dat)
+			}
+		}
+	}
+}
+
 // drawTile draws the provided tile from the provided tileset to the background image. The opts provided is mutated by
 // this call and is passed for efficiency.
 func (s *PlatformerScene) drawTile(tileset *ebiten.Image, layer *TileLayer, tile Tile, opts *ebiten.DrawImageOptions) {
+	tileset, offset := s.tileSheet(tileset, layer)
+	tile.SrcCoords = tile.SrcCoords.Add(offset)
 	opts.GeoM.Reset()
 	opts.GeoM = tile.GeoM(layer.GridSize)
 	opts.ColorScale.SetA(layer.Opacity)
@@ -256,6 +931,16 @@ func (s *PlatformerScene) drawTile(tileset *ebiten.Image, layer *TileLayer, tile
 	)
 }
 
+// tileSheet returns the image the tiles of the provided layer are drawn from, along with the offset of the layer's
+// tileset within it. Tiles are drawn from the atlas whenever their tileset was packed into it, to keep draw calls
+// batched.
+func (s *PlatformerScene) tileSheet(tileset *ebiten.Image, layer *TileLayer) (*ebiten.Image, IVec2) {
+	if offset, ok := s.gdat.AtlasOffsets[*layer.TileSetUID]; ok {
+		return s.gdat.Atlas, offset
+	}
+	return tileset, IVec2{}
+}
+
 // An Actor represents anything that can move around and collide with objects in the PlatformerScene. Actor handles
 // all low-level movement and collision testing within a PlatformerScene.
 type Actor struct {
@@ -276,6 +961,12 @@ func (a *Actor) MoveY(hitbox IRect, amt float64, clip ClipFunc) (actual int, res
 	return a.scene.MoveY(hitbox, amt, clip)
 }
 
+// Sweep moves this actor's hitbox diagonally by the given velocity, returning the actual displacement and any solid
+// collisions along each axis. See PlatformerScene.Sweep.
+func (a *Actor) Sweep(hitbox IRect, vel Vec2, clipX, clipY ClipFunc) (dx, dy int, xMask, yMask CollideMask) {
+	return a.scene.Sweep(hitbox, vel, clipX, clipY)
+}
+
 // CellAt provides the coordinates and contents of the cell containing the provided point.
 func (a *Actor) CellAt(point Vec2) (Vec2, CollideMask) {
 	return a.scene.at(point)
@@ -283,9 +974,7 @@ func (a *Actor) CellAt(point Vec2) (Vec2, CollideMask) {
 
 // Collides performs a collision test for this actor, returning the collidemask found.
 func (a *Actor) Collides(hitbox IRect) CollideMask {
-	return a.scene.Collides(hitbox, func(mask CollideMask) bool {
-		return false
-	})
+	return a.scene.Collides(hitbox, ClipNone)
 }
 
 type IntGridData uint32
@@ -295,6 +984,11 @@ const (
 	IntGridDirt
 	IntGridLadder
 	IntGridStone
+	IntGridMagnet
+	IntGridBreakable
+	IntGridIce
+	IntGridBounce
+	IntGridBorder       // IntGridBorder is returned for cells past the edges of a level which lead nowhere.
 	IntGridLadderTop    = IntGridLadder | (1 << 31)
 	IntGridLadderBottom = IntGridLadder | (1 << 30)
 	IntGridOneWay       = 1 << 31 // OneWay solids are cells you cannot hit your head on.
@@ -305,7 +999,11 @@ func (d IntGridData) isLadder() bool {
 }
 
 func (d IntGridData) isSolid() bool {
-	return d == IntGridStone || d == IntGridDirt
+	switch d {
+	case IntGridStone, IntGridDirt, IntGridMagnet, IntGridBreakable, IntGridIce, IntGridBounce:
+		return true
+	}
+	return false
 }
 
 func (d IntGridData) isOneWay() bool {
@@ -313,11 +1011,26 @@ func (d IntGridData) isOneWay() bool {
 }
 
 func (d IntGridData) CollideMask() CollideMask {
+	flags := CollideMask(d & (0xc0000000))
 	newd := d & (0x3fffffff) // unset flags.
 	if newd == 0 {
-		return CollideNone
+		return flags
+	}
+	return CollideMask(1<<(newd-1)) | flags // reset flags
+}
+
+// Describe returns a human-readable, pipe-delimited list of the cell type and flags set in this data, e.g.
+// "Ladder | LadderTop | OneWay".
+func (d IntGridData) Describe() string {
+	return d.CollideMask().Describe()
+}
+
+// intGridDataFor returns the IntGridData whose CollideMask is the provided mask, which must have a single bit set.
+func intGridDataFor(mask CollideMask) IntGridData {
+	if mask == CollideNone {
+		return IntGridNothing
 	}
-	return CollideMask(1<<(newd-1)) | CollideMask(d&(0xc0000000)) // reset flags
+	return IntGridData(bits.TrailingZeros32(uint32(mask)) + 1)
 }
 
 // CollideMask is a bitmask according to the following diagram.
@@ -328,14 +1041,48 @@ const (
 	CollideDirt = 1 << (iota - 1)
 	CollideLadder
 	CollideStone
-	CollidedSolid                = CollideDirt | CollideStone // solids are solid underfoot
+	CollideMagnet
+	CollideBreakable
+	CollideIce
+	CollideBounce
+	// CollideBorder is found past the edges of a level which lead nowhere.
+	CollideBorder
+	CollidedSolid                = CollideDirt | CollideStone | CollideMagnet | CollideBreakable | CollideIce | CollideBounce | CollideBorder // solids are solid underfoot
 	CollideLadderTop CollideMask = CollideLadder | (1 << 31)
 	CollideLadderBot CollideMask = CollideLadder | (1 << 30)
 	CollidedOneWay   CollideMask = 1 << 31
 )
 
+// A ClipFunc returns true if an actor should pass through cells with the provided CollideMask.
 type ClipFunc func(CollideMask) bool
 
+// ClipNone never clips through anything.
+var ClipNone ClipFunc = func(_ CollideMask) bool { return false }
+
+// ClipAll returns a ClipFunc which clips only when all the provided funcs clip.
+func ClipAll(funcs ...ClipFunc) ClipFunc {
+	return func(mask CollideMask) bool {
+		for _, f := range funcs {
+			if !f(mask) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ClipAny returns a ClipFunc which clips when any of the provided funcs clip.
+func ClipAny(funcs ...ClipFunc) ClipFunc {
+	return func(mask CollideMask) bool {
+		for _, f := range funcs {
+			if f(mask) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // Colliding returns false if the provided ClipFunc clips through the provided mask, otherwise
 // returns whether the provided collide mask is considered a solid object.
 func (m CollideMask) Colliding(clip ClipFunc) bool {
@@ -345,6 +1092,48 @@ func (m CollideMask) Colliding(clip ClipFunc) bool {
 	return m&CollidedSolid > 0 || (m&CollidedOneWay) == CollidedOneWay
 }
 
+// collideMaskNames names each cell type bit of a CollideMask, in bit order.
+var collideMaskNames = []struct {
+	bit  CollideMask
+	name string
+}{
+	{CollideDirt, "Dirt"},
+	{CollideLadder, "Ladder"},
+	{CollideStone, "Stone"},
+	{CollideMagnet, "Magnet"},
+	{CollideBreakable, "Breakable"},
+	{CollideIce, "Ice"},
+	{CollideBounce, "Bounce"},
+	{CollideBorder, "Border"},
+}
+
+// Describe returns a human-readable, pipe-delimited list of the cell types and flags set in this mask, e.g.
+// "Stone | Solid". Ladder tops are also one-way, so both are listed. "None" is returned if nothing is set.
+func (m CollideMask) Describe() string {
+	var parts []string
+	for _, n := range collideMaskNames {
+		if m&n.bit > 0 {
+			parts = append(parts, n.name)
+		}
+	}
+	if m&CollidedSolid > 0 {
+		parts = append(parts, "Solid")
+	}
+	if m&CollideLadderTop == CollideLadderTop {
+		parts = append(parts, "LadderTop")
+	}
+	if m&CollideLadderBot == CollideLadderBot {
+		parts = append(parts, "LadderBottom")
+	}
+	if m&CollidedOneWay == CollidedOneWay {
+		parts = append(parts, "OneWay")
+	}
+	if len(parts) == 0 {
+		return "None"
+	}
+	return strings.Join(parts, " | ")
+}
+
 // MoveX attempts to move a sprite with the provided hitbox by the provided amount in the X-direction, which may be
 // positive or negative. Returns the actual amount moved without colliding with a solid object and any items currently
 // collided with. MoveX only moves the provided box by integer amounts. Callers are responsible for managing the state
@@ -358,27 +1147,89 @@ func (s *PlatformerScene) MoveY(hitbox IRect, amt float64, clip ClipFunc) (actua
 	return s.move(hitbox, amt, IVec2{X: 0, Y: 1}, clip)
 }
 
+// MaxSingleFrameDisplacement returns the furthest move displaces a hitbox in a single step. Steps stay one pixel short
+// of a cell, so no step can carry a hitbox past a wall.
+func (s *PlatformerScene) MaxSingleFrameDisplacement() int {
+	return max(s.cellSize-1, 1)
+}
+
 // move moves the provided hitbox by the requested amount along the provided axis. The provided velocity is used to
-// ensure that one-way platforms are handled appropriately.
+// ensure that one-way platforms are handled appropriately. Large amounts are split into steps of at most
+// MaxSingleFrameDisplacement, so they cannot tunnel through walls no matter how many cells they span.
 func (s *PlatformerScene) move(hitbox IRect, amount float64, axis IVec2, clip ClipFunc) (actual int, result CollideMask) {
 	move := int(math.Round(amount))
 	if move == 0 {
 		return 0, s.AllOverlapping(hitbox)
 	}
-	actualMoved := 0
-	sign := int(math.Copysign(1, amount))
+	maxStep := s.MaxSingleFrameDisplacement()
 	for move != 0 {
-		displacement := axis.Scale(sign)
+		step := sign(move) * min(abs(move), maxStep)
+		moved, collideMask := s.moveStep(hitbox, step, axis, clip)
+		hitbox = hitbox.Add(axis.Scale(moved))
+		actual += moved
+		move -= step
+		if moved != step {
+			return actual, collideMask
+		}
+	}
+	return actual, 0 // no collision
+}
+
+// moveStep moves the provided hitbox by up to step pixels along the provided axis, one pixel at a time, testing
+// collision at each pixel. Returns the distance moved and the cells collided with, if any.
+func (s *PlatformerScene) moveStep(hitbox IRect, step int, axis IVec2, clip ClipFunc) (actual int, result CollideMask) {
+	dir := sign(step)
+	displacement := axis.Scale(dir)
+	for actual != step {
 		collideMask := s.Collides(hitbox.Add(displacement), clip)
-		if !collideMask.Colliding(clip) {
-			hitbox = hitbox.Add(displacement)
-			move -= sign
-			actualMoved += sign
+		if collideMask.Colliding(clip) {
+			return actual, collideMask
+		}
+		hitbox = hitbox.Add(displacement)
+		actual += dir
+	}
+	return actual, 0
+}
+
+// Sweep attempts to move a sprite with the provided hitbox by the provided velocity, interleaving single-pixel steps
+// along each axis so that diagonal movement cannot cut through corners. Once a step along one axis collides, the
+// remaining movement along that axis is abandoned. Returns the actual displacement along each axis and the cells
+// collided with along each axis, as in MoveX and MoveY.
+func (s *PlatformerScene) Sweep(hitbox IRect, vel Vec2, clipX, clipY ClipFunc) (dx, dy int, xMask, yMask CollideMask) {
+	moveX, moveY := int(math.Round(vel.X)), int(math.Round(vel.Y))
+	if moveX == 0 {
+		xMask = s.AllOverlapping(hitbox)
+	}
+	if moveY == 0 {
+		yMask = s.AllOverlapping(hitbox)
+	}
+	stepX, stepY := sign(moveX), sign(moveY)
+	remX, remY := abs(moveX), abs(moveY)
+	blockedX, blockedY := false, false
+	err := remX - remY // decides which axis to step along next, as in Bresenham's line algorithm.
+	for (remX > 0 && !blockedX) || (remY > 0 && !blockedY) {
+		alongX := remY == 0 || blockedY || (remX > 0 && !blockedX && err >= 0)
+		if alongX {
+			displaced := hitbox.Add(IVec2{X: stepX})
+			if mask := s.Collides(displaced, clipX); mask.Colliding(clipX) {
+				xMask, blockedX = mask, true
+				continue
+			}
+			hitbox, dx = displaced, dx+stepX
+			remX--
+			err -= 2 * abs(moveY)
 		} else {
-			return actualMoved, collideMask
+			displaced := hitbox.Add(IVec2{Y: stepY})
+			if mask := s.Collides(displaced, clipY); mask.Colliding(clipY) {
+				yMask, blockedY = mask, true
+				continue
+			}
+			hitbox, dy = displaced, dy+stepY
+			remY--
+			err += 2 * abs(moveX)
 		}
 	}
-	return actualMoved, 0 // no collision
+	return dx, dy, xMask, yMask
 }
 
 // cellOver returns the contents and coordinates of the unique cell closest to the bottom of the provided hitbox.
@@ -401,33 +1252,55 @@ func (s *PlatformerScene) Collides(hitbox IRect, clip ClipFunc) (result CollideM
 		result = result | dat.CollideMask()
 		return false
 	}
-	collidesBot := func(x, y float64) bool { // tests collisions, one-way platforms are only solid when not travelling upwards.
-		dat := s.gridData(x, y)
-		if clip(dat.CollideMask()) {
-			return false
-		}
-		result = result | dat.CollideMask()
-		return false
-	}
 
-	collidesBot(x2, y2) // bottom-right corner
-	collidesBot(x1, y2) // bottom-left corner
-	collides(x2, y1)    // top-right corner
-	collides(x1, y1)    // top-left corner
+	collides(x2, y2) // bottom-right corner
+	collides(x1, y2) // bottom-left corner
+	collides(x2, y1) // top-right corner
+	collides(x1, y1) // top-left corner
 
-	forAllVLine(x2, y1, y2, collides)    // right edge
-	forAllVLine(x1, y1, y2, collides)    // left edge
-	forAllHLine(x1, x2, y1, collides)    // top edge
-	forAllHLine(x1, x2, y2, collidesBot) // bottom edge
+	forAllVLine(x2, y1, y2, collides) // right edge
+	forAllVLine(x1, y1, y2, collides) // left edge
+	forAllHLine(x1, x2, y1, collides) // top edge
+	forAllHLine(x1, x2, y2, collides) // bottom edge
+	result |= s.oneWayCollides(hitbox, y2, clip)
 
 	for x := x1 + 0.5; x < x2; x += 0.5 {
 		for y := y1 + 0.5; y < y2; y += 0.5 {
 			collides(x, y)
 		}
 	}
+	if doors := s.doorCollides(hitbox); !clip(doors) {
+		result |= doors
+	}
 	return result
 }
 
+// oneWayCollides samples the center of each pixel along the bottom edge of the provided hitbox, which lies at y. The
+// one-way flag of one-way platforms is only reported if at least OneWayThreshold of the samples hit them, so that an
+// actor moving diagonally does not land on a platform it only clips by a corner. Ladder tops are always reported in
+// full, since climbing depends on their one-way flag.
+func (s *PlatformerScene) oneWayCollides(hitbox IRect, y float64, clip ClipFunc) CollideMask {
+	samples := max(hitbox.W, 1)
+	var platforms, ladderTops CollideMask
+	hits := 0
+	for i := 0; i < samples; i++ {
+		dat := s.gridData(float64(hitbox.X+i)+0.5, y)
+		if !dat.isOneWay() || clip(dat.CollideMask()) {
+			continue
+		}
+		if dat.isLadder() {
+			ladderTops |= dat.CollideMask()
+			continue
+		}
+		hits++
+		platforms |= dat.CollideMask()
+	}
+	if hits < int(math.Ceil(float64(samples)*OneWayThreshold)) {
+		platforms &^= CollidedOneWay
+	}
+	return platforms | ladderTops
+}
+
 // AllOverlapping retrieves all cells which the provided hitbox overlaps.
 func (s *PlatformerScene) AllOverlapping(hitbox IRect) (result CollideMask) {
 	const eps = 1e-3
@@ -445,39 +1318,61 @@ func (s *PlatformerScene) gridData(x, y float64) IntGridData {
 	return s.gridDataI(cx, cy)
 }
 
-// gridDataI retrieves grid data using cell coordinates (cx, cy).
+// gridDataI retrieves grid data using cell coordinates (cx, cy). Cells outside the current level are IntGridBorder
+// past any edge of the level which does not lead to a neighbouring level, and IntGridNothing otherwise.
 func (s *PlatformerScene) gridDataI(cx, cy int) IntGridData {
-	idx := cx + cy*s.cellsWide
-	if idx < 0 || idx >= len(s.intGridData) {
-		return 0
+	idx, ok := s.collisionLayer.cellIdx(cx, cy)
+	if !ok {
+		if s.isBorder(cx, cy) {
+			return IntGridBorder
+		}
+		return IntGridNothing
 	}
 	return s.intGridData[idx]
 }
 
+// isBorder returns true if the provided cell lies outside an edge of the current level which has no neighbouring level.
+func (s *PlatformerScene) isBorder(cx, cy int) bool {
+	dims := s.collisionLayer.CellDims
+	switch {
+	case cx < 0:
+		return !s.openEdges[IVec2{X: -1}]
+	case cx >= dims.W:
+		return !s.openEdges[IVec2{X: 1}]
+	case cy < 0:
+		return !s.openEdges[IVec2{Y: -1}]
+	case cy >= dims.H:
+		return !s.openEdges[IVec2{Y: 1}]
+	}
+	return false
+}
+
+// loadOpenEdges records which edges of the provided level lead to a neighbouring level.
+func (s *PlatformerScene) loadOpenEdges(level *Level) {
+	s.openEdges = make(map[IVec2]bool, 4)
+	for _, dir := range []IVec2{{X: -1}, {X: 1}, {Y: -1}, {Y: 1}} {
+		_, s.openEdges[dir] = s.gdat.AdjacentLevel(level, dir)
+	}
+}
+
 // setGridDataI sets grid data. If the cell provided is outside of the range of the currently loaded level, this
 // func is a no-op.
 func (s *PlatformerScene) setGridDataI(cx, cy int, dat IntGridData) {
-	idx := cx + cy*s.cellsWide
-	if idx < 0 || idx >= len(s.intGridData) {
-		return
+	if idx, ok := s.collisionLayer.cellIdx(cx, cy); ok {
+		s.intGridData[idx] = dat
 	}
-	s.intGridData[idx] = dat
 }
 
-// screenToCell rounds the provided screen coordinates (x, y) to cell coordinates (cx, cy)
+// screenToCell rounds the provided screen coordinates (x, y) down to cell coordinates (cx, cy)
 func (s *PlatformerScene) screenToCell(x, y float64) (int, int) {
-	return int(x / float64(s.cellSize)), int(y / float64(s.cellSize))
+	return int(math.Floor(x / float64(s.cellSize))), int(math.Floor(y / float64(s.cellSize)))
 }
 
 // forAllGridData loops over the grid data, calling f at each cell.
 func (s *PlatformerScene) forAllGridData(f func(cx int, cy int, dat IntGridData)) {
-	w := s.cellsWide
-	total := len(s.intGridData)
-	for x := 0; x < w; x++ {
-		for y := 0; y < total/w; y++ {
-			f(x, y, s.intGridData[x+y*w])
-		}
-	}
+	s.collisionLayer.ForEachCell(func(cx, cy int, _ int) {
+		f(cx, cy, s.gridDataI(cx, cy))
+	})
 }
 
 // forAllHLine visits all points in an half-integer grid which overlap the provided horizontal line, excluding the endpoints.
@@ -0,0 +1,311 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"github.com/hajimehoshi/ebiten/v2"
+	"testing"
+)
+
+func TestSceneWithoutPlayer(t *testing.T) {
+	s := newTestScene(t, grid(4, 4)...)
+	if s.player != nil {
+		t.Fatalf("newTestScene spawned a player; want none")
+	}
+	s.debug = true
+	screen := ebiten.NewImage(s.Layout(0, 0))
+	for i := 0; i < 3; i++ { // must not panic.
+		if err := s.Update(); err != nil {
+			t.Fatalf("Update() = %v; want nil", err)
+		}
+		s.Draw(screen)
+	}
+}
+
+func TestClipCombinators(t *testing.T) {
+	always := func(CollideMask) bool { return true }
+	never := ClipNone
+	tests := []struct {
+		name string
+		clip ClipFunc
+		want bool
+	}{
+		{name: "ClipAll(always, never)", clip: ClipAll(always, never), want: false},
+		{name: "ClipAll(always, always)", clip: ClipAll(always, always), want: true},
+		{name: "ClipAll()", clip: ClipAll(), want: true},
+		{name: "ClipAny(never, always)", clip: ClipAny(never, always), want: true},
+		{name: "ClipAny(never, never)", clip: ClipAny(never, never), want: false},
+		{name: "ClipAny()", clip: ClipAny(), want: false},
+	}
+	for _, tt := range tests {
+		for _, mask := range []CollideMask{CollideNone, CollideDirt, CollideLadderTop} {
+			if got := tt.clip(mask); got != tt.want {
+				t.Errorf("%s(%s) = %v; want %v", tt.name, mask.Describe(), got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReloadKeepsCollectedCoins(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	registerTestLevel(s, 1)
+	p := newTestPlayer(t, s, IVec2{X: 64, Y: 64})
+	loadTestEntities(t, s,
+		&Entity{ID: EtyCoin, PxCoords: p.Hitbox().Center(), Dim: IDim{W: 8, H: 8}},
+		&Entity{ID: EtyCoin, PxCoords: IVec2{X: 200, Y: 64}, Dim: IDim{W: 8, H: 8}},
+	)
+	s.updateCollectibles()
+	if s.coinCount != 1 {
+		t.Fatalf("coinCount = %d after collecting a coin; want 1", s.coinCount)
+	}
+
+	if err := s.Reload(s.gdat); err != nil {
+		t.Fatalf("could not reload: %v", err)
+	}
+	if s.coinCount != 1 {
+		t.Errorf("coinCount = %d after reload; want 1", s.coinCount)
+	}
+	if len(s.coins) != 1 {
+		t.Errorf("%d coins remain after reload; want 1", len(s.coins))
+	}
+	s.updateCollectibles()
+	if s.coinCount != 1 {
+		t.Errorf("coinCount = %d after standing where the collected coin was; want 1", s.coinCount)
+	}
+}
+
+func TestMoveXStopsAtWall(t *testing.T) {
+	s := newTestScene(t,
+		"............",
+		".....S......",
+		"SSSSSSSSSSSS",
+	)
+	wallX := 5 * testCellSize
+	tests := []struct {
+		name   string
+		startX int
+		amt    float64
+		want   int
+	}{
+		{name: "right", startX: 0, amt: 5 * testCellSize, want: wallX - testCellSize},
+		{name: "far right", startX: 0, amt: 20 * testCellSize, want: wallX - testCellSize},
+		{name: "left", startX: 10 * testCellSize, amt: -5 * testCellSize, want: wallX + testCellSize},
+		{name: "one cell", startX: 3*testCellSize + 8, amt: testCellSize, want: wallX - testCellSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hitbox := IRect{X: tt.startX, Y: testCellSize, W: testCellSize, H: testCellSize}
+			actual, mask := s.MoveX(hitbox, tt.amt, ClipNone)
+			if got := tt.startX + actual; got != tt.want {
+				t.Errorf("MoveX(%v) stopped at x=%d; want %d, against the wall", tt.amt, got, tt.want)
+			}
+			if mask&CollideStone == 0 {
+				t.Errorf("MoveX(%v) collided with %s; want Stone", tt.amt, mask.Describe())
+			}
+		})
+	}
+}
+
+func TestMoveCrossesOpenCellsInSteps(t *testing.T) {
+	s := newTestScene(t, grid(12, 3)...)
+	if got, want := s.MaxSingleFrameDisplacement(), testCellSize-1; got != want {
+		t.Fatalf("MaxSingleFrameDisplacement() = %d; want %d", got, want)
+	}
+	hitbox := IRect{Y: testCellSize, W: testCellSize, H: testCellSize}
+	if actual, mask := s.MoveX(hitbox, 5*testCellSize, ClipNone); actual != 5*testCellSize || mask != CollideNone {
+		t.Errorf("MoveX(%d) across open cells = %d, %s; want %d, None", 5*testCellSize, actual, mask.Describe(),
+			5*testCellSize)
+	}
+}
+
+func TestMoveXStopsAtLevelBorder(t *testing.T) {
+	s := newTestScene(t, grid(10, 3)...)
+	right := 10 * testCellSize
+	hitbox := IRect{X: right - testCellSize - 4, Y: testCellSize, W: testCellSize, H: testCellSize}
+
+	actual, mask := s.MoveX(hitbox, 20, ClipNone)
+	if got := hitbox.X + hitbox.W + actual; got != right {
+		t.Errorf("MoveX() stopped with the hitbox's right edge at %d; want %d, the level's edge", got, right)
+	}
+	if mask&CollideBorder == 0 {
+		t.Errorf("MoveX() into the level's edge collided with %s; want Border", mask.Describe())
+	}
+	if actual, mask := s.MoveX(IRect{X: 4, Y: testCellSize, W: testCellSize, H: testCellSize}, -20, ClipNone); actual != -4 ||
+		mask&CollideBorder == 0 {
+		t.Errorf("MoveX() into the level's left edge = %d, %s; want -4, Border", actual, mask.Describe())
+	}
+
+	s.openEdges = map[IVec2]bool{{X: 1}: true}
+	if actual, mask := s.MoveX(hitbox, 20, ClipNone); actual != 20 || mask != CollideNone {
+		t.Errorf("MoveX() past an edge leading to another level = %d, %s; want 20, None", actual, mask.Describe())
+	}
+}
+
+// newTestCoopScene returns a scene with both players spawned on the floor, player 2 to the right of player 1.
+func newTestCoopScene(t *testing.T) *PlatformerScene {
+	t.Helper()
+	s := newTestScene(t, grid(20, 6)...)
+	loadTestEntities(t, s,
+		&Entity{ID: EtyPlayer, PxCoords: IVec2{X: 2 * testCellSize, Y: 3 * testCellSize}},
+		&Entity{ID: EtyPlayer2, PxCoords: IVec2{X: 14 * testCellSize, Y: 3 * testCellSize}},
+	)
+	if s.player2 == nil {
+		t.Fatalf("player 2 did not spawn")
+	}
+	for _, p := range s.players() {
+		setFeet(p, IVec2{X: p.Hitbox().Center().X, Y: 5 * testCellSize})
+	}
+	return s
+}
+
+func TestCoopPlayersHaveIndependentStates(t *testing.T) {
+	s := newTestCoopScene(t)
+	p1, p2 := s.player, s.player2
+	for i := 0; i < 10; i++ {
+		updatePlayer(p1, InputWalkedRight)
+		updatePlayer(p2, InputNone)
+	}
+	if p1.state() != PlayerStateWalking {
+		t.Errorf("player 1 state = %v while walking; want %v", p1.state(), PlayerStateWalking)
+	}
+	if p2.state() != PlayerStateIdle {
+		t.Errorf("player 2 state = %v while player 1 walks; want %v", p2.state(), PlayerStateIdle)
+	}
+	updatePlayer(p2, InputJumped)
+	updatePlayer(p1, InputNone)
+	if p2.state() != PlayerStateJumping || p1.state() == PlayerStateJumping {
+		t.Errorf("states = %v, %v after only player 2 jumped; want player 2 alone %v", p1.state(), p2.state(),
+			PlayerStateJumping)
+	}
+}
+
+func TestCoopPlayer2Interacts(t *testing.T) {
+	s := newTestCoopScene(t)
+	hitbox := s.player2.Hitbox()
+	coin := &Entity{ID: EtyCoin, IID: uuid.New(), PxCoords: hitbox.Center(), Dim: IDim{W: 4, H: 4}}
+	powerUp := &Entity{ID: EtyPowerUpDoubleJump, IID: uuid.New(), PxCoords: hitbox.Center(), Dim: IDim{W: 4, H: 4}}
+	s.coins = append(s.coins, coin)
+	s.powerUps = append(s.powerUps, powerUp)
+	s.updateCollectibles()
+	if s.coinCount != 1 || len(s.coins) != 0 {
+		t.Errorf("coinCount = %d with %d coins left after player 2 touched the only coin; want 1 with 0 left",
+			s.coinCount, len(s.coins))
+	}
+	if s.player2.MaxAirJumps != 2 || s.player.MaxAirJumps == 2 {
+		t.Errorf("MaxAirJumps = %d, %d after player 2 took a double jump; want only player 2 to have 2",
+			s.player.MaxAirJumps, s.player2.MaxAirJumps)
+	}
+
+	door := NewSliderDoor(&Entity{ID: EtySliderDoor, PxCoords: IVec2{X: 8 * testCellSize, Y: 3 * testCellSize},
+		Dim: IDim{W: testCellSize, H: 2 * testCellSize}, Fields: map[string]any{"trigger_id": "gate",
+			"open_offset": map[string]any{"cx": 0.0, "cy": -2.0}}}, testCellSize)
+	s.doors = append(s.doors, door)
+	s.switches = append(s.switches, &Entity{ID: EtySwitch, IID: uuid.New(), PxCoords: hitbox.Center(),
+		Dim: IDim{W: 4, H: 4}, Fields: map[string]any{"trigger_id": "gate"}})
+	s.updateSwitches()
+	s.updateSequences()
+	if door.target != door.basePos.Add(door.openOffset).Vec2() {
+		t.Errorf("door is closed after player 2 pressed its switch; want it opening")
+	}
+}
+
+func TestEnemiesTargetNearestPlayer(t *testing.T) {
+	s := newTestCoopScene(t)
+	if got := s.nearestPlayer(IVec2{X: 17 * testCellSize}); got != s.player2 {
+		t.Errorf("nearestPlayer() beside player 2 returned player 1")
+	}
+	if got := s.nearestPlayer(IVec2{X: 0}); got != s.player {
+		t.Errorf("nearestPlayer() beside player 1 returned player 2")
+	}
+	e := newTestJumpEnemy(t, s, 16*testCellSize, 15*testCellSize, 18*testCellSize)
+	if !e.playerInRange() {
+		t.Fatalf("playerInRange() = false beside player 2; want true")
+	}
+	e.startJumping()
+	if e.dir != -1 {
+		t.Errorf("jumper leapt in direction %d; want -1, toward player 2", e.dir)
+	}
+}
+
+func TestOneWayPlatformThreshold(t *testing.T) {
+	s := newTestScene(t,
+		"......",
+		"#H..-.",
+		".H....",
+		"######",
+	)
+	top := testCellSize + 1 - 16 // a 16px hitbox whose bottom edge lies 1px inside row 1.
+	tests := []struct {
+		name    string
+		x       int
+		want    CollideMask
+		wantNot CollideMask
+	}{
+		{name: "platform 1px corner", x: 5*testCellSize - 1, wantNot: CollidedOneWay},
+		{name: "platform 7px", x: 4*testCellSize + 9, wantNot: CollidedOneWay},
+		{name: "platform half", x: 4*testCellSize + 8, want: CollidedOneWay},
+		{name: "platform full", x: 4 * testCellSize, want: CollidedOneWay},
+		{name: "ladder top 1px corner", x: 2*testCellSize - 1, want: CollideLadderTop},
+		{name: "ladder top full", x: testCellSize, want: CollideLadderTop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Collides(IRect{X: tt.x, Y: top, W: 16, H: 16}, ClipNone)
+			if got&tt.want != tt.want {
+				t.Errorf("Collides() = %s; want %s", got.Describe(), tt.want.Describe())
+			}
+			if tt.wantNot != 0 && got&tt.wantNot != 0 {
+				t.Errorf("Collides() = %s; want no %s", got.Describe(), tt.wantNot.Describe())
+			}
+		})
+	}
+}
+
+func TestLocalToWorld(t *testing.T) {
+	tests := []struct {
+		name  string
+		level *Level
+		local IVec2
+		world IVec2
+	}{
+		{name: "nil level", local: IVec2{X: 3, Y: -4}, world: IVec2{X: 3, Y: -4}},
+		{name: "origin", level: &Level{}, local: IVec2{X: 3, Y: 4}, world: IVec2{X: 3, Y: 4}},
+		{
+			name:  "positive world coords",
+			level: &Level{WorldCoords: IVec2{X: 512, Y: 256}},
+			local: IVec2{X: 3, Y: 4},
+			world: IVec2{X: 515, Y: 260},
+		},
+		{
+			name:  "negative world coords",
+			level: &Level{WorldCoords: IVec2{X: -512, Y: -256}},
+			local: IVec2{X: 3, Y: 4},
+			world: IVec2{X: -509, Y: -252},
+		},
+		{
+			name:  "negative local point",
+			level: &Level{WorldCoords: IVec2{X: -512, Y: 256}},
+			local: IVec2{X: -16, Y: -8},
+			world: IVec2{X: -528, Y: 248},
+		},
+	}
+	for _, tt := range tests {
+		s := &PlatformerScene{level: tt.level}
+		if got := s.LocalToWorld(tt.local); got != tt.world {
+			t.Errorf("%s: LocalToWorld(%v) = %v; want %v", tt.name, tt.local, got, tt.world)
+		}
+		if got := s.WorldToLocal(tt.world); got != tt.local {
+			t.Errorf("%s: WorldToLocal(%v) = %v; want %v", tt.name, tt.world, got, tt.local)
+		}
+		if got := s.WorldToLocal(s.LocalToWorld(tt.local)); got != tt.local {
+			t.Errorf("%s: WorldToLocal(LocalToWorld(%v)) = %v; want %v", tt.name, tt.local, got, tt.local)
+		}
+	}
+}
+
+func TestLevelWorldRect(t *testing.T) {
+	level := &Level{WorldCoords: IVec2{X: -256, Y: 128}, PxDims: IDim{W: 320, H: 180}}
+	want := IRect{X: -256, Y: 128, W: 320, H: 180}
+	if got := level.WorldRect(); got != want {
+		t.Errorf("WorldRect() = %v; want %v", got, want)
+	}
+}
@@ -1,11 +1,12 @@
 package internal
 
 import (
-	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"image"
-	"log"
+	"image/color"
+	"log/slog"
 	"math"
 )
 
@@ -24,6 +25,24 @@ const PlayerMaxRunSpeed = 5      // PlayerMaxRunSpeed is how quickly the player
 const PlayerMaxLadderSpeed = 2   // PlayerMaxLadderSpeed is how quickly the player moves up and down ladders.
 const PlayerClimbAccel = 0.5     // PlayerClimbAccel is the acceleration the player uses when climbing.
 const PlayerOneWayLiftForce = 3  // PlayerOneWayLiftForce is the force on the player when they are being lifted through one-way platforms.
+const NoclipSpeed = 4            // NoclipSpeed is how quickly the player flies while noclip is enabled.
+const PlayerMaxHP = 3            // PlayerMaxHP is the player's health when they are at full health.
+const PlayerHurtStunFrames = 20  // PlayerHurtStunFrames is the number of frames the player is stunned for after taking damage.
+const PlayerKnockbackX = 3       // PlayerKnockbackX is the X velocity the player is knocked back with when taking damage.
+const PlayerKnockbackY = 4       // PlayerKnockbackY is the upward velocity the player is knocked back with when taking damage.
+const PlayerMaxAirJumps = 1      // PlayerMaxAirJumps is the default number of jumps the player can make while airborne.
+const GrappleMaxRange = 96       // GrappleMaxRange is the furthest distance in pixels from which the player can grapple.
+const GrappleSwingAccel = 0.2    // GrappleSwingAccel is the acceleration the player uses in the X-direction when swinging.
+const GrappleRopeSpacing = 4     // GrappleRopeSpacing is the distance in pixels between each dot drawn along the rope.
+const PlayerAttackDamage = 1     // PlayerAttackDamage is the damage dealt to an enemy by each melee attack.
+const PlayerAttackReach = 16     // PlayerAttackReach is how far in pixels the attack hitbox extends in front of the player.
+const ParryWindow = 6            // ParryWindow is the number of frames after pressing parry in which a parry can succeed.
+const ParryRadius = 12           // ParryRadius is the distance in pixels from the player's center within which projectiles are parried.
+const PlayerParryDamage = 2      // PlayerParryDamage is the damage dealt to enemies by a parried projectile.
+const InvincibilityFrames = 30   // InvincibilityFrames is the number of frames the player cannot be damaged after a parry.
+const PlayerIceAccelScale = 0.25 // PlayerIceAccelScale multiplies the player's acceleration while on ice.
+const PlayerSkidSpeed = 1        // PlayerSkidSpeed is the X speed above which the player skids when trying to stop on ice.
+const iceChunkPeriod = 3         // iceChunkPeriod is the number of frames between each ice chunk kicked up while sliding.
 
 // PlayerInput is a bit vector identifying which buttons are currently being pressed.
 type PlayerInput uint32
@@ -36,6 +55,9 @@ const (
 	InputClimbedDown                               // InputClimbedDown is set when the climb (up) button is held.
 	InputRunning                                   // InputRunning is set when the run button is held.
 	InputJumped                                    // InputJumped is set when the jump button is held.
+	InputGrappled                                  // InputGrappled is set when the grapple button is held.
+	InputAttacked                                  // InputAttacked is set when the attack button is held.
+	InputParry                                     // InputParry is set when the parry button is held.
 
 	InputWalked  PlayerInput = InputWalkedRight | InputWalkedLeft // InputWalked is an input mask which doesn't distinguish between the direction walked.
 	InputClimbed PlayerInput = InputClimbedUp | InputClimbedDown  // InputClimbed is an input mask which doesn't distinguish between climbing up or down.
@@ -64,6 +86,14 @@ const (
 	PlayerStateLeaping
 	PlayerStateLadderClimbing
 	PlayerStateOneWayClimbing // PlayerStateOneWayClimbing means the player is climbing up through a one-way platform.
+	PlayerStateHurt           // PlayerStateHurt means the player has taken damage and is briefly stunned.
+	PlayerStateDead           // PlayerStateDead means the player has run out of HP.
+	PlayerStateGrappling      // PlayerStateGrappling means the player is swinging from a grapple anchor.
+	PlayerStateAttacking      // PlayerStateAttacking means the player is swinging a melee attack.
+	PlayerStateZipping        // PlayerStateZipping means the player is sliding along a zip line.
+	PlayerStateSwinging       // PlayerStateSwinging means the player is swinging from a rope anchor.
+	PlayerStateSkidding       // PlayerStateSkidding means the player is sliding to a stop on ice.
+	PlayerStateFlying         // PlayerStateFlying means the player is flying freely with noclip enabled. Debug builds only.
 )
 
 func (s PlayerState) String() string {
@@ -84,6 +114,22 @@ func (s PlayerState) String() string {
 		return "LADDER"
 	case PlayerStateOneWayClimbing:
 		return "ONEWAY_CLIMB"
+	case PlayerStateHurt:
+		return "HURT"
+	case PlayerStateDead:
+		return "DEAD"
+	case PlayerStateGrappling:
+		return "GRAPPLE"
+	case PlayerStateAttacking:
+		return "ATTACK"
+	case PlayerStateZipping:
+		return "ZIP"
+	case PlayerStateSwinging:
+		return "SWING"
+	case PlayerStateSkidding:
+		return "SKID"
+	case PlayerStateFlying:
+		return "FLY"
 	default:
 		return "?!?!"
 	}
@@ -91,16 +137,66 @@ func (s PlayerState) String() string {
 
 type Player struct {
 	*Actor
-	state PlayerState // state is the player's current state.
-	Pos   IVec2       // pos is position in world coordinates.
-	Vel   Vec2        // vel is velocity in world coordinates.
+	states StateMachine[PlayerState] // states runs the update handler of the player's current state.
+	input  PlayerInput               // input is the input read during the current update.
+	// fallStartY is the Y coordinate at which the player last started falling.
+	fallStartY int
+	Pos        IVec2   // pos is position in world coordinates.
+	Vel        Vec2    // vel is velocity in world coordinates.
+	subX       float64 // subX is the fractional X movement carried over from prior frames.
+	subY       float64 // subY is the fractional Y movement carried over from prior frames.
 
-	keys []ebiten.Key
+	// effectiveTerminalVelocity is the player's max Y velocity when falling, which may be overridden by the zone they are
+	// in.
+	effectiveTerminalVelocity float64
+
+	useSweep bool // useSweep moves the player diagonally in a single pass, rather than one axis at a time.
+	HP       int  // HP is the player's remaining health.
+
+	inventory map[string]int // inventory counts the items the player is carrying, keyed by item ID.
+
+	keys     []ebiten.Key
+	controls InputConfig // controls maps the keys this player responds to onto PlayerInput flags.
+	// readInput returns the input held this frame. It reads the keyboard using handleInput unless replaced, e.g. by tests.
+	readInput func() PlayerInput
 
 	fallResetY    int         // y position past which fallClipmask is reset.
 	fallClipmask  CollideMask // fallClipmask is the clipmask set for this fall state. Reset after Y position has dropped
 	colliding     CollideMask
-	maxFallXSpeed float64 // maxFallXSpeed is the maximum fall speed allowed given how the player started to fall.
+	maxFallXSpeed float64     // maxFallXSpeed is the maximum fall speed allowed given how the player started to fall.
+	ghost         bool        // ghost is true while the player passes through walls.
+	noclip        bool        // noclip is true while the player ignores collision entirely. Toggled by F4 in debug builds.
+	hurtFrames    int         // hurtFrames is the number of frames remaining in PlayerStateHurt.
+	deathAnimDone bool        // deathAnimDone is set once the player has finished falling in the death animation.
+	deathHandled  bool        // deathHandled is set once OnDeath has been called for the current death.
+	prevInput     PlayerInput // prevInput is the input from the previous frame.
+	airJumpsLeft  int         // airJumpsLeft is the number of jumps the player can make before landing.
+	MaxAirJumps   int         // MaxAirJumps is the number of jumps the player can make while airborne.
+	grappleAnchor IVec2       // grappleAnchor is the point in world coordinates the player is swinging from.
+	grappleLength float64     // grappleLength is the length of the rope while grappling.
+	attackHitbox  IRect       // attackHitbox is the area in world coordinates hit by the current attack, or empty if inactive.
+	attackDone    bool        // attackDone is set once the attack animation has finished.
+	attackHits    []Enemy     // attackHits holds the enemies already struck by the current attack.
+
+	parryFramesLeft  int // parryFramesLeft is the number of frames, including this one, in which a parry can succeed.
+	invincibleFrames int // invincibleFrames is the number of frames remaining in which the player cannot be damaged.
+
+	zipLine ZipLine // zipLine is the zip line the player is sliding along.
+	zipDist float64 // zipDist is the distance the player has slid along zipLine.
+
+	ropeAnchor     Vec2    // ropeAnchor is the point in world coordinates the player is swinging from.
+	ropeLength     float64 // ropeLength is the distance from ropeAnchor to the player's center while swinging.
+	ropeAngle      float64 // ropeAngle is the angle of the rope in radians from straight down.
+	ropeAngularVel float64 // ropeAngularVel is the angular velocity of the swing in radians per frame.
+
+	teleporterWait     int      // teleporterWait is the number of frames the player has stood on a teleporter.
+	teleporterCooldown Cooldown // teleporterCooldown must elapse off of teleporters before the player can warp again.
+
+	stateHistory    [64]PlayerState // stateHistory is a ring buffer of the most recent states the player changed to.
+	stateHistoryIdx int             // stateHistoryIdx is the total number of states recorded in stateHistory.
+
+	// OnDeath is called once after the player has died and the death animation has finished.
+	OnDeath func()
 
 	sprite *PlayerSprite
 }
@@ -112,43 +208,126 @@ func NewPlayer(scene *PlatformerScene) (*Player, error) {
 	}
 	result := &Player{
 		Actor:  &Actor{scene: scene},
+		HP:     PlayerMaxHP,
 		sprite: sprite,
+
+		inventory: make(map[string]int),
+
+		controls:     Player1Controls,
+		MaxAirJumps:  PlayerMaxAirJumps,
+		airJumpsLeft: PlayerMaxAirJumps,
+
+		teleporterCooldown: NewCooldown(TeleporterCooldownFrames),
+
+		effectiveTerminalVelocity: PlayerTerminalVelocity,
+		useSweep:                  options.SweepMovement,
 	}
+	result.readInput = result.handleInput
+	result.registerStates()
 	result.sprite.Update()
+	result.sprite.OnAnimEnd(PlayerAnimAttack, func() { result.attackDone = true })
+	result.sprite.OnTagEnd(PlayerAnimDead, deadFallTag, func() {
+		result.deathAnimDone = true
+		result.sprite.SetTag(deadDownTag) // lie still rather than looping the fall.
+	})
 	return result, nil
 }
 
 // Update updates the player this frame.
 func (p *Player) Update() {
 	p.sprite.Update()
-	input := p.handleInput()
-	nextState := p.state
+	var input PlayerInput
+	if p.state() != PlayerStateHurt && p.state() != PlayerStateDead { // all input is ignored while stunned or dead.
+		input = p.readInput()
+	}
+	if p.grapplePressed(input) && p.state() != PlayerStateGrappling {
+		if p.startGrappling() {
+			p.states.Set(PlayerStateGrappling)
+		}
+	}
+	if p.attackPressed(input) && p.canAttack() {
+		p.states.Set(p.startAttacking())
+	}
+	if p.invincibleFrames > 0 {
+		p.invincibleFrames--
+	}
+	if input&InputParry > 0 && p.prevInput&InputParry == 0 && p.parryFramesLeft == 0 {
+		p.parryFramesLeft = ParryWindow + 1 // the window counts the frames following the press.
+	}
+	p.updateParry()
 
-	switch p.state {
-	case PlayerStateIdle:
-		nextState = p.updateIdle(input)
-	case PlayerStateWalking:
-		nextState = p.updateWalking(input)
-	case PlayerStateFalling:
-		nextState = p.updateFalling(input)
-	case PlayerStateJumping:
-		nextState = p.updateJumping(input)
-	case PlayerStateRunning:
-		nextState = p.updateRunning(input)
-	case PlayerStateLeaping:
-		nextState = p.updateLeaping(input)
-	case PlayerStateLadderClimbing:
-		nextState = p.updateLadderClimbing(input)
-	case PlayerStateOneWayClimbing:
-		nextState = p.updateOneWayClimbing(input)
-	default:
-		panic("default!")
+	p.input = input
+	nextState := p.states.Update()
+
+	if p.jumpPressed(input) && p.canGrab() { // grabbing overrides whatever the jump press did this frame.
+		p.states.Set(p.startGrabbing(nextState))
 	}
+	p.prevInput = input
+}
+
+// registerStates registers the update handler of each player state.
+func (p *Player) registerStates() {
+	p.states = NewStateMachine[PlayerState](PlayerStateIdle)
+	handlers := map[PlayerState]func(PlayerInput) PlayerState{
+		PlayerStateIdle:           p.updateIdle,
+		PlayerStateWalking:        p.updateWalking,
+		PlayerStateFalling:        p.updateFalling,
+		PlayerStateJumping:        p.updateJumping,
+		PlayerStateRunning:        p.updateRunning,
+		PlayerStateLeaping:        p.updateLeaping,
+		PlayerStateLadderClimbing: p.updateLadderClimbing,
+		PlayerStateOneWayClimbing: p.updateOneWayClimbing,
+		PlayerStateHurt:           func(PlayerInput) PlayerState { return p.updateHurt() },
+		PlayerStateDead:           func(PlayerInput) PlayerState { return p.updateDead() },
+		PlayerStateGrappling:      p.updateGrappling,
+		PlayerStateAttacking:      p.updateAttacking,
+		PlayerStateZipping:        p.updateZipping,
+		PlayerStateSwinging:       p.updateSwinging,
+		PlayerStateSkidding:       p.updateSkidding,
+		PlayerStateFlying:         func(PlayerInput) PlayerState { return p.updateFlying() },
+	}
+	for state, update := range handlers {
+		update := update
+		p.states.Register(state, func(PlayerState) PlayerState { return update(p.input) })
+	}
+	p.states.OnTransition(func(from, to PlayerState) {
+		slog.Debug("player state changed", "from", from, "to", to)
+		if to == PlayerStateFalling {
+			p.fallStartY = p.Pos.Y
+		}
+		p.stateHistory[p.stateHistoryIdx%len(p.stateHistory)] = to
+		p.stateHistoryIdx++
+	})
+}
+
+// state returns the player's current state.
+func (p *Player) state() PlayerState {
+	return p.states.State()
+}
 
-	if nextState != p.state {
-		log.Printf("debug: player state changed from %s to %s", p.state, nextState)
+// StateHistory returns the most recent states the player has changed to, oldest first.
+func (p *Player) StateHistory() []PlayerState {
+	n := min(p.stateHistoryIdx, len(p.stateHistory))
+	result := make([]PlayerState, 0, n)
+	for i := p.stateHistoryIdx - n; i < p.stateHistoryIdx; i++ {
+		result = append(result, p.stateHistory[i%len(p.stateHistory)])
 	}
-	p.state = nextState
+	return result
+}
+
+// jumpPressed returns true if the jump button was pressed this frame, rather than held from a prior frame.
+func (p *Player) jumpPressed(input PlayerInput) bool {
+	return input&InputJumped > 0 && p.prevInput&InputJumped == 0
+}
+
+// grapplePressed returns true if the grapple button was pressed this frame, rather than held from a prior frame.
+func (p *Player) grapplePressed(input PlayerInput) bool {
+	return input&InputGrappled > 0 && p.prevInput&InputGrappled == 0
+}
+
+// attackPressed returns true if the attack button was pressed this frame, rather than held from a prior frame.
+func (p *Player) attackPressed(input PlayerInput) bool {
+	return input&InputAttacked > 0 && p.prevInput&InputAttacked == 0
 }
 
 // SetPos sets the players position without performing any collision testing. It should only be used on loading.
@@ -156,26 +335,133 @@ func (p *Player) SetPos(pos IVec2) {
 	p.Pos = pos
 }
 
+// TakeDamage reduces the player's health by the provided amount and knocks the player away from the source of the
+// damage. sourceDir is the sign of the direction from the player to the source in the X-direction. Damage taken while
+// the player is already stunned or dead is ignored. The player dies once they run out of HP.
+func (p *Player) TakeDamage(amount int, sourceDir int) {
+	if p.state() == PlayerStateHurt || p.state() == PlayerStateDead || p.invincibleFrames > 0 {
+		return
+	}
+	p.HP = max(p.HP-amount, 0)
+	if p == p.scene.player { // the HUD only tracks the first player.
+		p.scene.game.bus.Publish(TopicPlayerDamaged, p.HP)
+	}
+	if p.HP <= 0 {
+		p.startDying()
+		return
+	}
+
+	knockbackDir := -sign(sourceDir)
+	if knockbackDir == 0 {
+		knockbackDir = 1
+		if !p.sprite.facingLeft { // knock the player backwards when the source is directly above or below.
+			knockbackDir = -1
+		}
+	}
+	p.Vel = IVec2{X: knockbackDir * PlayerKnockbackX, Y: -PlayerKnockbackY}.Vec2()
+	p.hurtFrames = PlayerHurtStunFrames
+	p.sprite.Flash(PlayerHurtStunFrames)
+	p.states.Set(PlayerStateHurt)
+}
+
+// splitSubpixel splits the provided movement into whole pixels and the fractional remainder. Movements within a tiny
+// tolerance of a whole pixel are rounded to it, so that floating point error does not lose pixels.
+func splitSubpixel(total float64) (whole, remainder float64) {
+	const eps = 1e-9
+	whole = math.Trunc(total + math.Copysign(eps, total))
+	return whole, total - whole
+}
+
 // MoveX moves this player by X, updating its hitbox, velocity, and position as needed.
 func (p *Player) MoveX() CollideMask {
-	dx, collidesWith := p.Actor.MoveX(p.Hitbox(), p.Vel.X, p.clipsX)
+	if p.noclip {
+		var whole float64
+		whole, p.subX = splitSubpixel(p.Vel.X + p.subX)
+		p.Pos.X += int(whole)
+		return CollideNone
+	}
+	clip := ClipAny(p.clipsX, p.clipsGhost)
+	var whole float64
+	whole, p.subX = splitSubpixel(p.Vel.X + p.subX)
+	dx, collidesWith := p.Actor.MoveX(p.Hitbox(), whole, clip)
 	p.Pos.X += dx
-	if collidesWith.Colliding(p.clipsX) {
+	if collidesWith.Colliding(clip) {
 		p.Vel.X = 0
+		p.subX = 0
 	}
 	return collidesWith
 }
 
 // MoveY moves this player by Y, updating its hitbox, velocity, and position as needed.
 func (p *Player) MoveY() CollideMask {
-	dy, collidesWith := p.Actor.MoveY(p.Hitbox(), p.Vel.Y, p.clipsY)
+	if p.noclip {
+		var whole float64
+		whole, p.subY = splitSubpixel(p.Vel.Y + p.subY)
+		p.Pos.Y += int(whole)
+		return CollideNone
+	}
+	var whole float64
+	whole, p.subY = splitSubpixel(p.Vel.Y + p.subY)
+	dy, collidesWith := p.Actor.MoveY(p.Hitbox(), whole, p.clipsY)
 	p.Pos.Y += dy
 	if collidesWith.Colliding(p.clipsY) {
 		p.Vel.Y = 0
+		p.subY = 0
 	}
 	return collidesWith
 }
 
+// Move moves this player by its velocity along both axes, returning the cells collided with along each axis. If
+// useSweep is set, both axes are moved in a single diagonal pass; otherwise Y is moved before X.
+func (p *Player) Move() (collidesX, collidesY CollideMask) {
+	if !p.useSweep || p.noclip {
+		collidesY = p.MoveY()
+		collidesX = p.MoveX()
+		return collidesX, collidesY
+	}
+	clipX := ClipAny(p.clipsX, p.clipsGhost)
+	var whole Vec2
+	whole.X, p.subX = splitSubpixel(p.Vel.X + p.subX)
+	whole.Y, p.subY = splitSubpixel(p.Vel.Y + p.subY)
+	dx, dy, collidesX, collidesY := p.Actor.Sweep(p.Hitbox(), whole, clipX, p.clipsY)
+	p.Pos = p.Pos.Add(IVec2{X: dx, Y: dy})
+	if collidesX.Colliding(clipX) {
+		p.Vel.X, p.subX = 0, 0
+	}
+	if collidesY.Colliding(p.clipsY) {
+		p.Vel.Y, p.subY = 0, 0
+	}
+	return collidesX, collidesY
+}
+
+// SetTerminalVelocity overrides the player's max Y velocity when falling.
+func (p *Player) SetTerminalVelocity(v float64) {
+	p.effectiveTerminalVelocity = v
+}
+
+// ResetTerminalVelocity restores the player's max Y velocity when falling to PlayerTerminalVelocity.
+func (p *Player) ResetTerminalVelocity() {
+	p.effectiveTerminalVelocity = PlayerTerminalVelocity
+}
+
+// resetSubpixels discards the fractional movement carried over from prior frames, so that movement in one state does
+// not nudge the player an extra pixel after changing to another.
+func (p *Player) resetSubpixels() {
+	p.subX, p.subY = 0, 0
+}
+
+// foot returns the bottom-center pixel of the player's hitbox.
+func (p *Player) foot() IVec2 {
+	hb := p.Hitbox()
+	return IVec2{X: hb.X + hb.W/2, Y: hb.Y + hb.H - 1}
+}
+
+// onEscalator returns true if the player is standing inside an escalator.
+func (p *Player) onEscalator() bool {
+	_, ok := p.scene.escalatorAt(p.foot())
+	return ok
+}
+
 // cellUnderFoot provides the collideMask for the point directly under the player.
 func (p *Player) cellUnderFoot() (Vec2, CollideMask) {
 	hb := p.Hitbox()
@@ -184,13 +470,18 @@ func (p *Player) cellUnderFoot() (Vec2, CollideMask) {
 }
 
 func (p *Player) startIdling() PlayerState {
+	p.resetSubpixels()
 	p.sprite.SetAnim(PlayerAnimIdle, p.Vel.X < 0)
 	return PlayerStateIdle
 }
 
 func (p *Player) updateIdle(input PlayerInput) PlayerState {
-	p.Vel.X = orZero(Friction * p.Vel.X)
-	p.Vel.Y = orZero(Friction * p.Vel.Y)
+	if p.onEscalator() { // idle players are still carried by the escalator they stand on.
+		_ = p.MoveY()
+	}
+	friction := p.material().Friction
+	p.Vel.X = SnapToZero(friction*p.Vel.X, 0)
+	p.Vel.Y = SnapToZero(friction*p.Vel.Y, 0)
 
 	if !p.onSolidGround() {
 		return PlayerStateFalling
@@ -212,14 +503,24 @@ func (p *Player) updateIdle(input PlayerInput) PlayerState {
 	if input&InputJumped > 0 {
 		return p.startJumping(input)
 	}
-	return p.startIdling()
+	return PlayerStateIdle
+}
+
+// onIce returns true if the player was standing on ice when onSolidGround was last called.
+func (p *Player) onIce() bool {
+	return p.colliding&CollideIce > 0
+}
+
+// material returns the Material the player is currently in contact with.
+func (p *Player) material() Material {
+	return p.scene.materials.Lookup(p.colliding)
 }
 
 // onSolidGround returns true iff the player is on solid ground.
 func (p *Player) onSolidGround() bool {
 	collides := p.Actor.Collides(p.Hitbox().Add(IVec2{0, 1}))
 	p.colliding = collides
-	return collides&CollidedSolid > 0 || collides&CollidedOneWay == CollidedOneWay
+	return collides&CollidedSolid > 0 || collides&CollidedOneWay == CollidedOneWay || p.onEscalator()
 }
 
 func (p *Player) clipsX(mask CollideMask) bool {
@@ -229,14 +530,19 @@ func (p *Player) clipsX(mask CollideMask) bool {
 	return false
 }
 
+// clipsGhost clips through everything while the player is a ghost.
+func (p *Player) clipsGhost(_ CollideMask) bool {
+	return p.ghost
+}
+
 func (p *Player) clipsY(mask CollideMask) bool {
-	if p.state == PlayerStateFalling {
+	if p.state() == PlayerStateFalling {
 		return mask == p.fallClipmask
 	}
-	if p.state == PlayerStateLadderClimbing {
+	if p.state() == PlayerStateLadderClimbing {
 		return mask == CollideLadderTop
 	}
-	if p.Vel.Y < 0 || p.state == PlayerStateOneWayClimbing {
+	if p.Vel.Y < 0 || p.state() == PlayerStateOneWayClimbing {
 		return CollidedOneWay&mask > 0
 	}
 	return false
@@ -246,12 +552,12 @@ func (p *Player) clipsY(mask CollideMask) bool {
 func (p *Player) walkingOrRunning(input PlayerInput) PlayerState {
 	p.sprite.SetFacing(p.Vel.X < 0)
 	if input&InputRunning > 0 {
-		if p.state != PlayerStateRunning {
+		if p.state() != PlayerStateRunning {
 			p.sprite.SetAnim(PlayerAnimRun, p.Vel.X < 0)
 		}
 		return PlayerStateRunning
 	} else {
-		if p.state != PlayerStateWalking {
+		if p.state() != PlayerStateWalking {
 			p.sprite.SetAnim(PlayerAnimWalk, p.Vel.X < 0)
 		}
 		return PlayerStateWalking
@@ -270,7 +576,12 @@ func (p *Player) updateRunning(input PlayerInput) PlayerState {
 
 // updateRunOrWalk handles the update frame when running or walking.
 func (p *Player) updateRunOrWalk(input PlayerInput, maxSpeed float64, canLeap bool) PlayerState {
-	p.handleXVelUpdate(input, PlayerWalkAccel, maxSpeed, true)
+	accel := float64(PlayerWalkAccel)
+	if p.onIce() { // turning around on ice takes a while.
+		accel *= PlayerIceAccelScale
+	}
+	p.handleXVelUpdate(input, accel, maxSpeed, true)
+	p.kickUpIce()
 
 	_ = p.MoveY()
 	_ = p.MoveX() // TODO: play bump sound / animation?
@@ -291,23 +602,77 @@ func (p *Player) updateRunOrWalk(input PlayerInput, maxSpeed float64, canLeap bo
 		}
 	}
 	if input&InputWalked == 0 {
+		if p.onIce() && math.Abs(p.Vel.X) > PlayerSkidSpeed {
+			return PlayerStateSkidding
+		}
 		return p.startIdling()
 	}
 
 	return p.walkingOrRunning(input)
 }
 
+// updateSkidding slides the player to a stop on ice. The player can still jump or start walking again while skidding.
+func (p *Player) updateSkidding(input PlayerInput) PlayerState {
+	p.Vel.X = SnapToZero(p.material().Friction*p.Vel.X, 0)
+	_ = p.MoveY()
+	_ = p.MoveX()
+	p.kickUpIce()
+
+	if !p.onSolidGround() {
+		return p.startFalling(math.Abs(p.Vel.X))
+	}
+	if input&InputJumped > 0 {
+		return p.startJumping(input)
+	}
+	if input&InputWalked > 0 {
+		return p.walkingOrRunning(input)
+	}
+	if !p.onIce() || math.Abs(p.Vel.X) <= PlayerSkidSpeed {
+		return p.startIdling()
+	}
+	return PlayerStateSkidding
+}
+
+// updateFlying moves the player directly by the WASD keys while noclip is enabled. Gravity is not applied and the
+// player input bindings are ignored, so the player can fly anywhere in the level.
+func (p *Player) updateFlying() PlayerState {
+	var dir Vec2
+	if ebiten.IsKeyPressed(ebiten.KeyA) {
+		dir.X--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyD) {
+		dir.X++
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyW) {
+		dir.Y--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyS) {
+		dir.Y++
+	}
+	p.Vel = Vec2{X: dir.X * NoclipSpeed, Y: dir.Y * NoclipSpeed}
+	_, _ = p.Move()
+	return PlayerStateFlying
+}
+
+// kickUpIce throws up small chunks of ice from the player's feet while they are moving on ice.
+func (p *Player) kickUpIce() {
+	if !p.onIce() || math.Abs(p.Vel.X) <= PlayerSkidSpeed || p.scene.frame%iceChunkPeriod != 0 {
+		return
+	}
+	p.scene.particles.Burst(p.foot().Vec2(), 2, 0.75, 12, color.RGBA{R: 0xd0, G: 0xf0, B: 0xff, A: 0xff})
+}
+
 // handleXMotion handles updating the X velocity based on the current input, using the provided acceleration and max
 // speed.
 func (p *Player) handleXVelUpdate(input PlayerInput, accel, maxSpeed float64, useFriction bool) {
 	if input&InputWalked == InputWalked {
 		if useFriction { // dampen the player's movement if both bottoms are pressed
-			p.Vel.X = orZero(Friction * p.Vel.X)
+			p.Vel.X = SnapToZero(p.material().Friction*p.Vel.X, 0)
 		} else {
-			if p.Vel.X > 1e2 {
-				p.Vel.X = orZero(p.Vel.X - accel)
-			} else if p.Vel.X < -1e2 {
-				p.Vel.X = orZero(p.Vel.X + accel)
+			if p.Vel.X > SnapThreshold {
+				p.Vel.X = SnapToZero(max(p.Vel.X-accel, 0), 0)
+			} else if p.Vel.X < -SnapThreshold {
+				p.Vel.X = SnapToZero(min(p.Vel.X+accel, 0), 0)
 			}
 		}
 	}
@@ -322,12 +687,13 @@ func (p *Player) handleXVelUpdate(input PlayerInput, accel, maxSpeed float64, us
 // startFalling transitions to the fall state. When this transitions occurs the prior state must provide a maxFallXSpeed
 // based on the prior state.
 func (p *Player) startFalling(maxFallXSpeed float64) PlayerState {
+	p.resetSubpixels()
 	p.sprite.SetAnim(PlayerAnimJump, p.Vel.X < 0) // TODO: pick the last and middle frames of the animation
 	p.sprite.SetTag(jumpDownTag)
 	// test to see if we're colliding with a one-way platform, if so, increment y-velocity and don't change state.
 	collides := p.Collides(p.Hitbox())
 	if collides&CollidedOneWay > 0 && collides.Colliding(p.clipsY) { // if jumping up through a
-		fmt.Println("attempted to fall; not allowed")
+		slog.Debug("attempted to fall; not allowed")
 		p.Vel.Y -= PlayerOneWayLiftForce
 		p.Vel.X = 0
 		return PlayerStateOneWayClimbing
@@ -344,16 +710,33 @@ func (p *Player) updateFalling(input PlayerInput) (result PlayerState) {
 		}
 	}()
 	p.handleXVelUpdate(input, PlayerFallAccel, p.maxFallXSpeed, false)
-	p.Vel.Y = min(p.Vel.Y+Gravity/TPS, PlayerTerminalVelocity)
+	p.Vel.Y = min(p.Vel.Y+Gravity/TPS, p.effectiveTerminalVelocity)
 
-	collidesY := p.MoveY()
-	_ = p.MoveX()
+	landingSpeed := p.Vel.Y
+	_, collidesY := p.Move()
 
 	if p.fallClipmask != 0 && p.Pos.Y > p.fallResetY {
 		p.fallClipmask = 0
 	}
 
+	if landingSpeed >= 0 && p.onEscalator() { // escalators catch the player as though they were solid ground.
+		p.Vel.Y = 0
+		p.airJumpsLeft = p.MaxAirJumps
+		if input&InputWalked > 0 {
+			return p.walkingOrRunning(input)
+		}
+		return p.startIdling()
+	}
 	if collidesY.Colliding(p.clipsY) {
+		material := p.scene.materials.Lookup(collidesY)
+		if bounce := landingSpeed * material.Restitution; landingSpeed > 0 && bounce >= minBounceSpeed {
+			p.Vel.Y = -bounce
+			return PlayerStateFalling
+		}
+		p.airJumpsLeft = p.MaxAirJumps
+		if p == p.scene.player {
+			p.scene.game.bus.Publish(TopicPlayerLanded, p.Pos.Y-p.fallStartY)
+		}
 		if input&InputWalked > 0 {
 			return p.walkingOrRunning(input)
 		} else {
@@ -366,6 +749,9 @@ func (p *Player) updateFalling(input PlayerInput) (result PlayerState) {
 			return PlayerStateLadderClimbing
 		}
 	}
+	if p.jumpPressed(input) && p.airJumpsLeft > 0 {
+		return p.startAirJumping(p.maxFallXSpeed > PlayerMaxWalkSpeed)
+	}
 	return PlayerStateFalling
 }
 
@@ -376,6 +762,7 @@ func (p *Player) startJumping(input PlayerInput) PlayerState {
 
 // startJumpingOrLeaping starts jumping or leaping depending on whether the run bit is set in the input.
 func (p *Player) startJumpingOrLeaping(input PlayerInput) PlayerState {
+	p.resetSubpixels()
 	p.sprite.SetAnim(PlayerAnimJump, p.Vel.X < 0)
 
 	if input&InputClimbedDown > 0 { // if the player is jumping down off a one-way platform
@@ -388,11 +775,11 @@ func (p *Player) startJumpingOrLeaping(input PlayerInput) PlayerState {
 		}
 	}
 
-	if p.Vel.X < -1e-2 || 1e-2 < p.Vel.X {
+	if SnapToZero(p.Vel.X, 0) != 0 {
 		p.Vel.X = p.Vel.X * PlayerLeapCoeff
 	}
 	p.Vel.Y = -PlayerJumpForce
-	if p.state&CollideLadder > 0 {
+	if p.state()&CollideLadder > 0 {
 		p.Vel.Y = -PlayerLadderJumpForce
 	}
 	p.Pos.Y -= 1 // pick the player off the ground to prevent collisions with the ground from immediately ending the jump.
@@ -403,25 +790,43 @@ func (p *Player) startJumpingOrLeaping(input PlayerInput) PlayerState {
 	return PlayerStateJumping
 }
 
+// startAirJumping consumes an air jump to jump again while airborne. If leaping, the player keeps their running speed.
+func (p *Player) startAirJumping(leaping bool) PlayerState {
+	p.airJumpsLeft--
+	p.sprite.SetAnim(PlayerAnimJump, p.Vel.X < 0)
+	p.Vel.Y = -PlayerJumpForce
+	hb := p.Hitbox()
+	p.scene.particles.Burst(Vec2{X: float64(hb.X) + float64(hb.W)/2, Y: float64(hb.Y + hb.H)}, 12, 1.5, 20, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+	if leaping {
+		return PlayerStateLeaping
+	}
+	return PlayerStateJumping
+}
+
 // updateIdle performs an update and returns the next player state.
-func (p *Player) updateJumping(_ PlayerInput) PlayerState {
+func (p *Player) updateJumping(input PlayerInput) PlayerState {
+	if p.jumpPressed(input) && p.airJumpsLeft > 0 {
+		return p.startAirJumping(false)
+	}
 	return p.updateLeapingOrJumping(PlayerMaxWalkSpeed)
 }
 
 // updateLeaping performs an update and returns the next player state.
-func (p *Player) updateLeaping(_ PlayerInput) PlayerState {
+func (p *Player) updateLeaping(input PlayerInput) PlayerState {
+	if p.jumpPressed(input) && p.airJumpsLeft > 0 {
+		return p.startAirJumping(true)
+	}
 	return p.updateLeapingOrJumping(PlayerMaxRunSpeed)
 }
 
 func (p *Player) updateLeapingOrJumping(maxFallXSpeed float64) PlayerState {
-	p.Vel.Y = orZero(p.Vel.Y + Gravity/TPS)
+	p.Vel.Y = SnapToZero(p.Vel.Y+Gravity/TPS, 0)
 
 	if p.Vel.Y < 0.75 {
 		p.sprite.SetTag(jumpMaxTag)
 	}
 
-	collidesY := p.MoveY()
-	_ = p.MoveX()
+	_, collidesY := p.Move()
 
 	if collidesY.Colliding(p.clipsY) {
 		p.Vel.Y = 0
@@ -431,7 +836,294 @@ func (p *Player) updateLeapingOrJumping(maxFallXSpeed float64) PlayerState {
 	if p.Vel.Y > -0.25 {
 		return p.startFalling(math.Abs(p.Vel.X))
 	}
-	return p.state // don't change the current state; either leaping or jumping
+	return p.state() // don't change the current state; either leaping or jumping
+}
+
+// center returns the center of the player's hitbox in world coordinates.
+func (p *Player) center() Vec2 {
+	hb := p.Hitbox()
+	return Vec2{X: float64(hb.X) + float64(hb.W)/2, Y: float64(hb.Y) + float64(hb.H)/2}
+}
+
+// startGrappling casts the grapple toward the cursor, returning true if it caught a solid cell within GrappleMaxRange.
+func (p *Player) startGrappling() bool {
+	from := p.center()
+	cx, cy := ebiten.CursorPosition()
+	cursor := p.scene.camera.ScreenToWorld(IVec2{X: cx, Y: cy}.Vec2())
+	dir := cursor.Sub(from).Normalize()
+	if dir.Mag() == 0 {
+		return false
+	}
+	cell, hit := p.scene.Raycast(from, from.Add(dir.Scale(GrappleMaxRange)), ClipNone)
+	if !hit {
+		return false
+	}
+	size := p.scene.cellSize
+	anchor := IVec2{X: cell.X*size + size/2, Y: cell.Y*size + size/2}
+	length := anchor.Vec2().Sub(from).Mag()
+	if length > GrappleMaxRange {
+		return false
+	}
+	p.grappleAnchor, p.grappleLength = anchor, length
+	p.sprite.SetAnim(PlayerAnimJump, p.Vel.X < 0)
+	return true
+}
+
+// updateGrappling swings the player from the grapple anchor as a pendulum, releasing when the grapple button is let go.
+func (p *Player) updateGrappling(input PlayerInput) PlayerState {
+	if input&InputGrappled == 0 {
+		return p.startFalling(PlayerMaxRunSpeed)
+	}
+	if input&InputWalkedRight > 0 {
+		p.Vel.X += GrappleSwingAccel
+	}
+	if input&InputWalkedLeft > 0 {
+		p.Vel.X -= GrappleSwingAccel
+	}
+	p.Vel.Y = min(p.Vel.Y+Gravity/TPS, p.effectiveTerminalVelocity)
+	p.Vel = p.grappleConstrain(p.center(), p.Vel)
+	p.Move()
+	return PlayerStateGrappling
+}
+
+// grappleConstrain returns the velocity a player at pos should move with so that they remain attached to the grapple.
+// The radial component of vel pointing away from the anchor is removed, leaving only the tangential component, and a
+// centripetal correction pulls the player back onto the rope if they would otherwise end up past its length.
+func (p *Player) grappleConstrain(pos, vel Vec2) Vec2 {
+	anchor := p.grappleAnchor.Vec2()
+	next := pos.Add(vel)
+	radial := next.Sub(anchor)
+	dist := radial.Mag()
+	if dist <= p.grappleLength || dist == 0 {
+		return vel
+	}
+	n := radial.Scale(1 / dist)
+	if outward := vel.Dot(n); outward > 0 {
+		vel = vel.Sub(n.Scale(outward))
+	}
+	next = pos.Add(vel)
+	if d := next.Sub(anchor).Mag(); d > p.grappleLength {
+		target := anchor.Add(next.Sub(anchor).Scale(p.grappleLength / d))
+		vel = target.Sub(pos)
+	}
+	return vel
+}
+
+// Draw draws the player's sprite, along with any grapple or rope they are holding, offset by the provided camera
+// position.
+func (p *Player) Draw(screen *ebiten.Image, camera IVec2) {
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(p.Pos.X+camera.X), float64(p.Pos.Y+camera.Y))
+	p.sprite.DrawTo(screen, &opts)
+	p.DrawGrapple(screen, camera)
+	p.DrawRope(screen, camera)
+}
+
+// DrawGrapple draws the rope between the player and the grapple anchor as a series of dots while grappling.
+func (p *Player) DrawGrapple(screen *ebiten.Image, camera IVec2) {
+	if p.state() != PlayerStateGrappling {
+		return
+	}
+	from, to := p.center(), p.grappleAnchor.Vec2()
+	length := to.Sub(from).Mag()
+	offset := camera.Vec2()
+	for d := 0.0; d <= length; d += GrappleRopeSpacing {
+		pt := from.Lerp(to, d/length).Add(offset)
+		vector.DrawFilledRect(screen, float32(pt.X), float32(pt.Y), 1, 1, color.White, false)
+	}
+}
+
+// updateParry reflects any projectiles near the player while the parry window is open. A successful parry grants the
+// player InvincibilityFrames of invincibility; a parry which closes without reflecting anything whiffs.
+func (p *Player) updateParry() {
+	if p.parryFramesLeft <= 0 {
+		return
+	}
+	p.parryFramesLeft--
+	center := p.center()
+	if p.scene.projectiles.Reflect(center, ParryRadius, PlayerParryDamage) > 0 {
+		p.parryFramesLeft = 0
+		p.invincibleFrames = InvincibilityFrames
+		p.scene.particles.Burst(center, 16, 2, 12, color.RGBA{R: 0xff, G: 0xff, B: 0x80, A: 0xff})
+		return
+	}
+	if p.parryFramesLeft == 0 { // whiff
+		p.scene.particles.Burst(center, 6, 0.75, 10, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff})
+	}
+}
+
+// canAttack returns true if the player is able to start an attack from their current state.
+func (p *Player) canAttack() bool {
+	switch p.state() {
+	case PlayerStateHurt, PlayerStateDead, PlayerStateGrappling, PlayerStateAttacking:
+		return false
+	}
+	return true
+}
+
+// startAttacking starts a melee attack, which lasts as long as the attack animation.
+func (p *Player) startAttacking() PlayerState {
+	p.attackDone = false
+	p.attackHits = p.attackHits[:0]
+	p.sprite.SetAnim(PlayerAnimAttack, p.sprite.facingLeft)
+	return PlayerStateAttacking
+}
+
+// updateAttacking damages any enemies touched by the attack during the frames of the attack animation tagged
+// attackActiveTag. The player keeps falling while attacking, so attacks can be made from the air. Once the attack
+// ends, the player returns to the ground or falls.
+func (p *Player) updateAttacking(input PlayerInput) PlayerState {
+	p.handleXVelUpdate(input, PlayerFallAccel, PlayerMaxWalkSpeed, p.onSolidGround())
+	p.Vel.Y = min(p.Vel.Y+Gravity/TPS, p.effectiveTerminalVelocity)
+	_, collidesY := p.Move()
+	if collidesY.Colliding(p.clipsY) {
+		p.airJumpsLeft = p.MaxAirJumps
+	}
+
+	p.attackHitbox = IRect{}
+	if p.sprite.InTag(attackActiveTag) {
+		p.attackHitbox = p.attackBounds()
+		p.scene.breakCellsIn(p.attackHitbox)
+		for _, enemy := range p.scene.enemies {
+			if p.attackHit(enemy) || !p.attackHitbox.Overlaps(enemy.Hitbox()) {
+				continue
+			}
+			p.attackHits = append(p.attackHits, enemy)
+			enemy.TakeDamage(PlayerAttackDamage)
+		}
+	}
+
+	if !p.attackDone {
+		return PlayerStateAttacking
+	}
+	if !p.onSolidGround() {
+		return p.startFalling(PlayerMaxWalkSpeed)
+	}
+	if input&InputWalked > 0 {
+		return p.walkingOrRunning(input)
+	}
+	return p.startIdling()
+}
+
+// attackBounds returns the area hit by an attack, offset from the player's hitbox in the direction they are facing.
+func (p *Player) attackBounds() IRect {
+	hb := p.Hitbox()
+	result := IRect{X: hb.X + hb.W, Y: hb.Y, W: PlayerAttackReach, H: hb.H}
+	if p.sprite.facingLeft {
+		result.X = hb.X - PlayerAttackReach
+	}
+	return result
+}
+
+// attackHit returns true if the provided enemy has already been struck by the current attack.
+func (p *Player) attackHit(enemy Enemy) bool {
+	for _, hit := range p.attackHits {
+		if hit == enemy {
+			return true
+		}
+	}
+	return false
+}
+
+// canGrab returns true if the player is able to grab a zip line or rope from their current state.
+func (p *Player) canGrab() bool {
+	switch p.state() {
+	case PlayerStateHurt, PlayerStateDead, PlayerStateGrappling, PlayerStateZipping, PlayerStateSwinging:
+		return false
+	}
+	return true
+}
+
+// startGrabbing grabs any zip line or rope within reach of the player, returning nextState if there is none.
+func (p *Player) startGrabbing(nextState PlayerState) PlayerState {
+	if line, ok := p.scene.zipLineAt(p.Hitbox()); ok {
+		return p.startZipping(line)
+	}
+	if anchor, ok := p.scene.ropeAnchorNear(p.center()); ok {
+		return p.startSwinging(anchor)
+	}
+	return nextState
+}
+
+// startZipping grabs the provided zip line at its start.
+func (p *Player) startZipping(line ZipLine) PlayerState {
+	p.zipLine, p.zipDist = line, 0
+	p.Vel = Vec2{}
+	p.resetSubpixels()
+	p.hangFrom(line.Start)
+	p.sprite.SetAnim(PlayerAnimJump, p.sprite.facingLeft)
+	return PlayerStateZipping
+}
+
+// updateZipping slides the player along their zip line at ZipLineSpeed, ignoring collisions. The player falls once they
+// reach the end of the line, or lets go early if jump is pressed again.
+func (p *Player) updateZipping(input PlayerInput) PlayerState {
+	dir := p.zipLine.End.Sub(p.zipLine.Start).Normalize()
+	if p.jumpPressed(input) {
+		p.Vel = dir.Scale(ZipLineSpeed)
+		return p.startFalling(PlayerMaxRunSpeed)
+	}
+	p.zipDist = min(p.zipDist+ZipLineSpeed, p.zipLine.Length())
+	p.hangFrom(p.zipLine.PointAt(p.zipDist))
+	p.sprite.SetFacing(dir.X < 0)
+	if p.zipDist >= p.zipLine.Length() {
+		p.Vel = dir.Scale(ZipLineSpeed)
+		return p.startFalling(PlayerMaxRunSpeed)
+	}
+	return PlayerStateZipping
+}
+
+// hangFrom moves the player so that the top-center of their hitbox lies at the provided point.
+func (p *Player) hangFrom(pt Vec2) {
+	hb := p.Hitbox()
+	grip := IVec2{X: hb.X + hb.W/2, Y: hb.Y}
+	p.Pos = p.Pos.Add(IVec2{X: int(math.Round(pt.X)), Y: int(math.Round(pt.Y))}.Sub(grip))
+}
+
+// startSwinging grabs the rope hanging from the provided anchor, keeping the player's current distance and angle from
+// it. The player's velocity carries over into the swing.
+func (p *Player) startSwinging(anchor RopeAnchor) PlayerState {
+	offset := p.center().Sub(anchor.Pos)
+	p.ropeAnchor = anchor.Pos
+	p.ropeLength = max(offset.Mag(), 1)
+	p.ropeAngle = math.Atan2(offset.X, offset.Y)
+	tangent := Vec2{X: math.Cos(p.ropeAngle), Y: -math.Sin(p.ropeAngle)}
+	p.ropeAngularVel = p.Vel.Dot(tangent) / p.ropeLength
+	p.resetSubpixels()
+	p.sprite.SetAnim(PlayerAnimJump, p.sprite.facingLeft)
+	return PlayerStateSwinging
+}
+
+// updateSwinging swings the player from their rope as a pendulum. Pressing a walk key while the swing is at an extreme
+// pumps the swing in that direction. The player lets go when jump is pressed, keeping their tangential velocity.
+func (p *Player) updateSwinging(input PlayerInput) PlayerState {
+	if math.Abs(p.ropeAngularVel) < RopePumpWindow {
+		if input&InputWalkedRight > 0 {
+			p.ropeAngularVel += RopePumpAccel
+		}
+		if input&InputWalkedLeft > 0 {
+			p.ropeAngularVel -= RopePumpAccel
+		}
+	}
+	angle, angularVel := pendulumStep(p.ropeAngle, p.ropeAngularVel, p.ropeLength, Gravity/TPS)
+	angularVel = max(min(angularVel, RopeMaxAngularVel), -RopeMaxAngularVel)
+
+	next := pendulumPos(p.ropeAnchor, p.ropeLength, angle).Sub(p.center())
+	delta := IVec2{X: int(math.Round(next.X)), Y: int(math.Round(next.Y))}
+	if p.Collides(p.Hitbox().Add(delta)).Colliding(p.clipsY) { // the swing stops dead against walls.
+		p.ropeAngularVel = 0
+	} else {
+		p.ropeAngle, p.ropeAngularVel = angle, angularVel
+		p.Pos = p.Pos.Add(delta)
+	}
+	p.sprite.SetFacing(p.ropeAngularVel < 0)
+
+	if p.jumpPressed(input) {
+		speed := p.ropeLength * p.ropeAngularVel
+		p.Vel = Vec2{X: speed * math.Cos(p.ropeAngle), Y: -speed * math.Sin(p.ropeAngle)}
+		return p.startFalling(max(math.Abs(p.Vel.X), PlayerMaxWalkSpeed))
+	}
+	return PlayerStateSwinging
 }
 
 // startLadderClimbing performs a quick collision check to see if a ladder is underfoot, and starts climbing if so. The
@@ -441,27 +1133,28 @@ func (p *Player) startLadderClimbing(input PlayerInput) PlayerState {
 	// test point under foot
 	coords, cell := p.cellUnderFoot()
 	if cell&CollideLadder == 0 {
-		return p.state // don't change state unless we're under a ladder.
+		return p.state() // don't change state unless we're under a ladder.
 	}
 	if input&InputClimbedUp > 0 && cell&CollideLadderTop == CollideLadderTop { // don't climb up at tops
-		return p.state
+		return p.state()
 	}
 	if input&InputClimbedDown > 0 && cell&CollideLadderBot == CollideLadderBot { // don't climb down at bottoms
-		return p.state
+		return p.state()
 	}
 	p.Pos.X = int(coords.X) // center the player on the ladder (TODO: probably a bit too quickly..)
 	p.Vel.Y = 0             // player catches themselves and stops all movement.
 	p.Vel.X = 0
+	p.resetSubpixels()
 	return PlayerStateLadderClimbing
 }
 
 func (p *Player) updateLadderClimbing(input PlayerInput) PlayerState {
 	// ignore X movement until you jump off
 	if input&InputClimbed == InputClimbed {
-		if p.Vel.Y > 1e2 { // start dampening the player's movement.
-			p.Vel.Y = orZero(p.Vel.Y - PlayerClimbAccel)
-		} else if p.Vel.X < -1e2 {
-			p.Vel.Y = orZero(p.Vel.Y + PlayerClimbAccel)
+		if p.Vel.Y > SnapThreshold { // start dampening the player's movement.
+			p.Vel.Y = SnapToZero(max(p.Vel.Y-PlayerClimbAccel, 0), 0)
+		} else if p.Vel.Y < -SnapThreshold {
+			p.Vel.Y = SnapToZero(min(p.Vel.Y+PlayerClimbAccel, 0), 0)
 		}
 	} else if input&InputClimbedDown > 0 {
 		p.Vel.Y = min(p.Vel.Y+PlayerClimbAccel, PlayerMaxLadderSpeed)
@@ -510,6 +1203,43 @@ func (p *Player) updateOneWayClimbing(input PlayerInput) PlayerState {
 	return PlayerStateOneWayClimbing
 }
 
+// startDying transitions to the dead state, playing the frames of the death animation in which the player falls.
+func (p *Player) startDying() {
+	p.Vel = Vec2{}
+	p.deathAnimDone, p.deathHandled = false, false
+	p.sprite.SetAnim(PlayerAnimDead, p.sprite.facingLeft)
+	p.sprite.SetTag(deadFallTag)
+	p.states.Set(PlayerStateDead)
+}
+
+// updateDead waits for the player to finish falling in the death animation, then calls OnDeath exactly once. Gravity
+// and movement are suppressed while dead.
+func (p *Player) updateDead() PlayerState {
+	if !p.deathAnimDone || p.deathHandled {
+		return PlayerStateDead
+	}
+	p.deathHandled = true
+	if p.OnDeath != nil {
+		p.OnDeath()
+	}
+	return p.state() // OnDeath may have respawned the player.
+}
+
+// updateHurt performs an update while the player is stunned and returns the next player state.
+func (p *Player) updateHurt() PlayerState {
+	p.Vel.Y = min(p.Vel.Y+Gravity/TPS, p.effectiveTerminalVelocity)
+	p.Move()
+
+	p.hurtFrames--
+	if p.hurtFrames > 0 {
+		return PlayerStateHurt
+	}
+	if p.onSolidGround() {
+		return p.startIdling()
+	}
+	return p.startFalling(PlayerMaxWalkSpeed)
+}
+
 // Hitbox retrieves the bounds of the current image.
 func (p *Player) Hitbox() (result IRect) {
 	r := p.sprite.Hitbox().Add(image.Point{X: p.Pos.X, Y: p.Pos.Y})
@@ -518,24 +1248,46 @@ func (p *Player) Hitbox() (result IRect) {
 
 // handleInput handles all player input and returns PlayerInput flags which are used to handle state changes.
 func (p *Player) handleInput() PlayerInput {
-	var inputFlags PlayerInput
-
 	p.keys = inpututil.AppendPressedKeys(p.keys[:0]) // TODO: virtualize input from multiple sources.
-	for _, key := range p.keys {
-		switch key {
-		case ebiten.KeyA:
-			inputFlags = inputFlags | InputWalkedLeft
-		case ebiten.KeyD:
-			inputFlags = inputFlags | InputWalkedRight
-		case ebiten.KeyW:
-			inputFlags = inputFlags | InputClimbedUp
-		case ebiten.KeyS:
-			inputFlags = inputFlags | InputClimbedDown
-		case ebiten.KeySpace:
-			inputFlags = inputFlags | InputJumped
-		case ebiten.KeyShift:
-			inputFlags = inputFlags | InputRunning
-		}
+	return p.controls.Input(p.keys)
+}
+
+// InputConfig maps each key to the PlayerInput flags set while it is held.
+type InputConfig map[ebiten.Key]PlayerInput
+
+// Player1Controls are the controls used by the first player.
+var Player1Controls = InputConfig{
+	ebiten.KeyA:     InputWalkedLeft,
+	ebiten.KeyD:     InputWalkedRight,
+	ebiten.KeyW:     InputClimbedUp,
+	ebiten.KeyS:     InputClimbedDown,
+	ebiten.KeySpace: InputJumped,
+	ebiten.KeyShift: InputRunning,
+	ebiten.KeyE:     InputGrappled,
+	ebiten.KeyZ:     InputAttacked,
+	ebiten.KeyX:     InputParry,
+}
+
+// Player2Controls are the controls used by the second player in co-op.
+var Player2Controls = InputConfig{
+	ebiten.KeyJ: InputWalkedLeft,
+	ebiten.KeyL: InputWalkedRight,
+	ebiten.KeyI: InputClimbedUp,
+	ebiten.KeyK: InputClimbedDown,
+	ebiten.KeyO: InputRunning,
+	ebiten.KeyP: InputJumped,
+}
+
+// Input maps the provided pressed keys to PlayerInput flags.
+func (c InputConfig) Input(keys []ebiten.Key) PlayerInput {
+	var inputFlags PlayerInput
+	for _, key := range keys {
+		inputFlags |= c[key]
 	}
 	return inputFlags
 }
+
+// inputFromKeys maps the provided pressed keys to PlayerInput flags using Player1Controls.
+func inputFromKeys(keys []ebiten.Key) PlayerInput {
+	return Player1Controls.Input(keys)
+}
@@ -0,0 +1,454 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kalexmills/asebiten"
+	"github.com/niftysoft/2d-platformer/internal/testutil"
+	"math"
+	"testing"
+)
+
+func TestHandleXVelUpdateBothDirectionsDamp(t *testing.T) {
+	s := newTestScene(t, grid(10, 6)...)
+	p := newTestPlayer(t, s, IVec2{X: 32, Y: 32})
+	for _, vel := range []float64{0.5, -0.5} {
+		p.Vel.X = vel
+		p.handleXVelUpdate(InputWalked, PlayerClimbAccel, PlayerMaxWalkSpeed, false)
+		if math.Abs(p.Vel.X) >= math.Abs(vel) {
+			t.Errorf("holding left and right with velocity %v left velocity at %v; want it reduced toward zero", vel,
+				p.Vel.X)
+		}
+	}
+}
+
+func TestLadderClimbingBothButtonsStops(t *testing.T) {
+	s := newTestScene(t,
+		"..........",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"....H.....",
+		"##########",
+	)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 4*testCellSize + testCellSize/2, Y: 3*testCellSize + testCellSize/2})
+	p.states.Set(PlayerStateLadderClimbing)
+	p.Vel.Y = PlayerMaxLadderSpeed
+	for i := 0; i < 10; i++ {
+		updatePlayer(p, InputClimbed)
+	}
+	if p.state() != PlayerStateLadderClimbing {
+		t.Fatalf("player left the ladder; state is %v", p.state())
+	}
+	if p.Vel.Y != 0 {
+		t.Errorf("Vel.Y = %v after holding both climb buttons for 10 frames; want 0", p.Vel.Y)
+	}
+}
+
+func TestHurtBlocksInput(t *testing.T) {
+	s := newTestScene(t, grid(10, 6)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 5 * testCellSize})
+	p.readInput = func() PlayerInput { return InputWalkedRight }
+	p.TakeDamage(1, 1)
+	if p.state() != PlayerStateHurt {
+		t.Fatalf("state = %v after taking damage; want %v", p.state(), PlayerStateHurt)
+	}
+	if p.Vel.X >= 0 {
+		t.Errorf("Vel.X = %v after damage from the right; want knockback to the left", p.Vel.X)
+	}
+	for i := 1; i <= PlayerHurtStunFrames; i++ {
+		p.Update()
+		if p.input != 0 {
+			t.Fatalf("input was read %d frames after taking damage; want it blocked for %d", i, PlayerHurtStunFrames)
+		}
+	}
+	if p.state() == PlayerStateHurt {
+		t.Fatalf("player is still hurt after %d frames", PlayerHurtStunFrames)
+	}
+	p.Update()
+	if p.input != InputWalkedRight {
+		t.Errorf("input = %v on the frame after the stun ended; want %v", p.input, InputWalkedRight)
+	}
+}
+
+func TestDeathCallsOnDeathOnce(t *testing.T) {
+	const (
+		delta    = 10  // delta is the number of milliseconds which pass each frame.
+		fallTime = 550 // fallTime is the total duration of the frames tagged "fall" in dead.json.
+	)
+	prev := asebiten.DeltaMillis
+	asebiten.DeltaMillis = delta
+	t.Cleanup(func() { asebiten.DeltaMillis = prev })
+
+	s := newTestScene(t, grid(10, 6)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 5 * testCellSize})
+	calls := 0
+	p.OnDeath = func() { calls++ }
+	p.TakeDamage(p.HP, 1)
+	if p.state() != PlayerStateDead {
+		t.Fatalf("state = %v after losing all HP; want %v", p.state(), PlayerStateDead)
+	}
+	update := func(input PlayerInput) {
+		updatePlayer(p, input)
+		p.sprite.Update()
+	}
+	for elapsed := delta; elapsed < fallTime; elapsed += delta {
+		update(InputNone)
+		if calls > 0 {
+			t.Fatalf("OnDeath was called %dms after dying; want it called once the %dms fall ends", elapsed, fallTime)
+		}
+	}
+	for i := 0; i < 60; i++ {
+		update(InputJumped)
+	}
+	if calls != 1 {
+		t.Errorf("OnDeath was called %d times; want 1", calls)
+	}
+	if p.sprite.currTag != deadDownTag {
+		t.Errorf("sprite tag = %q after the fall ended; want %q", p.sprite.currTag, deadDownTag)
+	}
+	p.TakeDamage(1, 1)
+	if p.HP != 0 || p.state() != PlayerStateDead {
+		t.Errorf("damage while dead changed HP to %d and state to %v", p.HP, p.state())
+	}
+}
+
+func TestAirJump(t *testing.T) {
+	s := newTestScene(t, grid(10, 20)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 4 * testCellSize})
+	p.states.Set(p.startFalling(PlayerMaxWalkSpeed))
+	updatePlayer(p, 0)
+
+	updatePlayer(p, InputJumped)
+	if p.state() != PlayerStateJumping || p.airJumpsLeft != 0 {
+		t.Fatalf("first air jump: state = %v, airJumpsLeft = %d; want %v, 0", p.state(), p.airJumpsLeft,
+			PlayerStateJumping)
+	}
+	for i := 0; i < 5; i++ {
+		updatePlayer(p, 0)
+	}
+	before := p.Vel.Y
+	updatePlayer(p, InputJumped)
+	if p.Vel.Y <= before-PlayerJumpForce/2 {
+		t.Errorf("second air jump fired; Vel.Y went from %v to %v", before, p.Vel.Y)
+	}
+
+	for i := 0; i < 300 && p.state() != PlayerStateIdle; i++ {
+		updatePlayer(p, 0)
+	}
+	if p.state() != PlayerStateIdle {
+		t.Fatalf("player did not land; state is %v", p.state())
+	}
+	if p.airJumpsLeft != p.MaxAirJumps {
+		t.Errorf("airJumpsLeft = %d after landing; want %d", p.airJumpsLeft, p.MaxAirJumps)
+	}
+}
+
+func TestGrappleKeepsRopeLength(t *testing.T) {
+	s := newTestScene(t, grid(30, 20)...)
+	p := newTestPlayer(t, s, IVec2{})
+	anchor := IVec2{X: 240, Y: 40}
+	hb := p.Hitbox()
+	setFeet(p, IVec2{X: anchor.X + GrappleMaxRange, Y: anchor.Y + hb.H/2})
+	p.grappleAnchor, p.grappleLength = anchor, p.center().Sub(anchor.Vec2()).Mag()
+	if math.Abs(p.grappleLength-GrappleMaxRange) > 1 {
+		t.Fatalf("player starts %v pixels from the anchor; want %v", p.grappleLength, GrappleMaxRange)
+	}
+	p.states.Set(PlayerStateGrappling)
+	for i := 0; i < 180; i++ {
+		input := InputGrappled
+		if i < 30 {
+			input |= InputWalkedRight // swing outward to test the constraint.
+		}
+		updatePlayer(p, input)
+		if dist := p.center().Sub(anchor.Vec2()).Mag(); dist > GrappleMaxRange+1 {
+			t.Fatalf("player is %v pixels from the anchor after %d frames; want at most %v", dist, i+1,
+				GrappleMaxRange+1)
+		}
+	}
+	if p.state() != PlayerStateGrappling {
+		t.Errorf("state = %v; want %v", p.state(), PlayerStateGrappling)
+	}
+}
+
+func TestPlayerSubpixelMovement(t *testing.T) {
+	tests := []struct {
+		vel    float64
+		frames int
+		want   int
+	}{
+		{0.1, 100, 10},
+		{0.3, 10, 3},
+		{-0.1, 100, -10},
+		{0.25, 7, 1},
+	}
+	for _, tt := range tests {
+		s := newTestScene(t, grid(20, 10)...)
+		p := newTestPlayer(t, s, IVec2{X: 128, Y: 48})
+		start := p.Pos
+		for i := 0; i < tt.frames; i++ {
+			p.Vel = Vec2{X: tt.vel, Y: tt.vel}
+			p.MoveX()
+			p.MoveY()
+		}
+		if got := p.Pos.Sub(start); got != (IVec2{X: tt.want, Y: tt.want}) {
+			t.Errorf("moved %v after %d frames at %v pixels per frame; want %d along each axis", got, tt.frames,
+				tt.vel, tt.want)
+		}
+	}
+}
+
+func TestPlayerMoveSweepMatchesTwoPass(t *testing.T) {
+	for _, vel := range []Vec2{{X: 3, Y: 5}, {X: -4, Y: 2}, {X: 2, Y: 40}} {
+		var got [2]IVec2
+		for i, sweep := range []bool{false, true} {
+			s := newTestScene(t, grid(20, 10)...)
+			p := newTestPlayer(t, s, IVec2{X: 128, Y: 16})
+			p.useSweep = sweep
+			p.Vel = vel
+			p.Move()
+			got[i] = p.Pos
+		}
+		if got[0] != got[1] {
+			t.Errorf("Move() with velocity %v ends at %v with sweep; want %v, as with two passes", vel, got[1], got[0])
+		}
+	}
+}
+
+// benchmarkMove measures moving the player diagonally through open air, with or without sweeping.
+func benchmarkMove(b *testing.B, sweep bool) {
+	s := newTestScene(b, grid(20, 10)...)
+	p := newTestPlayer(b, s, IVec2{X: 64, Y: 16})
+	p.useSweep = sweep
+	start := p.Pos
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Pos, p.Vel = start, Vec2{X: 6, Y: 8}
+		p.Move()
+	}
+}
+
+func BenchmarkMoveTwoPass(b *testing.B) { benchmarkMove(b, false) }
+func BenchmarkMoveSweep(b *testing.B)   { benchmarkMove(b, true) }
+
+func TestAttackHitboxActiveWindow(t *testing.T) {
+	const (
+		delta      = 10  // delta is the number of milliseconds which pass each frame.
+		attackTime = 450 // attackTime is the total duration of attack.json.
+		activeTime = 150 // activeTime is the total duration of the frames tagged "active" in attack.json.
+	)
+	prev := asebiten.DeltaMillis
+	asebiten.DeltaMillis = delta
+	t.Cleanup(func() { asebiten.DeltaMillis = prev })
+
+	s := newTestScene(t, grid(20, 6)...)
+	loadTestEntities(t, s, &Entity{ID: EtyPatrolEnemy, PxCoords: IVec2{X: 0, Y: 4 * testCellSize},
+		Dim: IDim{W: 16, H: 16}})
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 5 * testCellSize})
+	enemy := s.enemies[0].(*PatrolEnemy)
+	hb := p.Hitbox()
+	enemy.Pos = IVec2{X: hb.X + hb.W + PlayerAttackReach/2, Y: hb.Y + hb.H - enemy.Dim.H}
+	hp := enemy.HP
+
+	p.states.Set(p.startAttacking())
+	frame, activeFrames, wasActive := 0, 0, false
+	for ; p.state() == PlayerStateAttacking; frame++ {
+		if frame > attackTime/delta+1 {
+			t.Fatalf("player is still attacking after %d frames; want the attack to last %dms", frame, attackTime)
+		}
+		active := p.sprite.InTag(attackActiveTag)
+		updatePlayer(p, InputNone)
+		p.sprite.Update()
+		if got := p.attackHitbox != (IRect{}); got != active {
+			t.Errorf("attack hitbox active = %v on frame %d; want %v", got, frame, active)
+		}
+		if active {
+			activeFrames++
+			wasActive = true
+		}
+		wantHP := hp
+		if wasActive {
+			wantHP = hp - PlayerAttackDamage
+		}
+		if enemy.HP != wantHP {
+			t.Errorf("enemy HP = %d on frame %d; want %d", enemy.HP, frame, wantHP)
+		}
+	}
+	if frame < attackTime/delta {
+		t.Errorf("attack ended after %d frames; want it to last %dms", frame, attackTime)
+	}
+	if activeFrames != activeTime/delta {
+		t.Errorf("attack was active for %d frames; want %d", activeFrames, activeTime/delta)
+	}
+}
+
+func TestParryWindow(t *testing.T) {
+	for _, tt := range []struct {
+		frame   int
+		reflect bool
+	}{
+		{0, true},
+		{ParryWindow, true},
+		{ParryWindow + 1, false},
+	} {
+		s := newTestScene(t, grid(10, 6)...)
+		p := newTestPlayer(t, s, IVec2{})
+		setFeet(p, IVec2{X: 5 * testCellSize, Y: 5 * testCellSize})
+		pressed := false
+		p.readInput = func() PlayerInput {
+			if pressed {
+				return 0
+			}
+			pressed = true
+			return InputParry
+		}
+		for frame := 0; frame < tt.frame; frame++ {
+			p.Update()
+		}
+		s.projectiles.Spawn(p.center().Sub(Vec2{X: ProjectileSize / 2, Y: ProjectileSize / 2}), Vec2{X: -2}, 60, 1)
+		p.Update()
+		proj := s.projectiles.pool[0]
+		if proj.Reflected != tt.reflect {
+			t.Errorf("projectile arriving %d frames after the press reflected = %v; want %v", tt.frame, proj.Reflected,
+				tt.reflect)
+		}
+		if tt.reflect && (proj.Vel.X != 2 || proj.Damage != PlayerParryDamage || p.invincibleFrames == 0) {
+			t.Errorf("parried projectile has velocity %v and damage %d, player has %d invincible frames",
+				proj.Vel, proj.Damage, p.invincibleFrames)
+		}
+	}
+}
+
+func TestParryWhiff(t *testing.T) {
+	s := newTestScene(t, grid(10, 6)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 5 * testCellSize, Y: 5 * testCellSize})
+	p.readInput = func() PlayerInput { return InputParry }
+	for frame := 0; frame < ParryWindow; frame++ {
+		p.Update()
+	}
+	before := len(s.particles.particles)
+	p.Update()
+	if len(s.particles.particles) <= before {
+		t.Errorf("no whiff particles were burst when the parry window closed without reflecting anything")
+	}
+	if p.invincibleFrames != 0 {
+		t.Errorf("player has %d invincible frames after a whiff; want 0", p.invincibleFrames)
+	}
+}
+
+func TestStateHistoryWraps(t *testing.T) {
+	s := newTestScene(t, grid(8, 4)...)
+	p := newTestPlayer(t, s, IVec2{})
+	cycle := []PlayerState{PlayerStateWalking, PlayerStateRunning, PlayerStateJumping, PlayerStateFalling,
+		PlayerStateIdle}
+	capacity := len(p.stateHistory)
+	var want []PlayerState
+	for i := 0; i < capacity+7; i++ {
+		state := cycle[i%len(cycle)]
+		if p.state() == state {
+			t.Fatalf("player is already %v; the cycle must change state every step", state)
+		}
+		p.states.Set(state)
+		want = append(want, state)
+
+		got := p.StateHistory()
+		wantTail := want[max(len(want)-capacity, 0):]
+		if len(got) < len(wantTail) || len(got) > capacity {
+			t.Fatalf("len(StateHistory()) = %d after %d changes; want between %d and %d", len(got), i+1,
+				len(wantTail), capacity)
+		}
+		got = got[len(got)-len(wantTail):]
+		for j := range wantTail {
+			if got[j] != wantTail[j] {
+				t.Fatalf("StateHistory()[%d] = %v after %d changes; want %v", j, got[j], i+1, wantTail[j])
+			}
+		}
+	}
+	if got := len(p.StateHistory()); got != capacity {
+		t.Errorf("len(StateHistory()) = %d after more than %d changes; want %d", got, capacity, capacity)
+	}
+}
+
+func TestPlayerDrawIdleGolden(t *testing.T) {
+	s := newTestScene(t, grid(10, 6)...)
+	p := newTestPlayer(t, s, IVec2{X: 32, Y: 32})
+	if got := p.state(); got != PlayerStateIdle {
+		t.Fatalf("state() = %v; want %v", got, PlayerStateIdle)
+	}
+	bounds := p.sprite.Bounds()
+	screen := ebiten.NewImage(bounds.Dx(), bounds.Dy())
+	p.Draw(screen, p.Pos.Scale(-1))
+	testutil.AssertGoldenScreen(t, screen, "player_idle")
+}
+
+func TestNoclipMovesIntoSolidCell(t *testing.T) {
+	for _, noclip := range []bool{false, true} {
+		s := newTestScene(t,
+			"..........",
+			"..........",
+			"......S...",
+			"##########",
+		)
+		p := newTestPlayer(t, s, IVec2{})
+		setFeet(p, IVec2{X: 4 * testCellSize, Y: 3 * testCellSize})
+		p.noclip = noclip
+		p.Vel = Vec2{X: NoclipSpeed}
+		for i := 0; i < 2*testCellSize/NoclipSpeed; i++ {
+			p.MoveX()
+		}
+		inWall := s.Collides(p.Hitbox(), ClipNone)&CollideStone != 0
+		if inWall != noclip {
+			t.Errorf("noclip %v: player hitbox %v inside the wall = %v; want %v", noclip, p.Hitbox(), inWall, noclip)
+		}
+	}
+}
+
+func TestStartIdlingResetsSubpixels(t *testing.T) {
+	s := newTestScene(t, grid(10, 4)...)
+	p := newTestPlayer(t, s, IVec2{})
+	setFeet(p, IVec2{X: 64, Y: 3 * testCellSize})
+	p.subX, p.subY = 0.9, 0.9
+	p.states.Set(p.startIdling())
+	if p.subX != 0 || p.subY != 0 {
+		t.Fatalf("subpixels after idling = (%v, %v); want (0, 0)", p.subX, p.subY)
+	}
+	x := p.Pos.X
+	p.Vel.X = 0.5 // half a pixel, which would cross a whole pixel had the 0.9 carried over.
+	p.MoveX()
+	if got := p.Pos.X - x; got != 0 {
+		t.Errorf("a half-pixel step after idling moved %d pixels; want 0", got)
+	}
+	p.MoveX()
+	if got := p.Pos.X - x; got != 1 {
+		t.Errorf("two half-pixel steps after idling moved %d pixels; want 1", got)
+	}
+}
+
+func TestStateTransitionsResetSubpixels(t *testing.T) {
+	s := newTestScene(t, grid(10, 4)...)
+	p := newTestPlayer(t, s, IVec2{})
+	tests := []struct {
+		name  string
+		start func() PlayerState
+	}{
+		{name: "falling", start: func() PlayerState { return p.startFalling(PlayerMaxWalkSpeed) }},
+		{name: "jumping", start: func() PlayerState { return p.startJumping(InputJumped) }},
+	}
+	for _, tt := range tests {
+		setFeet(p, IVec2{X: 64, Y: 3 * testCellSize})
+		p.subX, p.subY = 0.9, -0.9
+		p.states.Set(tt.start())
+		if p.subX != 0 || p.subY != 0 {
+			t.Errorf("subpixels after %s = (%v, %v); want (0, 0)", tt.name, p.subX, p.subY)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package procgen
+
+import (
+	"errors"
+	"fmt"
+	"github.com/niftysoft/2d-platformer/internal"
+	"math/rand"
+)
+
+// maxConnectAttempts is the number of random orderings ConnectRooms tries before giving up.
+const maxConnectAttempts = 32
+
+// Exit is a way out of a Room. Each exit leads to an exit of the opposite direction in a neighbouring room, which serves
+// as its entrance.
+type Exit struct {
+	Dir     internal.IVec2 // Dir is the cardinal direction the player travels when leaving through this exit.
+	SpawnID string         // SpawnID identifies where the player appears when entering a room through this exit.
+}
+
+// Room is a hand-authored level which can be stitched together with other rooms.
+type Room struct {
+	Level *internal.Level
+	Exits []Exit
+}
+
+// Link identifies an exit in a specific room.
+type Link struct {
+	Room *Room
+	Exit int // Exit is the index of the exit in Room.Exits.
+}
+
+// RoomGraph connects every exit of every room to an entrance of another room.
+type RoomGraph struct {
+	Rooms []*Room
+	links map[Link]Link
+}
+
+// Next returns the room and entrance the player arrives at after leaving room through the exit with index exit.
+func (g *RoomGraph) Next(room *Room, exit int) (*Room, Exit, bool) {
+	to, ok := g.links[Link{Room: room, Exit: exit}]
+	if !ok {
+		return nil, Exit{}, false
+	}
+	return to.Room, to.Room.Exits[to.Exit], true
+}
+
+// Connected returns true if every room in the graph can be reached from the first room.
+func (g *RoomGraph) Connected() bool {
+	if len(g.Rooms) == 0 {
+		return true
+	}
+	seen := map[*Room]bool{g.Rooms[0]: true}
+	queue := []*Room{g.Rooms[0]}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for i := range room.Exits {
+			if next, _, ok := g.Next(room, i); ok && !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(seen) == len(g.Rooms)
+}
+
+// ConnectRooms builds a RoomGraph which connects the provided rooms via their exits. A spanning tree is built first to
+// ensure every room is reachable, after which any remaining exits are paired up. Each exit is paired with exactly one
+// exit travelling in the opposite direction, and no two rooms are connected more than once. An error is returned if
+// no such pairing can be found.
+func ConnectRooms(rooms []*Room, rng *rand.Rand) (*RoomGraph, error) {
+	for _, room := range rooms {
+		if len(room.Exits) > len(rooms)-1 {
+			return nil, fmt.Errorf("room %s has %d exits but only %d neighbours are available",
+				roomName(room), len(room.Exits), len(rooms)-1)
+		}
+	}
+	for attempt := 0; attempt < maxConnectAttempts; attempt++ {
+		if graph, ok := tryConnectRooms(rooms, rng); ok {
+			return graph, nil
+		}
+	}
+	return nil, errors.New("could not connect every exit to an entrance")
+}
+
+// tryConnectRooms attempts a single random pairing of exits, returning false if it fails.
+func tryConnectRooms(rooms []*Room, rng *rand.Rand) (*RoomGraph, bool) {
+	graph := &RoomGraph{Rooms: rooms, links: make(map[Link]Link)}
+	if len(rooms) == 0 {
+		return graph, true
+	}
+	paired := make(map[[2]*Room]bool)
+	connect := func(a, b Link) {
+		graph.links[a], graph.links[b] = b, a
+		paired[[2]*Room{a.Room, b.Room}], paired[[2]*Room{b.Room, a.Room}] = true, true
+	}
+	// candidates finds all unlinked exits in rooms accepted by the filter which could serve as an entrance for from.
+	candidates := func(from Link, accept func(*Room) bool) []Link {
+		var result []Link
+		dir := from.Room.Exits[from.Exit].Dir
+		for _, room := range rooms {
+			if room == from.Room || paired[[2]*Room{from.Room, room}] || !accept(room) {
+				continue
+			}
+			for i, exit := range room.Exits {
+				_, linked := graph.links[Link{Room: room, Exit: i}]
+				if !linked && exit.Dir.X == -dir.X && exit.Dir.Y == -dir.Y {
+					result = append(result, Link{Room: room, Exit: i})
+				}
+			}
+		}
+		return result
+	}
+
+	// build a random spanning tree
+	inTree := map[*Room]bool{rooms[rng.Intn(len(rooms))]: true}
+	for len(inTree) < len(rooms) {
+		var options [][2]Link
+		for _, room := range rooms {
+			if !inTree[room] {
+				continue
+			}
+			for i := range room.Exits {
+				from := Link{Room: room, Exit: i}
+				if _, linked := graph.links[from]; linked {
+					continue
+				}
+				for _, to := range candidates(from, func(r *Room) bool { return !inTree[r] }) {
+					options = append(options, [2]Link{from, to})
+				}
+			}
+		}
+		if len(options) == 0 {
+			return nil, false
+		}
+		choice := options[rng.Intn(len(options))]
+		connect(choice[0], choice[1])
+		inTree[choice[1].Room] = true
+	}
+
+	// pair up any remaining exits
+	var unlinked []Link
+	for _, room := range rooms {
+		for i := range room.Exits {
+			if _, linked := graph.links[Link{Room: room, Exit: i}]; !linked {
+				unlinked = append(unlinked, Link{Room: room, Exit: i})
+			}
+		}
+	}
+	rng.Shuffle(len(unlinked), func(i, j int) { unlinked[i], unlinked[j] = unlinked[j], unlinked[i] })
+	for _, from := range unlinked {
+		if _, linked := graph.links[from]; linked {
+			continue
+		}
+		options := candidates(from, func(*Room) bool { return true })
+		if len(options) == 0 {
+			return nil, false
+		}
+		connect(from, options[rng.Intn(len(options))])
+	}
+	return graph, true
+}
+
+func roomName(room *Room) string {
+	if room.Level == nil {
+		return "<nil>"
+	}
+	return room.Level.ID
+}
@@ -0,0 +1,69 @@
+package procgen
+
+import (
+	"github.com/niftysoft/2d-platformer/internal"
+	"math/rand"
+	"testing"
+)
+
+var (
+	left  = internal.IVec2{X: -1}
+	right = internal.IVec2{X: 1}
+	up    = internal.IVec2{Y: -1}
+	down  = internal.IVec2{Y: 1}
+)
+
+// testRooms returns a small set of rooms whose exits can all be paired.
+func testRooms() []*Room {
+	room := func(id string, dirs ...internal.IVec2) *Room {
+		result := &Room{Level: &internal.Level{ID: id}}
+		for _, dir := range dirs {
+			result.Exits = append(result.Exits, Exit{Dir: dir, SpawnID: id})
+		}
+		return result
+	}
+	return []*Room{
+		room("A", right, down),
+		room("B", left, right),
+		room("C", left, up),
+		room("D", up),
+		room("E", down),
+	}
+}
+
+func TestConnectRoomsConnected(t *testing.T) {
+	rooms := testRooms()
+	graph, err := ConnectRooms(rooms, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatalf("ConnectRooms() returned error: %v", err)
+	}
+	if !graph.Connected() {
+		t.Fatal("graph is not connected")
+	}
+	for _, room := range rooms {
+		for i, exit := range room.Exits {
+			next, entrance, ok := graph.Next(room, i)
+			if !ok {
+				t.Errorf("exit %d of room %s is not linked", i, room.Level.ID)
+				continue
+			}
+			if next == room {
+				t.Errorf("exit %d of room %s leads back to itself", i, room.Level.ID)
+			}
+			if entrance.Dir != (internal.IVec2{X: -exit.Dir.X, Y: -exit.Dir.Y}) {
+				t.Errorf("exit %d of room %s travelling %v enters through an exit travelling %v", i, room.Level.ID,
+					exit.Dir, entrance.Dir)
+			}
+		}
+	}
+}
+
+func TestConnectRoomsTooManyExits(t *testing.T) {
+	rooms := []*Room{
+		{Level: &internal.Level{ID: "A"}, Exits: []Exit{{Dir: right}, {Dir: left}}},
+		{Level: &internal.Level{ID: "B"}, Exits: []Exit{{Dir: left}}},
+	}
+	if _, err := ConnectRooms(rooms, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("ConnectRooms() returned no error for a room with more exits than neighbours")
+	}
+}
@@ -0,0 +1,93 @@
+package procgen
+
+import (
+	"github.com/niftysoft/2d-platformer/internal"
+	"math"
+	"math/rand"
+)
+
+// Thresholds used to convert noise values in [0, 1] into terrain.
+const (
+	stoneThreshold = 0.6 // noise values above stoneThreshold become stone.
+	dirtThreshold  = 0.4 // noise values above dirtThreshold become dirt.
+)
+
+// GenerateTerrain generates an IntGrid of the provided width and height, in cells, by thresholding 2D Perlin noise
+// sampled at (x*scale, y*scale). The same seed always generates the same terrain. The result is laid out as
+// idx = x + y*width.
+func GenerateTerrain(width, height int, scale float64, seed int64) []internal.IntGridData {
+	noise := newPerlin(seed)
+	result := make([]internal.IntGridData, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := (noise.At(float64(x)*scale, float64(y)*scale) + 1) / 2 // map from [-1, 1] to [0, 1]
+			switch {
+			case v > stoneThreshold:
+				result[x+y*width] = internal.IntGridStone
+			case v >= dirtThreshold:
+				result[x+y*width] = internal.IntGridDirt
+			default:
+				result[x+y*width] = internal.IntGridNothing
+			}
+		}
+	}
+	return result
+}
+
+// perlin is Ken Perlin's improved noise, using a permutation table shuffled from a seed.
+type perlin struct {
+	perm [512]int
+}
+
+func newPerlin(seed int64) *perlin {
+	result := &perlin{}
+	p := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range result.perm {
+		result.perm[i] = p[i%256]
+	}
+	return result
+}
+
+// At samples the noise field at (x, y), returning a value in approximately [-1, 1].
+func (p *perlin) At(x, y float64) float64 {
+	xf, yf := math.Floor(x), math.Floor(y)
+	xi, yi := int(xf)&255, int(yf)&255
+	x, y = x-xf, y-yf
+	u, v := fade(x), fade(y)
+
+	aa := p.perm[p.perm[xi]+yi]
+	ab := p.perm[p.perm[xi]+yi+1]
+	ba := p.perm[p.perm[xi+1]+yi]
+	bb := p.perm[p.perm[xi+1]+yi+1]
+
+	return lerp(v,
+		lerp(u, grad(aa, x, y), grad(ba, x-1, y)),
+		lerp(u, grad(ab, x, y-1), grad(bb, x-1, y-1)),
+	)
+}
+
+func fade(t float64) float64 { return t * t * t * (t*(t*6-15) + 10) }
+
+func lerp(t, a, b float64) float64 { return a + t*(b-a) }
+
+// grad returns the dot product of (x, y) with one of eight gradient directions selected by hash.
+func grad(hash int, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
@@ -0,0 +1,33 @@
+package procgen
+
+import (
+	"github.com/niftysoft/2d-platformer/internal"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateTerrainReproducible(t *testing.T) {
+	a := GenerateTerrain(40, 20, 0.1, 7)
+	b := GenerateTerrain(40, 20, 0.1, 7)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("two calls with the same seed generated different terrain")
+	}
+	if c := GenerateTerrain(40, 20, 0.1, 8); reflect.DeepEqual(a, c) {
+		t.Error("two calls with different seeds generated identical terrain")
+	}
+	counts := make(map[internal.IntGridData]int)
+	for _, dat := range a {
+		counts[dat]++
+	}
+	for _, dat := range []internal.IntGridData{internal.IntGridNothing, internal.IntGridDirt, internal.IntGridStone} {
+		if counts[dat] == 0 {
+			t.Errorf("generated terrain contains no %s cells", dat.Describe())
+		}
+	}
+}
+
+func BenchmarkGenerateTerrain(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GenerateTerrain(200, 100, 0.1, int64(i))
+	}
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+)
+
+const ProjectileSize = 2      // ProjectileSize is the width and height of each projectile's hitbox in pixels.
+const ProjectilePoolSize = 64 // ProjectilePoolSize is the initial capacity of the projectile pool.
+
+// Projectile is a small moving hitbox which damages the player on contact and is destroyed by solid cells.
+type Projectile struct {
+	Actor
+	Pos    Vec2 // Pos is the position of the top-left corner of the projectile in world coordinates.
+	Vel    Vec2 // Vel is the projectile's velocity in pixels per frame.
+	Life   int  // Life is the number of frames remaining before the projectile expires.
+	Damage int  // Damage is the amount of HP the projectile removes from the player on contact.
+
+	Reflected bool // Reflected is set once the player has parried this projectile; it then damages enemies instead.
+}
+
+// Hitbox returns the hitbox of this projectile in world coordinates.
+func (p *Projectile) Hitbox() IRect {
+	return IRect{X: int(math.Round(p.Pos.X)), Y: int(math.Round(p.Pos.Y)), W: ProjectileSize, H: ProjectileSize}
+}
+
+// Center returns the center of this projectile in world coordinates.
+func (p *Projectile) Center() Vec2 {
+	return p.Pos.Add(Vec2{X: ProjectileSize / 2, Y: ProjectileSize / 2})
+}
+
+// update moves this projectile, returning false once the projectile should be deactivated.
+func (p *Projectile) update() bool {
+	p.Life--
+	if p.Life <= 0 {
+		return false
+	}
+	if _, collides := p.MoveX(p.Hitbox(), p.Vel.X, ClipNone); collides.Colliding(ClipNone) {
+		return false
+	}
+	p.Pos.X += p.Vel.X
+	if _, collides := p.MoveY(p.Hitbox(), p.Vel.Y, ClipNone); collides.Colliding(ClipNone) {
+		return false
+	}
+	p.Pos.Y += p.Vel.Y
+	return true
+}
+
+// ProjectileSystem manages a pool of projectiles. Only the first active projectiles in the pool are live.
+type ProjectileSystem struct {
+	scene  *PlatformerScene
+	pool   []Projectile
+	active int
+}
+
+// NewProjectileSystem constructs an empty ProjectileSystem for the provided scene.
+func NewProjectileSystem(scene *PlatformerScene) *ProjectileSystem {
+	return &ProjectileSystem{scene: scene, pool: make([]Projectile, 0, ProjectilePoolSize)}
+}
+
+// Spawn fires a new projectile from pos with the provided velocity, lifetime in frames, and damage.
+func (ps *ProjectileSystem) Spawn(pos Vec2, vel Vec2, life, damage int) {
+	p := Projectile{Actor: Actor{scene: ps.scene}, Pos: pos, Vel: vel, Life: life, Damage: damage}
+	if ps.active < len(ps.pool) {
+		ps.pool[ps.active] = p
+	} else {
+		ps.pool = append(ps.pool, p)
+	}
+	ps.active++
+}
+
+// Update moves each active projectile, damaging the player on contact and deactivating projectiles which have expired or
+// hit a solid cell.
+func (ps *ProjectileSystem) Update() {
+	for i := 0; i < ps.active; {
+		p := &ps.pool[i]
+		alive := p.update()
+		if alive && p.Reflected {
+			alive = !ps.hitEnemy(p)
+		} else if alive {
+			alive = !ps.hitPlayer(p)
+		}
+		if alive {
+			i++
+			continue
+		}
+		ps.active--
+		ps.pool[i] = ps.pool[ps.active] // swap the last active projectile into this slot.
+	}
+}
+
+// hitPlayer damages the first player touching the provided projectile, returning true if a player was hit.
+func (ps *ProjectileSystem) hitPlayer(p *Projectile) bool {
+	for _, player := range ps.scene.players() {
+		if p.Hitbox().Overlaps(player.Hitbox()) {
+			player.TakeDamage(p.Damage, sign(int(math.Round(p.Pos.X))-player.Pos.X))
+			return true
+		}
+	}
+	return false
+}
+
+// hitEnemy damages the first enemy touching the provided projectile, returning true if an enemy was hit.
+func (ps *ProjectileSystem) hitEnemy(p *Projectile) bool {
+	for _, enemy := range ps.scene.enemies {
+		if p.Hitbox().Overlaps(enemy.Hitbox()) {
+			enemy.TakeDamage(p.Damage)
+			return true
+		}
+	}
+	return false
+}
+
+// Reflect reverses each active projectile whose center lies within radius of center, setting its damage to the
+// provided amount. Reflected projectiles damage enemies rather than the player. Returns the number of projectiles
+// reflected.
+func (ps *ProjectileSystem) Reflect(center Vec2, radius float64, damage int) (count int) {
+	for i := range ps.pool[:ps.active] {
+		p := &ps.pool[i]
+		if p.Reflected || p.Center().Sub(center).Mag() > radius {
+			continue
+		}
+		p.Vel.X, p.Vel.Y = -p.Vel.X, -p.Vel.Y
+		p.Damage = damage
+		p.Reflected = true
+		count++
+	}
+	return count
+}
+
+// Draw draws each active projectile to screen, offset by the provided camera position.
+func (ps *ProjectileSystem) Draw(screen *ebiten.Image, camera IVec2) {
+	for _, p := range ps.pool[:ps.active] {
+		hb := p.Hitbox().Add(camera)
+		vector.DrawFilledRect(screen, float32(hb.X), float32(hb.Y), float32(hb.W), float32(hb.H),
+			color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff}, false)
+	}
+}
+
+// Clear deactivates all projectiles.
+func (ps *ProjectileSystem) Clear() {
+	ps.active = 0
+}
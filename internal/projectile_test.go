@@ -0,0 +1,43 @@
+package internal
+
+import "testing"
+
+func TestProjectileStopsAtWall(t *testing.T) {
+	s := newTestScene(t,
+		"..........",
+		"........S.",
+		"........S.",
+		"..........",
+	)
+	wallX := 8.0 * testCellSize
+	s.projectiles.Spawn(Vec2{X: 8, Y: 20}, Vec2{X: 3}, 600, 1)
+	for i := 0; i < 600 && s.projectiles.active > 0; i++ {
+		last := s.projectiles.pool[0].Pos
+		if last.X+ProjectileSize > wallX {
+			t.Fatalf("projectile passed into the wall at %v", last)
+		}
+		s.projectiles.Update()
+		if s.projectiles.active == 0 && wallX-(last.X+ProjectileSize) > testCellSize {
+			t.Errorf("projectile deactivated %v pixels from the wall; want within %d", wallX-(last.X+ProjectileSize),
+				testCellSize)
+		}
+	}
+	if s.projectiles.active != 0 {
+		t.Fatal("projectile is still active")
+	}
+}
+
+func TestProjectileExpires(t *testing.T) {
+	s := newTestScene(t, grid(10, 4)...)
+	s.projectiles.Spawn(Vec2{X: 8, Y: 8}, Vec2{}, 3, 1)
+	for i := 0; i < 2; i++ {
+		s.projectiles.Update()
+	}
+	if s.projectiles.active != 1 {
+		t.Fatalf("projectile expired before its life ran out")
+	}
+	s.projectiles.Update()
+	if s.projectiles.active != 0 {
+		t.Errorf("projectile is still active after its life ran out")
+	}
+}
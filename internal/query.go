@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"github.com/niftysoft/2d-platformer/internal/pathfind"
+)
+
+// CircleCollides returns the union of the CollideMasks of all cells whose centers lie within radius of center, skipping
+// any cells which the provided ClipFunc clips through.
+func (s *PlatformerScene) CircleCollides(center Vec2, radius float64, clip ClipFunc) (result CollideMask) {
+	size := float64(s.cellSize)
+	minX, minY := s.screenToCell(center.X-radius, center.Y-radius)
+	maxX, maxY := s.screenToCell(center.X+radius, center.Y+radius)
+	for cx := minX; cx <= maxX; cx++ {
+		for cy := minY; cy <= maxY; cy++ {
+			cellCenter := Vec2{X: (float64(cx) + 0.5) * size, Y: (float64(cy) + 0.5) * size}
+			if cellCenter.Sub(center).Mag() > radius {
+				continue
+			}
+			if mask := s.gridDataI(cx, cy).CollideMask(); !clip(mask) {
+				result |= mask
+			}
+		}
+	}
+	return result
+}
+
+// LineCollides returns the union of the CollideMasks of all cells visited by Bresenham's line algorithm when walking
+// from the cell containing a to the cell containing b, skipping any cells which the provided ClipFunc clips through.
+func (s *PlatformerScene) LineCollides(a, b Vec2, clip ClipFunc) (result CollideMask) {
+	forAllLineCells(s.cellOf(a), s.cellOf(b), func(cx, cy int) {
+		if mask := s.gridDataI(cx, cy).CollideMask(); !clip(mask) {
+			result |= mask
+		}
+	})
+	return result
+}
+
+// Raycast walks the cells on the line from a to b, returning the coordinates of the first cell which is solid for the
+// provided ClipFunc. hit is false if no such cell is found.
+func (s *PlatformerScene) Raycast(a, b Vec2, clip ClipFunc) (cell IVec2, hit bool) {
+	forAllLineCells(s.cellOf(a), s.cellOf(b), func(cx, cy int) {
+		if hit {
+			return
+		}
+		if s.gridDataI(cx, cy).CollideMask().Colliding(clip) {
+			cell, hit = IVec2{X: cx, Y: cy}, true
+		}
+	})
+	return cell, hit
+}
+
+// EntityAt returns the first entity in the current level whose bounds contain the provided point.
+func (s *PlatformerScene) EntityAt(pt IVec2) (*Entity, bool) {
+	return s.entityIndex.at(pt)
+}
+
+// EntitiesIn returns every entity in the current level whose bounds overlap the provided region.
+func (s *PlatformerScene) EntitiesIn(region IRect) []*Entity {
+	return s.entityIndex.in(region)
+}
+
+// entityIndex looks up entities by their position. It is a simple list for now, but may be replaced by a spatial hash
+// if levels grow large enough to need one.
+type entityIndex []*Entity
+
+// at returns the first entity whose bounds contain pt.
+func (idx entityIndex) at(pt IVec2) (*Entity, bool) {
+	for _, entity := range idx {
+		if entity.PxBounds().Contains(pt) {
+			return entity, true
+		}
+	}
+	return nil, false
+}
+
+// in returns every entity whose bounds overlap region.
+func (idx entityIndex) in(region IRect) []*Entity {
+	var result []*Entity
+	for _, entity := range idx {
+		if entity.PxBounds().Overlaps(region) {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// cellOf returns the coordinates of the cell containing the provided point.
+func (s *PlatformerScene) cellOf(pt Vec2) IVec2 {
+	cx, cy := s.screenToCell(pt.X, pt.Y)
+	return IVec2{X: cx, Y: cy}
+}
+
+// forAllLineCells visits each cell on the line from start to end, inclusive, using Bresenham's line algorithm.
+func forAllLineCells(start, end IVec2, f func(cx, cy int)) {
+	x0, y0 := start.X, start.Y
+	dx, dy := abs(end.X-x0), -abs(end.Y-y0)
+	sx, sy := sign(end.X-x0), sign(end.Y-y0)
+	err := dx + dy
+	for {
+		f(x0, y0)
+		if x0 == end.X && y0 == end.Y {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// FindPath returns the shortest path through non-solid cells from the cell containing start to the cell containing
+// goal, both in pixel coordinates. The path is returned as the pixel coordinates of the center of each cell visited,
+// including the first and last. ok is false if no such path exists.
+func (s *PlatformerScene) FindPath(start, goal IVec2) (path []IVec2, ok bool) {
+	if s.cellsWide <= 0 {
+		return nil, false
+	}
+	grid := pathfind.Grid{
+		Wide:     s.cellsWide,
+		High:     len(s.intGridData) / s.cellsWide,
+		Walkable: func(idx int) bool { return !s.intGridData[idx].isSolid() },
+	}
+	from, to := s.cellOf(start.Vec2()), s.cellOf(goal.Vec2())
+	if from.X < 0 || from.X >= grid.Wide || to.X < 0 || to.X >= grid.Wide {
+		return nil, false // cells past the left or right edge would wrap onto the neighbouring row.
+	}
+	cells, ok := pathfind.AStar(grid, from.X+from.Y*grid.Wide, to.X+to.Y*grid.Wide)
+	if !ok {
+		return nil, false
+	}
+	path = make([]IVec2, len(cells))
+	for i, idx := range cells {
+		cell := IVec2{X: idx % grid.Wide, Y: idx / grid.Wide}
+		path[i] = cell.Scale(s.cellSize).Add(IVec2{X: s.cellSize / 2, Y: s.cellSize / 2})
+	}
+	return path, true
+}
@@ -0,0 +1,84 @@
+package internal
+
+import "testing"
+
+func TestCircleCollidesAdjacentCells(t *testing.T) {
+	s := newTestScene(t,
+		".....",
+		".BSB.",
+		".#.I.",
+		".BMB.",
+		".....",
+	)
+	center := Vec2{X: 2.5 * testCellSize, Y: 2.5 * testCellSize}
+	got := s.CircleCollides(center, testCellSize, ClipNone) & 0x3fffffff // unset flags.
+	if want := CollideMask(CollideStone | CollideDirt | CollideIce | CollideMagnet); got != want {
+		t.Errorf("CircleCollides() = %s; want %s", got.Describe(), want.Describe())
+	}
+	if got := s.CircleCollides(center, testCellSize-1, ClipNone); got != CollideNone {
+		t.Errorf("CircleCollides() with a radius under one cell = %s; want None", got.Describe())
+	}
+}
+
+func TestLineCollidesAndRaycast(t *testing.T) {
+	s := newTestScene(t,
+		"......",
+		"...S..",
+		"......",
+	)
+	a, b := Vec2{X: 8, Y: 24}, Vec2{X: 88, Y: 24}
+	if got := s.LineCollides(a, b, ClipNone); got != CollideStone {
+		t.Errorf("LineCollides() = %s; want Stone", got.Describe())
+	}
+	cell, hit := s.Raycast(a, b, ClipNone)
+	if want := (IVec2{X: 3, Y: 1}); !hit || cell != want {
+		t.Errorf("Raycast() = %v, %v; want %v, true", cell, hit, want)
+	}
+	if _, hit := s.Raycast(Vec2{X: 8, Y: 8}, Vec2{X: 88, Y: 8}, ClipNone); hit {
+		t.Error("Raycast() along an empty row hit something")
+	}
+}
+
+func TestEntityAt(t *testing.T) {
+	s := newTestScene(t, grid(8, 8)...)
+	coin := &Entity{ID: EtyCoin, PxCoords: IVec2{X: 10, Y: 10}, Dim: IDim{W: 16, H: 16}}
+	loadTestEntities(t, s, coin)
+
+	tests := []struct {
+		pt   IVec2
+		want bool
+	}{
+		{pt: IVec2{X: 18, Y: 18}, want: true},
+		{pt: IVec2{X: 10, Y: 10}, want: true},
+		{pt: IVec2{X: 25, Y: 25}, want: true},
+		{pt: IVec2{X: 26, Y: 26}, want: false},
+		{pt: IVec2{X: 30, Y: 30}, want: false},
+		{pt: IVec2{X: 9, Y: 18}, want: false},
+	}
+	for _, tt := range tests {
+		got, ok := s.EntityAt(tt.pt)
+		if ok != tt.want {
+			t.Errorf("EntityAt(%v) found = %v; want %v", tt.pt, ok, tt.want)
+		}
+		if ok && got != coin {
+			t.Errorf("EntityAt(%v) = %v; want the coin", tt.pt, got.ID)
+		}
+	}
+}
+
+func TestEntitiesIn(t *testing.T) {
+	s := newTestScene(t, grid(8, 8)...)
+	near := &Entity{ID: EtyCoin, PxCoords: IVec2{X: 10, Y: 10}, Dim: IDim{W: 16, H: 16}}
+	far := &Entity{ID: EtyKey, PxCoords: IVec2{X: 80, Y: 80}, Dim: IDim{W: 16, H: 16}}
+	loadTestEntities(t, s, near, far)
+
+	if got := s.EntitiesIn(IRect{X: 20, Y: 20, W: 10, H: 10}); len(got) != 1 || got[0] != near {
+		t.Errorf("EntitiesIn() overlapping only the coin = %v; want just the coin", got)
+	}
+	if got := s.EntitiesIn(IRect{X: 0, Y: 0, W: 128, H: 128}); len(got) != 2 {
+		t.Errorf("EntitiesIn() covering the level found %d entities; want 2", len(got))
+	}
+	if got := s.EntitiesIn(IRect{X: 40, Y: 40, W: 10, H: 10}); len(got) != 0 {
+		t.Errorf("EntitiesIn() of an empty region found %d entities; want 0", len(got))
+	}
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MarkDirty queues the provided region of the background to be redrawn at the end of the current update.
+func (s *PlatformerScene) MarkDirty(region IRect) {
+	s.dirtyRegions = append(s.dirtyRegions, region)
+}
+
+// flushDirtyRegions redraws every region of the background which has been marked dirty since the last flush.
+func (s *PlatformerScene) flushDirtyRegions() {
+	for _, region := range s.dirtyRegions {
+		s.redrawTiles(region)
+	}
+	s.dirtyRegions = s.dirtyRegions[:0]
+}
+
+// redrawTiles repaints the provided region of the background with the level's background color and redraws every tile
+// in the current level which overlaps it, in draw order.
+func (s *PlatformerScene) redrawTiles(region IRect) {
+	s.background.SubImage(region.Rectangle()).(*ebiten.Image).Fill(s.level.BGColor) // safe; guaranteed per docs.
+	for _, layer := range s.level.layers {
+		s.RedrawRegion(layer, region)
+	}
+	opts := ebiten.DrawImageOptions{}
+	s.drawDoorTiles(region, &opts)
+}
+
+// RedrawRegion draws only those tiles of the provided layer which overlap region onto the background. The region is not
+// cleared first; see redrawTiles.
+func (s *PlatformerScene) RedrawRegion(layer *TileLayer, region IRect) {
+	if layer.TileSetUID == nil {
+		return
+	}
+	tileset, ok := s.gdat.Tilesets[*layer.TileSetUID]
+	if !ok {
+		return
+	}
+	opts := ebiten.DrawImageOptions{}
+	retiled := s.retiled[layer]
+	for _, tile := range layer.Tiles {
+		if s.isDoorTile(tile) || s.isBrokenTile(tile) || s.isRetiled(layer, tile) {
+			continue
+		}
+		if region.Overlaps(IRect{X: tile.PxCoords.X, Y: tile.PxCoords.Y, W: layer.GridSize, H: layer.GridSize}) {
+			s.drawTile(tileset, layer, tile, &opts)
+		}
+	}
+	for cell, tiles := range retiled {
+		if !region.Overlaps(IRect{X: cell.X * layer.GridSize, Y: cell.Y * layer.GridSize, W: layer.GridSize, H: layer.GridSize}) {
+			continue
+		}
+		for _, tile := range tiles {
+			s.drawTile(tileset, layer, tile, &opts)
+		}
+	}
+}
+
+// isRetiled returns true if the cell of the provided tile has been retiled since the level was loaded, replacing it.
+func (s *PlatformerScene) isRetiled(layer *TileLayer, tile Tile) bool {
+	retiled, ok := s.retiled[layer]
+	if !ok {
+		return false
+	}
+	_, ok = retiled[IVec2{X: tile.PxCoords.X / layer.GridSize, Y: tile.PxCoords.Y / layer.GridSize}]
+	return ok
+}
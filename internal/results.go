@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"image/color"
+)
+
+// ResultsDuration is the number of seconds the results of a level are shown before continuing automatically.
+const ResultsDuration = 5
+
+// LevelCompletion summarizes the player's performance in a single level.
+type LevelCompletion struct {
+	CoinsCollected int     `json:"coinsCollected"` // CoinsCollected is the number of coins collected by the player.
+	TotalCoins     int     `json:"totalCoins"`     // TotalCoins is the number of coins available in the level.
+	TimeSeconds    float64 `json:"timeSeconds"`    // TimeSeconds is the time taken to complete the level.
+	Reached        bool    `json:"reached"`        // Reached is true if the player reached the level's exit.
+}
+
+// ResultsScene displays a LevelCompletion over the scene beneath it. Once the results have been shown for
+// ResultsDuration seconds, or the player presses any key, the scene pops itself and calls onDone.
+type ResultsScene struct {
+	*BaseScene
+	completion LevelCompletion
+	framesLeft int
+	onDone     func()
+	keys       []ebiten.Key
+}
+
+// NewResultsScene constructs a new ResultsScene displaying the provided LevelCompletion. onDone is called once the
+// scene has been popped.
+func NewResultsScene(g *Game, completion LevelCompletion, onDone func()) *ResultsScene {
+	return &ResultsScene{
+		BaseScene:  NewBaseScene(g),
+		completion: completion,
+		framesLeft: int(ResultsDuration * TPS),
+		onDone:     onDone,
+	}
+}
+
+// Update counts down until the results are dismissed.
+func (s *ResultsScene) Update() error {
+	s.framesLeft--
+	s.keys = inpututil.AppendJustPressedKeys(s.keys[:0])
+	if s.framesLeft > 0 && len(s.keys) == 0 {
+		return nil
+	}
+	s.game.PopScene()
+	if s.onDone != nil {
+		s.onDone()
+	}
+	return nil
+}
+
+// Draw draws the level results.
+func (s *ResultsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	s.game.font.DrawText(screen, "LEVEL COMPLETE", 111, 80, nil)
+	s.game.font.DrawText(screen, fmt.Sprintf("Coins: %d / %d", s.completion.CoinsCollected, s.completion.TotalCoins), 111, 110, nil)
+	s.game.font.DrawText(screen, fmt.Sprintf("Time:  %.2fs", s.completion.TimeSeconds), 111, 125, nil)
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+)
+
+// LevelRNG is the source of randomness for everything in a level. It is reseeded each time a level is loaded from the
+// level's ID and the global seed, so that a given seed always produces the same level.
+type LevelRNG struct {
+	*rand.Rand
+}
+
+// NewLevelRNG constructs a LevelRNG; it should be reset before each level is played.
+func NewLevelRNG() *LevelRNG {
+	return &LevelRNG{Rand: rand.New(rand.NewSource(options.Seed))}
+}
+
+// Reset reseeds this LevelRNG for the level with the provided ID.
+func (r *LevelRNG) Reset(levelID string) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(levelID + strconv.FormatInt(options.Seed, 10))) // never returns an error.
+	r.Seed(int64(h.Sum64()))
+}
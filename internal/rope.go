@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"math"
+)
+
+const RopeGrabRange = 24       // RopeGrabRange is the furthest distance in pixels from an anchor at which the player can grab its rope.
+const RopePumpAccel = 0.005    // RopePumpAccel is the angular acceleration in radians per frame^2 gained by pumping the swing.
+const RopePumpWindow = 0.01    // RopePumpWindow is the angular speed in radians per frame below which the swing is at an extreme.
+const RopeMaxAngularVel = 0.25 // RopeMaxAngularVel is the fastest the player can swing in radians per frame.
+
+var ropeColor = color.RGBA{R: 0xa0, G: 0x80, B: 0x50, A: 0xff}
+
+// RopeAnchor is a point from which the player can swing on a rope.
+type RopeAnchor struct {
+	Pos Vec2 // Pos is the position of the anchor in world coordinates.
+}
+
+// NewRopeAnchor constructs a RopeAnchor at the center of the provided entity.
+func NewRopeAnchor(entity *Entity) RopeAnchor {
+	return RopeAnchor{Pos: entity.PxBounds().Center().Vec2()}
+}
+
+// ropeAnchorNear returns the closest rope anchor within RopeGrabRange of the provided point, if any.
+func (s *PlatformerScene) ropeAnchorNear(pt Vec2) (result RopeAnchor, ok bool) {
+	best := float64(RopeGrabRange)
+	for _, anchor := range s.ropeAnchors {
+		if dist := anchor.Pos.Sub(pt).Mag(); dist <= best {
+			result, best, ok = anchor, dist, true
+		}
+	}
+	return result, ok
+}
+
+// pendulumStep advances a pendulum of the provided length by one frame under gravity g, given in pixels per frame^2.
+// angle is measured in radians from straight down, and is positive to the right of the anchor. Semi-implicit Euler integration
+// is used so the swing neither gains nor loses energy over time.
+func pendulumStep(angle, angularVel, length, g float64) (float64, float64) {
+	angularVel -= g / length * math.Sin(angle)
+	return angle + angularVel, angularVel
+}
+
+// pendulumPos returns the position of a pendulum bob hanging from anchor by the provided length at the provided angle.
+func pendulumPos(anchor Vec2, length, angle float64) Vec2 {
+	return anchor.Add(Vec2{X: length * math.Sin(angle), Y: length * math.Cos(angle)})
+}
+
+// DrawRope draws the rope between the player and its anchor while swinging.
+func (p *Player) DrawRope(screen *ebiten.Image, camera IVec2) {
+	if p.state() != PlayerStateSwinging {
+		return
+	}
+	from, to := p.center().Add(camera.Vec2()), p.ropeAnchor.Add(camera.Vec2())
+	vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 1, ropeColor, false)
+}
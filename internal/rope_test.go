@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPendulumStepConservesEnergy(t *testing.T) {
+	newTestGame(t)
+	const length = 64
+	angle, vel := math.Pi/4, 0.0
+	swung := false
+	for i := 0; i < 1000; i++ {
+		prev := vel
+		angle, vel = pendulumStep(angle, vel, length, Gravity/TPS)
+		if prev < 0 && vel >= 0 { // the swing has reached the other side.
+			swung = true
+			break
+		}
+	}
+	if !swung {
+		t.Fatalf("pendulum never reached the other side")
+	}
+	want := length * math.Cos(math.Pi/4)
+	if got := length * math.Cos(angle); math.Abs(got-want) > 1 {
+		t.Errorf("pendulum peaked %v pixels below the anchor; want %v, as at the start", got, want)
+	}
+}
+
+func TestSwingReachesSameHeight(t *testing.T) {
+	s := newTestScene(t, grid(40, 20)...)
+	p := newTestPlayer(t, s, IVec2{})
+	anchor := RopeAnchor{Pos: Vec2{X: 320, Y: 48}}
+	start := pendulumPos(anchor.Pos, 64, -math.Pi/4)
+	delta := start.Sub(p.center())
+	p.Pos = p.Pos.Add(IVec2{X: int(math.Round(delta.X)), Y: int(math.Round(delta.Y))})
+	p.Vel = Vec2{}
+	p.states.Set(p.startSwinging(anchor))
+	startY := p.center().Y
+
+	peakY := math.Inf(1)
+	for i := 0; i < 1000; i++ {
+		prev := p.ropeAngularVel
+		updatePlayer(p, 0)
+		if p.state() != PlayerStateSwinging {
+			t.Fatalf("player let go of the rope; state is %v", p.state())
+		}
+		if p.center().X > anchor.Pos.X {
+			peakY = min(peakY, p.center().Y)
+		}
+		if prev > 0 && p.ropeAngularVel <= 0 {
+			break
+		}
+	}
+	if p.center().X <= anchor.Pos.X {
+		t.Fatalf("player swung to %v; want them on the other side of the anchor at %v", p.center(), anchor.Pos)
+	}
+	if math.Abs(peakY-startY) > 2 {
+		t.Errorf("player peaked at Y = %v on the other side; want about %v, where they started", peakY, startY)
+	}
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"time"
+)
+
+const (
+	HardLandThreshold = 48 // HardLandThreshold is the distance in pixels the player must fall for landing to rumble.
+
+	LandRumbleStrong   = 0.5                    // LandRumbleStrong is the low-frequency magnitude of a hard landing.
+	LandRumbleWeak     = 0.3                    // LandRumbleWeak is the high-frequency magnitude of a hard landing.
+	LandRumbleDuration = 100 * time.Millisecond // LandRumbleDuration is the length of a hard landing's rumble.
+
+	DamageRumbleStrong   = 1.0                    // DamageRumbleStrong is the low-frequency magnitude of taking damage.
+	DamageRumbleWeak     = 0.6                    // DamageRumbleWeak is the high-frequency magnitude of taking damage.
+	DamageRumbleDuration = 200 * time.Millisecond // DamageRumbleDuration is the length of taking damage's rumble.
+)
+
+// RumbleManager vibrates the gamepad the player is using in response to game events. It becomes active on the last
+// gamepad a button was pressed on and is deactivated, cancelling any rumble, as soon as a key is pressed or the gamepad
+// is disconnected.
+type RumbleManager struct {
+	gamepadID ebiten.GamepadID
+	active    bool      // active is true while gamepadID is the player's input device.
+	settings  *Settings // settings controls whether rumble is enabled at all.
+
+	vibrate func(id ebiten.GamepadID, options *ebiten.VibrateGamepadOptions) // vibrate rumbles a gamepad.
+
+	gamepadIDs []ebiten.GamepadID     // gamepadIDs is scratch space for the connected gamepads.
+	buttons    []ebiten.GamepadButton // buttons is scratch space for the buttons pressed on a gamepad.
+	keys       []ebiten.Key           // keys is scratch space for the keys pressed this frame.
+}
+
+// NewRumbleManager constructs a RumbleManager which rumbles on each event published to the provided bus that calls for
+// it.
+func NewRumbleManager(bus *EventBus, settings *Settings) *RumbleManager {
+	result := &RumbleManager{settings: settings, vibrate: ebiten.VibrateGamepad}
+	bus.Subscribe(TopicPlayerDamaged, func(any) {
+		result.Rumble(DamageRumbleStrong, DamageRumbleWeak, DamageRumbleDuration)
+	})
+	bus.Subscribe(TopicPlayerLanded, func(data any) {
+		if data.(int) > HardLandThreshold {
+			result.Rumble(LandRumbleStrong, LandRumbleWeak, LandRumbleDuration)
+		}
+	})
+	return result
+}
+
+// Update tracks which input device the player is using. It should be called once per frame.
+func (r *RumbleManager) Update() {
+	if r.active && inpututil.IsGamepadJustDisconnected(r.gamepadID) {
+		r.active = false
+	}
+	if r.keys = inpututil.AppendJustPressedKeys(r.keys[:0]); len(r.keys) > 0 {
+		r.useKeyboard()
+	}
+	r.gamepadIDs = ebiten.AppendGamepadIDs(r.gamepadIDs[:0])
+	for _, id := range r.gamepadIDs {
+		if r.buttons = inpututil.AppendJustPressedGamepadButtons(id, r.buttons[:0]); len(r.buttons) > 0 {
+			r.useGamepad(id)
+		}
+	}
+}
+
+// useKeyboard deactivates the gamepad when the player switches to the keyboard, cancelling any rumble in progress.
+func (r *RumbleManager) useKeyboard() {
+	if r.active {
+		r.Rumble(0, 0, 0)
+		r.active = false
+	}
+}
+
+// useGamepad makes the gamepad with the provided ID the one which rumbles.
+func (r *RumbleManager) useGamepad(id ebiten.GamepadID) {
+	r.gamepadID, r.active = id, true
+}
+
+// Rumble vibrates the active gamepad with the provided magnitudes, each between 0 and 1, for the provided duration.
+// Nothing happens if no gamepad is active or rumble is disabled in the settings.
+func (r *RumbleManager) Rumble(strong, weak float64, duration time.Duration) {
+	if !r.active || !r.settings.RumbleEnabled {
+		return
+	}
+	r.vibrate(r.gamepadID, &ebiten.VibrateGamepadOptions{
+		Duration:        duration,
+		StrongMagnitude: strong,
+		WeakMagnitude:   weak,
+	})
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"testing"
+)
+
+// newTestRumble returns a RumbleManager whose vibrations are recorded in the returned slice instead of sent to a
+// gamepad.
+func newTestRumble(enabled bool) (*RumbleManager, *EventBus, *[]ebiten.VibrateGamepadOptions) {
+	bus := NewEventBus()
+	settings := NewSettings()
+	settings.RumbleEnabled = enabled
+	r := NewRumbleManager(bus, settings)
+	var calls []ebiten.VibrateGamepadOptions
+	r.vibrate = func(_ ebiten.GamepadID, opts *ebiten.VibrateGamepadOptions) { calls = append(calls, *opts) }
+	return r, bus, &calls
+}
+
+func TestRumbleCancelledOnSwitchToKeyboard(t *testing.T) {
+	r, bus, calls := newTestRumble(true)
+	r.useGamepad(0)
+	bus.Publish(TopicPlayerDamaged, 2)
+	r.useKeyboard()
+	want := []ebiten.VibrateGamepadOptions{
+		{Duration: DamageRumbleDuration, StrongMagnitude: DamageRumbleStrong, WeakMagnitude: DamageRumbleWeak},
+		{}, // Rumble(0, 0, 0) cancels the rumble in progress.
+	}
+	if len(*calls) != len(want) || (*calls)[0] != want[0] || (*calls)[1] != want[1] {
+		t.Fatalf("vibrations = %+v; want %+v", *calls, want)
+	}
+	r.useKeyboard()
+	bus.Publish(TopicPlayerDamaged, 1)
+	if len(*calls) != len(want) {
+		t.Errorf("vibrations while using the keyboard = %+v; want none after %+v", (*calls)[len(want):], want)
+	}
+}
+
+func TestRumbleEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		topic     string
+		data      any
+		wantCalls int
+	}{
+		{name: "damage", enabled: true, topic: TopicPlayerDamaged, data: 2, wantCalls: 1},
+		{name: "hard landing", enabled: true, topic: TopicPlayerLanded, data: HardLandThreshold + 1, wantCalls: 1},
+		{name: "soft landing", enabled: true, topic: TopicPlayerLanded, data: HardLandThreshold},
+		{name: "disabled", enabled: false, topic: TopicPlayerDamaged, data: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, bus, calls := newTestRumble(tt.enabled)
+			r.useGamepad(0)
+			bus.Publish(tt.topic, tt.data)
+			if len(*calls) != tt.wantCalls {
+				t.Errorf("got %d vibrations; want %d", len(*calls), tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"io"
+)
+
+// SaveData is the player's progress which persists across levels and play sessions.
+type SaveData struct {
+	TriggeredDialogues map[uuid.UUID]bool `json:"triggeredDialogues"` // TriggeredDialogues is the set of one-shot dialogues already shown.
+	// Completion holds the player's best results for each completed level, keyed by level ID.
+	Completion map[string]LevelCompletion `json:"completion"`
+}
+
+// NewSaveData constructs empty SaveData for a new game.
+func NewSaveData() *SaveData {
+	return &SaveData{
+		TriggeredDialogues: make(map[uuid.UUID]bool),
+		Completion:         make(map[string]LevelCompletion),
+	}
+}
+
+// ReadSaveData reads SaveData previously written by Write.
+func ReadSaveData(r io.Reader) (*SaveData, error) {
+	result := NewSaveData()
+	if err := json.NewDecoder(r).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Write writes this SaveData to the provided writer.
+func (d *SaveData) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(d)
+}
+
+// RecordCompletion merges the provided results into the best results recorded for the level with the provided ID. The
+// fastest time and the most coins collected are kept, even if they were reached in different attempts.
+func (d *SaveData) RecordCompletion(levelID string, c LevelCompletion) {
+	if !c.Reached {
+		return
+	}
+	best, ok := d.Completion[levelID]
+	if !ok {
+		d.Completion[levelID] = c
+		return
+	}
+	best.TimeSeconds = min(best.TimeSeconds, c.TimeSeconds)
+	if c.CoinsCollected > best.CoinsCollected {
+		best.CoinsCollected, best.TotalCoins = c.CoinsCollected, c.TotalCoins
+	}
+	d.Completion[levelID] = best
+}
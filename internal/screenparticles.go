@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+)
+
+// ScreenParticle is a purely visual effect which moves in screen coordinates, such as a score popup. Particles with
+// non-empty Text draw the text; all others draw a circle of radius Scale.
+type ScreenParticle struct {
+	ScreenPos     Vec2
+	Vel           Vec2
+	Life, MaxLife int // Life is the number of frames remaining before the particle disappears.
+	Text          string
+	Scale         float64
+	Color         color.RGBA
+}
+
+// ScreenParticleSystem updates and draws particles in screen coordinates, unaffected by the camera.
+type ScreenParticleSystem struct {
+	font      *BitmapFont
+	particles []ScreenParticle
+}
+
+// NewScreenParticleSystem constructs an empty ScreenParticleSystem which draws text using the provided font.
+func NewScreenParticleSystem(font *BitmapFont) *ScreenParticleSystem {
+	return &ScreenParticleSystem{font: font}
+}
+
+// Emit spawns a single particle at pos moving with the provided velocity. Text particles are drawn scaled by scale.
+func (ps *ScreenParticleSystem) Emit(pos, vel Vec2, life int, text string, scale float64, c color.RGBA) {
+	ps.particles = append(ps.particles, ScreenParticle{
+		ScreenPos: pos,
+		Vel:       vel,
+		Life:      life,
+		MaxLife:   life,
+		Text:      text,
+		Scale:     scale,
+		Color:     c,
+	})
+}
+
+// Update moves each particle and removes those which have expired.
+func (ps *ScreenParticleSystem) Update() {
+	remaining := ps.particles[:0]
+	for _, p := range ps.particles {
+		p.Life--
+		if p.Life <= 0 {
+			continue
+		}
+		p.ScreenPos = p.ScreenPos.Add(p.Vel)
+		remaining = append(remaining, p)
+	}
+	ps.particles = remaining
+}
+
+// Draw draws each particle to screen. Particles fade out as they expire.
+func (ps *ScreenParticleSystem) Draw(screen *ebiten.Image) {
+	opts := ebiten.DrawImageOptions{}
+	for _, p := range ps.particles {
+		alpha := float32(p.Life) / float32(p.MaxLife)
+		if p.Text == "" {
+			c := fadeRGBA(p.Color, alpha)
+			vector.DrawFilledCircle(screen, float32(p.ScreenPos.X), float32(p.ScreenPos.Y), float32(p.Scale), c, false)
+			continue
+		}
+		opts.GeoM.Reset()
+		opts.GeoM.Scale(p.Scale, p.Scale)
+		opts.GeoM.Translate(p.ScreenPos.X, p.ScreenPos.Y)
+		opts.ColorScale.Reset()
+		opts.ColorScale.ScaleWithColor(p.Color)
+		opts.ColorScale.ScaleAlpha(alpha)
+		ps.font.DrawText(screen, p.Text, 0, 0, &opts)
+	}
+}
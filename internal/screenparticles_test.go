@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"testing"
+)
+
+func TestScreenParticleCircleFades(t *testing.T) {
+	tests := []struct {
+		name string
+		life int
+		want color.RGBA
+	}{
+		{name: "full life", life: 4, want: color.RGBA{R: 0xff, G: 0x80, A: 0xff}},
+		{name: "half life", life: 2, want: color.RGBA{R: 0x7f, G: 0x40, A: 0x7f}},
+		{name: "quarter life", life: 1, want: color.RGBA{R: 0x3f, G: 0x20, A: 0x3f}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := NewScreenParticleSystem(nil)
+			ps.Emit(Vec2{X: 8, Y: 8}, Vec2{}, 4, "", 3, color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+			ps.particles[0].Life = tt.life
+			screen := ebiten.NewImage(16, 16)
+			ps.Draw(screen)
+
+			got := color.RGBAModel.Convert(screen.At(8, 8)).(color.RGBA)
+			if got != tt.want {
+				t.Errorf("pixel at the centre of the particle = %v; want %v", got, tt.want)
+			}
+			if got.R > got.A || got.G > got.A || got.B > got.A {
+				t.Errorf("pixel at the centre of the particle = %v; want a valid premultiplied colour", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package internal
+
+// Step is a single step of a Sequence. It returns the number of frames to wait before running the next step.
+type Step func() (waitFrames int)
+
+// Sequence runs a list of steps one after another, waiting the number of frames returned by each step before running
+// the next. Steps which return zero are followed immediately by the next step in the same frame.
+type Sequence struct {
+	steps   []Step
+	current int // current is the index of the next step to run.
+	wait    int // wait is the number of frames remaining before the next step runs.
+}
+
+// NewSequence constructs a Sequence which runs the provided steps in order.
+func NewSequence(steps ...Step) *Sequence {
+	return &Sequence{steps: steps}
+}
+
+// Update advances the sequence by one frame, running any steps which are due. Returns true once all steps have run.
+func (s *Sequence) Update() bool {
+	if s.wait > 0 {
+		s.wait--
+		if s.wait > 0 {
+			return false
+		}
+	}
+	for s.current < len(s.steps) {
+		s.wait = s.steps[s.current]()
+		s.current++
+		if s.wait > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// RunSequence starts running the provided sequence. Its first step runs during the next update. Running sequences
+// are stopped when a new level is loaded.
+func (s *PlatformerScene) RunSequence(seq *Sequence) {
+	s.sequences = append(s.sequences, seq)
+}
+
+// updateSequences advances every running sequence, removing any which have finished.
+func (s *PlatformerScene) updateSequences() {
+	remaining := s.sequences[:0]
+	for _, seq := range s.sequences {
+		if !seq.Update() {
+			remaining = append(remaining, seq)
+		}
+	}
+	s.sequences = remaining
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestSequenceStepTiming(t *testing.T) {
+	waits := []int{5, 10, 5}
+	var fired []int
+	frame := 0
+	var steps []Step
+	for _, wait := range waits {
+		wait := wait
+		steps = append(steps, func() int {
+			fired = append(fired, frame)
+			return wait
+		})
+	}
+	seq := NewSequence(steps...)
+
+	done := -1
+	for frame = 1; frame <= 30 && done < 0; frame++ {
+		if seq.Update() {
+			done = frame
+		}
+	}
+	want := []int{1, 6, 16}
+	if len(fired) != len(want) {
+		t.Fatalf("steps fired on frames %v; want %v", fired, want)
+	}
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Errorf("step %d fired on frame %d; want %d", i, fired[i], want[i])
+		}
+	}
+	if done != 21 {
+		t.Errorf("Update() first returned true on frame %d; want 21, once the last wait elapsed", done)
+	}
+}
+
+func TestSequenceZeroWaitRunsSameFrame(t *testing.T) {
+	var ran int
+	step := func() int { ran++; return 0 }
+	if !NewSequence(step, step, step).Update() {
+		t.Errorf("Update() = false for steps which never wait; want true")
+	}
+	if ran != 3 {
+		t.Errorf("%d steps ran in the first frame; want 3", ran)
+	}
+}
+
+func TestUpdateSequencesRemovesFinished(t *testing.T) {
+	s := newTestScene(t, grid(4, 4)...)
+	s.RunSequence(NewSequence(func() int { return 0 }))
+	s.RunSequence(NewSequence(func() int { return 2 }))
+	s.updateSequences()
+	if len(s.sequences) != 1 {
+		t.Errorf("%d sequences running after one finished; want 1", len(s.sequences))
+	}
+	s.updateSequences()
+	s.updateSequences()
+	if len(s.sequences) != 0 {
+		t.Errorf("%d sequences running after both finished; want 0", len(s.sequences))
+	}
+}
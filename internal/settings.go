@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Settings holds the player's preferences, which persist across play sessions.
+type Settings struct {
+	MasterVolume   float64                   `json:"masterVolume"`   // MasterVolume scales the volume of every audio channel.
+	ChannelVolumes [numAudioChannels]float64 `json:"channelVolumes"` // ChannelVolumes holds the volume of each AudioChannel.
+	RumbleEnabled  bool                      `json:"rumbleEnabled"`  // RumbleEnabled vibrates the gamepad on game events.
+}
+
+// NewSettings constructs the default Settings, with every channel at full volume.
+func NewSettings() *Settings {
+	return &Settings{
+		MasterVolume:   1,
+		ChannelVolumes: [numAudioChannels]float64{1, 1, 1},
+		RumbleEnabled:  true,
+	}
+}
+
+// ReadSettings reads Settings previously written by Write. Any settings missing from the input keep their defaults.
+func ReadSettings(r io.Reader) (*Settings, error) {
+	result := NewSettings()
+	if err := json.NewDecoder(r).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Write writes these Settings to the provided writer.
+func (s *Settings) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,18 @@
+// Package shader holds the Kage shaders used to post-process the screen.
+package shader
+
+import (
+	_ "embed"
+)
+
+// CRT is the source of a shader which draws scanlines and a slight colour aberration over the screen. Its Time uniform
+// is the number of seconds the game has been running.
+//
+//go:embed crt.kage
+var CRT []byte
+
+// Water is the source of a shader which ripples the screen along a sine wave. Its Time uniform is the number of
+// seconds the game has been running; Amplitude and Frequency shape the ripples.
+//
+//go:embed water.kage
+var Water []byte
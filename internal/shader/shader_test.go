@@ -0,0 +1,40 @@
+package shader
+
+import (
+	"errors"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/niftysoft/2d-platformer/internal/testutil"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests from within the game loop, since shaders can only be compiled once a graphics context exists.
+// The tests are skipped if there is no display to create one on.
+func TestMain(m *testing.M) {
+	code, err := testutil.RunTests(m)
+	if errors.Is(err, testutil.ErrNoDisplay) {
+		slog.Warn("skipping shader tests", "err", err)
+		os.Exit(0)
+	}
+	if err != nil {
+		slog.Error("could not run shader tests", "err", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+func TestShadersCompile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []byte
+	}{
+		{"crt.kage", CRT},
+		{"water.kage", Water},
+	}
+	for _, tt := range tests {
+		if _, err := ebiten.NewShader(tt.src); err != nil {
+			t.Errorf("NewShader(%s) = %v", tt.name, err)
+		}
+	}
+}
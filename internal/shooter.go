@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const (
+	ShooterEnemyHP          = 1   // ShooterEnemyHP is the health of each ShooterEnemy.
+	LineOfSightTolerance    = 16  // LineOfSightTolerance is how far apart vertically a ShooterEnemy and the player may be for it to fire.
+	DefaultShootInterval    = 90  // DefaultShootInterval is the default number of frames between each shot.
+	DefaultProjectileSpeed  = 2.0 // DefaultProjectileSpeed is the default speed of each shot in pixels per frame.
+	ShooterProjectileLife   = 180 // ShooterProjectileLife is the number of frames each shot lasts.
+	ShooterProjectileDamage = 1   // ShooterProjectileDamage is the damage dealt by each shot.
+)
+
+// ShooterEnemy stands in place, firing projectiles horizontally at the player whenever they are roughly level with it.
+type ShooterEnemy struct {
+	enemyBody
+	shootInterval   int      // shootInterval is the minimum number of frames between each shot.
+	shootCooldown   Cooldown // shootCooldown must elapse between each shot.
+	projectileSpeed float64  // projectileSpeed is the speed of each shot in pixels per frame.
+}
+
+// NewShooterEnemy constructs a ShooterEnemy from the provided entity. The "shoot_interval" field gives the number of
+// frames between shots and the "projectile_speed" field gives the speed of each shot.
+func NewShooterEnemy(s *PlatformerScene, entity *Entity) (Enemy, error) {
+	result := &ShooterEnemy{
+		enemyBody:       newEnemyBody(s, entity, ShooterEnemyHP),
+		shootInterval:   entity.FieldInt("shoot_interval"),
+		projectileSpeed: entity.FieldFloat("projectile_speed"),
+	}
+	if result.shootInterval <= 0 {
+		result.shootInterval = DefaultShootInterval
+	}
+	if result.projectileSpeed <= 0 {
+		result.projectileSpeed = DefaultProjectileSpeed
+	}
+	result.shootCooldown = NewCooldown(result.shootInterval)
+	result.shootCooldown.Start()
+	return result, nil
+}
+
+// Update fires at the nearest player in line of sight once the cooldown has elapsed.
+func (e *ShooterEnemy) Update() {
+	e.move()
+	e.shootCooldown.Tick()
+	player := e.playerInSight()
+	if !e.shootCooldown.Ready() || player == nil {
+		return
+	}
+	dir := float64(sign(player.Pos.X - e.Pos.X))
+	if dir == 0 {
+		dir = 1
+	}
+	center := e.Hitbox().Center().Vec2()
+	e.scene.projectiles.Spawn(center, Vec2{X: dir * e.projectileSpeed}, ShooterProjectileLife, ShooterProjectileDamage)
+	e.shootCooldown.Start()
+}
+
+// playerInSight returns the nearest player in line of sight, or nil if neither is.
+func (e *ShooterEnemy) playerInSight() *Player {
+	var result *Player
+	for _, p := range e.scene.players() {
+		if abs(p.Pos.Y-e.Pos.Y) >= LineOfSightTolerance {
+			continue
+		}
+		if result == nil || abs(p.Pos.X-e.Pos.X) < abs(result.Pos.X-e.Pos.X) {
+			result = p
+		}
+	}
+	return result
+}
+
+// Draw draws the enemy as a placeholder rectangle.
+func (e *ShooterEnemy) Draw(screen *ebiten.Image, camera IVec2) {
+	e.drawRect(screen, camera, color.RGBA{R: 0xe0, G: 0xa0, B: 0x20, A: 0xff})
+}
@@ -0,0 +1,46 @@
+package internal
+
+import "testing"
+
+func TestShooterEnemyCooldown(t *testing.T) {
+	const interval = 10
+	s := newTestScene(t, grid(40, 8)...)
+	newTestPlayer(t, s, IVec2{X: 64, Y: 64})
+	loadTestEntities(t, s, &Entity{
+		ID:       EtyShooterEnemy,
+		PxCoords: IVec2{X: 320, Y: s.level.PxDims.H - testCellSize - 16},
+		Dim:      IDim{W: 16, H: 16},
+		Fields:   map[string]any{"shoot_interval": float64(interval)},
+	})
+	e, p := s.enemies[0].(*ShooterEnemy), s.player // loading entities again respawns the player.
+
+	var shots []int
+	for frame := 0; frame < 5*interval; frame++ {
+		p.Pos.Y = e.Pos.Y
+		before := s.projectiles.active
+		e.Update()
+		if s.projectiles.active > before {
+			shots = append(shots, frame)
+		}
+	}
+	if len(shots) != 5 {
+		t.Errorf("enemy shot on frames %v; want 5 shots in %d frames", shots, 5*interval)
+	}
+	for i := 1; i < len(shots); i++ {
+		if shots[i]-shots[i-1] < interval {
+			t.Errorf("enemy shot on frames %d and %d; want at least %d frames apart", shots[i-1], shots[i], interval)
+		}
+	}
+	if vel := s.projectiles.pool[0].Vel; vel.X >= 0 {
+		t.Errorf("shot velocity = %v; want it moving left toward the player", vel)
+	}
+
+	active := s.projectiles.active
+	for frame := 0; frame < 2*interval; frame++ {
+		p.Pos.Y = e.Pos.Y - LineOfSightTolerance
+		e.Update()
+	}
+	if s.projectiles.active != active {
+		t.Errorf("enemy fired %d shots at a player out of its line of sight", s.projectiles.active-active)
+	}
+}
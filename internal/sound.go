@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	music "github.com/niftysoft/2d-platformer/internal/audio"
+	"io"
+	"log/slog"
+	"math"
+)
+
+const (
+	SampleRate = 44100 // SampleRate is the sample rate all audio is decoded at.
+	MusicFade  = 60    // MusicFade is the number of frames taken to cross-fade between level soundtracks.
+
+	// MaxAudibleRange is the horizontal distance from the listener, in pixels, at which sounds are panned fully to one
+	// side.
+	MaxAudibleRange = 240.0
+)
+
+// AudioChannel is a group of sounds whose volume is set together.
+type AudioChannel int
+
+const (
+	ChannelSFX      AudioChannel = iota // ChannelSFX is the channel of all sound effects.
+	ChannelMusic                        // ChannelMusic is the channel of level soundtracks.
+	ChannelAmbience                     // ChannelAmbience is the channel of looping background sounds.
+	numAudioChannels
+)
+
+// sfxPlayer is the part of *audio.Player used to play sound effects, allowing playback to be observed in tests.
+type sfxPlayer interface {
+	SetVolume(volume float64)
+	Play()
+}
+
+// AudioManager loads and plays sound effects and music by name. Sound effects are loaded on first use from
+// gamedata/sfx/<name>.wav and tracks from gamedata/music/<name>.wav; missing sounds are logged once and otherwise
+// ignored.
+type AudioManager struct {
+	ctx    *audio.Context
+	sounds map[string][]byte // sounds caches decoded PCM data by name; a nil entry marks a sound which failed to load.
+	music  *music.SoundtrackManager
+
+	newPlayer func(pcm []byte) sfxPlayer // newPlayer constructs a player for each sound effect played.
+
+	listener Vec2 // listener is the world position sounds played with PlaySFXAt are panned relative to.
+
+	masterVolume   float64                   // masterVolume scales the volume of every channel.
+	channelVolumes [numAudioChannels]float64 // channelVolumes holds the volume of each channel, before masterVolume.
+}
+
+// NewAudioManager constructs a new AudioManager.
+func NewAudioManager() *AudioManager {
+	ctx := audio.NewContext(SampleRate)
+	return &AudioManager{
+		ctx:    ctx,
+		sounds: make(map[string][]byte),
+		music:  music.NewSoundtrackManager(ctx, gameDataFS, "gamedata/music"),
+
+		newPlayer: func(pcm []byte) sfxPlayer { return ctx.NewPlayerFromBytes(pcm) },
+
+		masterVolume:   1,
+		channelVolumes: [numAudioChannels]float64{1, 1, 1},
+	}
+}
+
+// SetMasterVolume sets the volume scaling every channel, between 0 and 1.
+func (a *AudioManager) SetMasterVolume(v float64) {
+	a.masterVolume = min(max(v, 0), 1)
+	a.music.SetVolume(a.volume(ChannelMusic))
+}
+
+// SetChannelVolume sets the volume of the provided channel, between 0 and 1.
+func (a *AudioManager) SetChannelVolume(ch AudioChannel, v float64) {
+	a.channelVolumes[ch] = min(max(v, 0), 1)
+	a.music.SetVolume(a.volume(ChannelMusic))
+}
+
+// ApplySettings sets the volume of every channel from the provided settings.
+func (a *AudioManager) ApplySettings(settings *Settings) {
+	a.SetMasterVolume(settings.MasterVolume)
+	for ch, v := range settings.ChannelVolumes {
+		a.SetChannelVolume(AudioChannel(ch), v)
+	}
+}
+
+// StoreSettings records the volume of every channel in the provided settings.
+func (a *AudioManager) StoreSettings(settings *Settings) {
+	settings.MasterVolume = a.masterVolume
+	settings.ChannelVolumes = a.channelVolumes
+}
+
+// volume returns the effective volume of the provided channel.
+func (a *AudioManager) volume(ch AudioChannel) float64 {
+	return a.masterVolume * a.channelVolumes[ch]
+}
+
+// play plays the provided PCM data at the volume of the provided channel. Nothing is played if the channel is muted.
+func (a *AudioManager) play(ch AudioChannel, pcm []byte) {
+	v := a.volume(ch)
+	if v <= 0 {
+		return
+	}
+	player := a.newPlayer(pcm)
+	player.SetVolume(v)
+	player.Play()
+}
+
+// PlayMusic cross-fades from the current track to the named track; an empty name fades to silence.
+func (a *AudioManager) PlayMusic(name string) {
+	a.music.CrossFadeTo(name, MusicFade)
+}
+
+// Update advances any music cross-fade in progress. It should be called once per frame.
+func (a *AudioManager) Update() {
+	a.music.Update()
+}
+
+// PlaySFX plays the named sound effect from the beginning.
+func (a *AudioManager) PlaySFX(name string) {
+	if pcm := a.pcm(name); pcm != nil {
+		a.play(ChannelSFX, pcm)
+	}
+}
+
+// PlaySFXAt plays the named sound effect from the beginning, panned left or right by the horizontal offset of worldPos
+// from the listener. Sounds MaxAudibleRange or further away are panned fully to one side.
+func (a *AudioManager) PlaySFXAt(name string, worldPos Vec2) {
+	pcm := a.pcm(name)
+	if pcm == nil || a.volume(ChannelSFX) <= 0 {
+		return
+	}
+	left, right := panGains(panOf(a.listener, worldPos))
+	a.play(ChannelSFX, panPCM(pcm, left, right))
+}
+
+// SetListener sets the world position which PlaySFXAt pans sounds relative to.
+func (a *AudioManager) SetListener(pos Vec2) {
+	a.listener = pos
+}
+
+// panOf returns the pan of a sound played at worldPos, from -1 (left) to 1 (right), by its horizontal offset from the
+// listener.
+func panOf(listener, worldPos Vec2) float64 {
+	return min(max((worldPos.X-listener.X)/MaxAudibleRange, -1), 1)
+}
+
+// panGains returns the gain of each channel for the provided pan, from -1 (left) to 1 (right), using equal-power
+// panning so that the loudness of a sound does not dip as it moves across the listener.
+func panGains(pan float64) (left, right float64) {
+	theta := (pan + 1) * math.Pi / 4
+	return math.Cos(theta), math.Sin(theta)
+}
+
+// panPCM returns a copy of the provided 16-bit stereo PCM data downmixed to mono, then scaled by the provided gain in
+// each channel.
+func panPCM(pcm []byte, left, right float64) []byte {
+	out := make([]byte, len(pcm))
+	for i := 0; i+4 <= len(pcm); i += 4 {
+		l := int16(binary.LittleEndian.Uint16(pcm[i:]))
+		r := int16(binary.LittleEndian.Uint16(pcm[i+2:]))
+		mono := (float64(l) + float64(r)) / 2
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(mono*left)))
+		binary.LittleEndian.PutUint16(out[i+2:], uint16(int16(mono*right)))
+	}
+	return out
+}
+
+// pcm returns the decoded PCM data of the named sound effect, loading it on first use. Returns nil if the sound could
+// not be loaded.
+func (a *AudioManager) pcm(name string) []byte {
+	pcm, ok := a.sounds[name]
+	if !ok {
+		pcm = a.load(name)
+		a.sounds[name] = pcm
+	}
+	return pcm
+}
+
+// load decodes the named sound effect, returning nil if it could not be loaded.
+func (a *AudioManager) load(name string) []byte {
+	f, err := gameDataFS.Open("gamedata/sfx/" + name + ".wav")
+	if err != nil {
+		slog.Warn("could not load sound", "name", name, "err", err)
+		return nil
+	}
+	defer f.Close()
+	stream, err := wav.DecodeWithSampleRate(SampleRate, f)
+	if err != nil {
+		slog.Warn("could not decode sound", "name", name, "err", err)
+		return nil
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, stream); err != nil {
+		slog.Warn("could not decode sound", "name", name, "err", err)
+		return nil
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestPanGains(t *testing.T) {
+	listener := Vec2{X: 100, Y: 50}
+	tests := []struct {
+		name                string
+		pos                 Vec2
+		wantLeft, wantRight float64
+	}{
+		{name: "right edge", pos: Vec2{X: 100 + MaxAudibleRange}, wantLeft: 0, wantRight: 1},
+		{name: "beyond right", pos: Vec2{X: 100 + 2*MaxAudibleRange}, wantLeft: 0, wantRight: 1},
+		{name: "left edge", pos: Vec2{X: 100 - MaxAudibleRange}, wantLeft: 1, wantRight: 0},
+		{name: "centered", pos: Vec2{X: 100, Y: -200}, wantLeft: math.Sqrt2 / 2, wantRight: math.Sqrt2 / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := panGains(panOf(listener, tt.pos))
+			if math.Abs(left-tt.wantLeft) > 1e-9 || math.Abs(right-tt.wantRight) > 1e-9 {
+				t.Errorf("panGains() = (%v, %v); want (%v, %v)", left, right, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+}
+
+func TestPanPCM(t *testing.T) {
+	pcm := make([]byte, 8)
+	for i, sample := range []int16{1000, 3000, -2000, -2000} {
+		binary.LittleEndian.PutUint16(pcm[2*i:], uint16(sample))
+	}
+	out := panPCM(pcm, 0, 1)
+	want := []int16{0, 2000, 0, -2000}
+	for i, w := range want {
+		if got := int16(binary.LittleEndian.Uint16(out[2*i:])); got != w {
+			t.Errorf("panPCM() sample %d = %d; want %d", i, got, w)
+		}
+	}
+}
+
+// fakeSFXPlayer records the volume each sound effect is played at.
+type fakeSFXPlayer struct {
+	volume float64
+	played *[]float64
+}
+
+func (f *fakeSFXPlayer) SetVolume(volume float64) { f.volume = volume }
+func (f *fakeSFXPlayer) Play()                    { *f.played = append(*f.played, f.volume) }
+
+func TestMutedChannelPlaysNothing(t *testing.T) {
+	a := newTestGame(t).audio
+	master, channels, newPlayer := a.masterVolume, a.channelVolumes, a.newPlayer
+	t.Cleanup(func() {
+		a.masterVolume, a.channelVolumes, a.newPlayer = master, channels, newPlayer
+		delete(a.sounds, "test")
+	})
+	var played []float64
+	a.newPlayer = func([]byte) sfxPlayer { return &fakeSFXPlayer{played: &played} }
+	a.sounds["test"] = make([]byte, 16)
+
+	a.SetMasterVolume(0.5)
+	a.SetChannelVolume(ChannelMusic, 1)
+	a.SetChannelVolume(ChannelAmbience, 1)
+	a.SetChannelVolume(ChannelSFX, 0)
+	a.PlaySFX("test")
+	a.PlaySFXAt("test", Vec2{})
+	if len(played) != 0 {
+		t.Errorf("muted SFX channel played sounds at volumes %v; want none", played)
+	}
+	if got := a.volume(ChannelMusic); got != 0.5 {
+		t.Errorf("music volume with SFX muted = %v; want 0.5", got)
+	}
+	if got := a.volume(ChannelAmbience); got != 0.5 {
+		t.Errorf("ambience volume with SFX muted = %v; want 0.5", got)
+	}
+
+	a.SetChannelVolume(ChannelSFX, 0.8)
+	a.SetChannelVolume(ChannelMusic, 0)
+	a.PlaySFX("test")
+	if len(played) != 1 || played[0] != 0.4 {
+		t.Errorf("SFX with music muted played at volumes %v; want [0.4]", played)
+	}
+}
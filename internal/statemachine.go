@@ -0,0 +1,55 @@
+package internal
+
+import "fmt"
+
+// StateMachine runs the update handler registered for its current state each frame, transitioning to whichever state
+// the handler returns.
+type StateMachine[S comparable] struct {
+	state        S
+	prev         S                // prev is the state before the most recent transition.
+	transitions  map[S]func(S) S  // transitions holds the update handler of each state.
+	onTransition func(from, to S) // onTransition, if set, is called after every transition.
+}
+
+// NewStateMachine constructs a StateMachine starting in the provided state.
+func NewStateMachine[S comparable](initial S) StateMachine[S] {
+	return StateMachine[S]{state: initial, prev: initial, transitions: make(map[S]func(S) S)}
+}
+
+// Register sets the update handler of the provided state. Each handler is passed the state the machine was in before
+// entering its state, and returns the state to transition to; returning its own state remains in it.
+func (m *StateMachine[S]) Register(state S, update func(prev S) S) {
+	m.transitions[state] = update
+}
+
+// OnTransition sets a func to be called after every transition.
+func (m *StateMachine[S]) OnTransition(f func(from, to S)) {
+	m.onTransition = f
+}
+
+// Update calls the handler of the current state and transitions to the state it returns, then returns the new state.
+// Panics if no handler is registered for the current state.
+func (m *StateMachine[S]) Update() S {
+	update, ok := m.transitions[m.state]
+	if !ok {
+		panic(fmt.Sprintf("no handler registered for state %v", m.state))
+	}
+	m.Set(update(m.prev))
+	return m.state
+}
+
+// State returns the current state.
+func (m *StateMachine[S]) State() S {
+	return m.state
+}
+
+// Set transitions to the provided state without calling any handler. Nothing happens if the machine is already in it.
+func (m *StateMachine[S]) Set(next S) {
+	if next == m.state {
+		return
+	}
+	m.prev, m.state = m.state, next
+	if m.onTransition != nil {
+		m.onTransition(m.prev, next)
+	}
+}
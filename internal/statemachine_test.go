@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestStateMachineUpdate(t *testing.T) {
+	m := NewStateMachine("idle")
+	m.Register("idle", func(string) string { return "walking" })
+	m.Register("walking", func(string) string { return "walking" })
+	var transitions [][2]string
+	m.OnTransition(func(from, to string) { transitions = append(transitions, [2]string{from, to}) })
+
+	if got := m.Update(); got != "walking" {
+		t.Errorf("Update() = %q; want %q", got, "walking")
+	}
+	if got := m.Update(); got != "walking" {
+		t.Errorf("Update() while remaining in state = %q; want %q", got, "walking")
+	}
+	if got := m.State(); got != "walking" {
+		t.Errorf("State() = %q; want %q", got, "walking")
+	}
+	if len(transitions) != 1 || transitions[0] != [2]string{"idle", "walking"} {
+		t.Errorf("OnTransition saw %v; want one transition from idle to walking", transitions)
+	}
+}
+
+func TestStateMachinePassesPreviousState(t *testing.T) {
+	m := NewStateMachine(1)
+	var prevs []int
+	m.Register(1, func(prev int) int { prevs = append(prevs, prev); return 2 })
+	m.Register(2, func(prev int) int { prevs = append(prevs, prev); return 1 })
+	for i := 0; i < 3; i++ {
+		m.Update()
+	}
+	want := []int{1, 1, 2}
+	for i := range want {
+		if i >= len(prevs) || prevs[i] != want[i] {
+			t.Fatalf("handlers were passed previous states %v; want %v", prevs, want)
+		}
+	}
+}
+
+func TestStateMachineUnregisteredPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Update() in an unregistered state did not panic")
+		}
+	}()
+	m := NewStateMachine("unknown")
+	m.Update()
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/niftysoft/2d-platformer/internal/ai"
+	"image/color"
+	"math"
+)
+
+const (
+	SwarmDefaultCount  = 8    // SwarmDefaultCount is the number of boids in swarms without a "count" field.
+	SwarmBoidSize      = 4    // SwarmBoidSize is the width and height of each boid's hitbox in pixels.
+	SwarmMaxSpeed      = 1.5  // SwarmMaxSpeed is the fastest each boid flies in pixels per frame.
+	SwarmSeparation    = 1.5  // SwarmSeparation weights how strongly boids steer away from each other.
+	SwarmAlignment     = 0.05 // SwarmAlignment weights how strongly boids match each other's velocity.
+	SwarmCohesion      = 0.01 // SwarmCohesion weights how strongly boids steer toward each other.
+	SwarmSeekWeight    = 0.02 // SwarmSeekWeight weights how strongly boids steer toward the player.
+	SwarmWallRepulsion = 2.0  // SwarmWallRepulsion scales the force pushing boids away from the nearest solid cell.
+	SwarmWallRange     = 2    // SwarmWallRange is how many cells away a boid looks for solid cells to avoid.
+)
+
+// SwarmEnemy is a flock of small flying boids which chase the player. Its hitbox covers the whole flock; each hit
+// kills one boid, and the swarm dies once no boids remain.
+type SwarmEnemy struct {
+	Actor
+	boids []ai.Boid
+}
+
+// NewSwarmEnemy constructs a SwarmEnemy from the provided entity, spreading its boids across the entity's bounds. The
+// "count" field sets the number of boids.
+func NewSwarmEnemy(s *PlatformerScene, entity *Entity) (Enemy, error) {
+	count := entity.FieldInt("count")
+	if count <= 0 {
+		count = SwarmDefaultCount
+	}
+	bounds := entity.PxBounds()
+	center := bounds.Center().Vec2()
+	result := &SwarmEnemy{Actor: Actor{scene: s}, boids: make([]ai.Boid, count)}
+	for i := range result.boids {
+		offset := Vec2FromAngle(2*math.Pi*float64(i)/float64(count), float64(min(bounds.W, bounds.H))/2)
+		pos := center.Add(offset)
+		result.boids[i] = ai.Boid{Pos: ai.Vec2{X: pos.X, Y: pos.Y}, MaxSpeed: SwarmMaxSpeed}
+	}
+	return result, nil
+}
+
+// Update steers the flock toward the nearest player and away from solid cells, then moves each boid.
+func (e *SwarmEnemy) Update() {
+	ai.UpdateBoids(e.boids, SwarmSeparation, SwarmAlignment, SwarmCohesion)
+	player := e.scene.nearestPlayer(e.Hitbox().Center())
+	if player == nil {
+		return
+	}
+	target := player.center()
+	for i := range e.boids {
+		b := &e.boids[i]
+		seek := ai.Vec2{X: target.X - b.Pos.X, Y: target.Y - b.Pos.Y}.Scale(SwarmSeekWeight)
+		b.Vel = b.Vel.Add(seek).Add(e.wallRepulsion(b.Pos)).ClampMag(b.MaxSpeed)
+		e.moveBoid(b)
+	}
+}
+
+// wallRepulsion returns a force pushing away from the nearest solid cell within SwarmWallRange cells of pos, with
+// magnitude proportional to 1/distance.
+func (e *SwarmEnemy) wallRepulsion(pos ai.Vec2) ai.Vec2 {
+	size := float64(e.scene.cellSize)
+	cx, cy := e.scene.screenToCell(pos.X, pos.Y)
+	var nearest ai.Vec2
+	nearestDist := math.Inf(1)
+	for x := cx - SwarmWallRange; x <= cx+SwarmWallRange; x++ {
+		for y := cy - SwarmWallRange; y <= cy+SwarmWallRange; y++ {
+			if !e.scene.gridDataI(x, y).isSolid() {
+				continue
+			}
+			cellCenter := ai.Vec2{X: (float64(x) + 0.5) * size, Y: (float64(y) + 0.5) * size}
+			if dist := pos.Sub(cellCenter).Mag(); dist < nearestDist {
+				nearest, nearestDist = cellCenter, dist
+			}
+		}
+	}
+	if math.IsInf(nearestDist, 1) {
+		return ai.Vec2{}
+	}
+	dist := max(nearestDist, 1)
+	return pos.Sub(nearest).Scale(SwarmWallRepulsion / (dist * dist)) // unit vector scaled by 1/dist.
+}
+
+// moveBoid moves the provided boid by its velocity, stopping it along any axis on which it hits a solid cell.
+func (e *SwarmEnemy) moveBoid(b *ai.Boid) {
+	amt := math.Floor(b.Pos.X+b.Vel.X) - math.Floor(b.Pos.X)
+	dx, collides := e.MoveX(boidHitbox(*b), amt, ClipNone)
+	if collides.Colliding(ClipNone) {
+		b.Pos.X, b.Vel.X = math.Floor(b.Pos.X)+float64(dx), 0
+	} else {
+		b.Pos.X += b.Vel.X
+	}
+	amt = math.Floor(b.Pos.Y+b.Vel.Y) - math.Floor(b.Pos.Y)
+	dy, collides := e.MoveY(boidHitbox(*b), amt, ClipNone)
+	if collides.Colliding(ClipNone) {
+		b.Pos.Y, b.Vel.Y = math.Floor(b.Pos.Y)+float64(dy), 0
+	} else {
+		b.Pos.Y += b.Vel.Y
+	}
+}
+
+// boidHitbox returns the hitbox of the provided boid, centered on its position.
+func boidHitbox(b ai.Boid) IRect {
+	return IRect{
+		X: int(math.Floor(b.Pos.X)) - SwarmBoidSize/2,
+		Y: int(math.Floor(b.Pos.Y)) - SwarmBoidSize/2,
+		W: SwarmBoidSize,
+		H: SwarmBoidSize,
+	}
+}
+
+// Hitbox returns the smallest rectangle containing the hitbox of every boid.
+func (e *SwarmEnemy) Hitbox() IRect {
+	if len(e.boids) == 0 {
+		return IRect{}
+	}
+	first := boidHitbox(e.boids[0])
+	minX, minY, maxX, maxY := first.X, first.Y, first.X+first.W, first.Y+first.H
+	for _, b := range e.boids[1:] {
+		box := boidHitbox(b)
+		minX, minY = min(minX, box.X), min(minY, box.Y)
+		maxX, maxY = max(maxX, box.X+box.W), max(maxY, box.Y+box.H)
+	}
+	return IRect{X: minX, Y: minY, W: maxX - minX, H: maxY - minY}
+}
+
+// TakeDamage kills one boid for each point of damage, returning true once no boids remain.
+func (e *SwarmEnemy) TakeDamage(amount int) bool {
+	e.boids = e.boids[:max(len(e.boids)-amount, 0)]
+	return e.Dead()
+}
+
+// Dead returns true once every boid has been killed.
+func (e *SwarmEnemy) Dead() bool {
+	return len(e.boids) == 0
+}
+
+// Draw draws each boid as a placeholder square.
+func (e *SwarmEnemy) Draw(screen *ebiten.Image, camera IVec2) {
+	for _, b := range e.boids {
+		box := boidHitbox(b).Add(camera)
+		vector.DrawFilledRect(screen, float32(box.X), float32(box.Y), float32(box.W), float32(box.H),
+			color.RGBA{R: 0x60, G: 0x60, B: 0xe0, A: 0xff}, false)
+	}
+}
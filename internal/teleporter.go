@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"image/color"
+	"log/slog"
+)
+
+const TeleporterDelaySeconds = 0.5  // TeleporterDelaySeconds is how long the player must stand on a teleporter before it warps them.
+const TeleporterCooldownFrames = 30 // TeleporterCooldownFrames is how long the player must spend off teleporters before warping again.
+const TeleportFlashFrames = 15      // TeleportFlashFrames is the length of the flash shown after the player teleports.
+
+// Teleporter warps the player to its partner teleporter, which may be in another level.
+type Teleporter struct {
+	*Entity
+	Partner      *Entity // Partner is the teleporter the player is sent to.
+	PartnerLevel *Level  // PartnerLevel is the level containing Partner.
+}
+
+// FindEntity returns the entity with the provided IID and the level containing it, searching every level.
+func (gd *GameData) FindEntity(iid uuid.UUID) (*Level, *Entity, bool) {
+	for _, level := range gd.Levels {
+		for _, entity := range level.Entities {
+			if entity.IID == iid {
+				return level, entity, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// linkTeleporter finds the partner of the provided teleporter entity by its "partner_iid" field, preferring partners in
+// the provided level. ok is false if the partner could not be found.
+func linkTeleporter(gdat *GameData, level *Level, entity *Entity) (result Teleporter, ok bool) {
+	iid, err := uuid.Parse(entity.FieldString("partner_iid"))
+	if err != nil {
+		return Teleporter{}, false
+	}
+	result.Entity = entity
+	for _, other := range level.Entities {
+		if other.IID == iid {
+			result.Partner, result.PartnerLevel = other, level
+			return result, true
+		}
+	}
+	result.PartnerLevel, result.Partner, ok = gdat.FindEntity(iid)
+	return result, ok
+}
+
+// updateTeleporters warps each player to the partner of the teleporter they are standing on once they press up or wait
+// for TeleporterDelaySeconds. After warping, a player must spend TeleporterCooldownFrames off of every teleporter
+// before they can warp again.
+func (s *PlatformerScene) updateTeleporters() {
+	for _, p := range s.players() {
+		s.updateTeleporter(p)
+	}
+}
+
+// updateTeleporter warps the provided player if they have waited long enough on a teleporter.
+func (s *PlatformerScene) updateTeleporter(p *Player) {
+	hitbox := p.Hitbox()
+	for _, tp := range s.teleporters {
+		if !hitbox.Overlaps(tp.PxBounds()) {
+			continue
+		}
+		if !p.teleporterCooldown.Ready() {
+			return
+		}
+		p.teleporterWait++
+		if p.prevInput&InputClimbedUp > 0 || float64(p.teleporterWait) >= TeleporterDelaySeconds*TPS {
+			s.teleport(p, tp)
+		}
+		return
+	}
+	p.teleporterWait = 0
+	p.teleporterCooldown.Tick()
+}
+
+// teleport moves the provided player to the partner of the provided teleporter, transitioning to the partner's level if
+// needed. In co-op, both players are brought along when the partner is in another level.
+func (s *PlatformerScene) teleport(p *Player, tp Teleporter) {
+	p.teleporterWait = 0
+	p.teleporterCooldown.Start()
+	if tp.PartnerLevel == s.level {
+		p.SetPos(tp.Partner.PxCoords)
+		p.Vel = Vec2{}
+		s.flashFrames = TeleportFlashFrames
+		s.updateCamera()
+		return
+	}
+	wipe := NewWipeTransition(1, WipeSpeed)
+	wipe.Color = s.level.BGColor
+	s.game.PlayTransition(wipe, s.Draw, func() {
+		if err := s.LoadLevel(tp.PartnerLevel.UID); err != nil {
+			fatal("error loading level", "err", err)
+		}
+		for _, player := range s.players() {
+			player.SetPos(tp.Partner.PxCoords)
+			player.Vel = Vec2{}
+			player.teleporterCooldown.Start()
+		}
+		s.spawn = tp.Partner.PxCoords
+		if s.player2 != nil {
+			s.spawn2 = s.spawn
+		}
+		s.flashFrames = TeleportFlashFrames
+		s.updateCamera()
+	})
+}
+
+// drawTeleportFlash draws a white flash over the screen which fades out after the player teleports.
+func (s *PlatformerScene) drawTeleportFlash(screen *ebiten.Image) {
+	if s.flashFrames <= 0 {
+		return
+	}
+	alpha := uint8(255 * s.flashFrames / TeleportFlashFrames)
+	vector.DrawFilledRect(screen, 0, 0, float32(s.camera.Size.W), float32(s.camera.Size.H),
+		color.RGBA{R: alpha, G: alpha, B: alpha, A: alpha}, false) // colors are premultiplied by alpha.
+}
+
+// loadTeleporters links every teleporter in the provided level to its partner.
+func (s *PlatformerScene) loadTeleporters(level *Level, entities []*Entity) {
+	s.teleporters = s.teleporters[:0]
+	for _, entity := range entities {
+		tp, ok := linkTeleporter(s.gdat, level, entity)
+		if !ok {
+			slog.Warn("teleporter partner not found", "teleporter", entity.IID, "partner", entity.FieldString("partner_iid"))
+			continue
+		}
+		s.teleporters = append(s.teleporters, tp)
+	}
+}
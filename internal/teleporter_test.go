@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"testing"
+)
+
+// testTeleporter returns a teleporter entity linked to the provided partner IID.
+func testTeleporter(partner string) *Entity {
+	return &Entity{ID: EtyTeleporter, IID: uuid.New(), Fields: map[string]any{"partner_iid": partner}}
+}
+
+func TestLinkTeleporter(t *testing.T) {
+	here, there := &Level{UID: 1, ID: "here"}, &Level{UID: 2, ID: "there"}
+	local, remote := testTeleporter(""), testTeleporter("")
+	decoy := &Entity{ID: EtyCoin, IID: uuid.New()}
+	here.Entities = []*Entity{local, decoy}
+	there.Entities = []*Entity{remote}
+	gdat := &GameData{Levels: map[UID]*Level{here.UID: here, there.UID: there}}
+
+	tests := []struct {
+		name        string
+		partner     string
+		wantOK      bool
+		wantPartner *Entity
+		wantLevel   *Level
+	}{
+		{name: "same level", partner: local.IID.String(), wantOK: true, wantPartner: local, wantLevel: here},
+		{name: "other level", partner: remote.IID.String(), wantOK: true, wantPartner: remote, wantLevel: there},
+		{name: "any entity", partner: decoy.IID.String(), wantOK: true, wantPartner: decoy, wantLevel: here},
+		{name: "missing", partner: uuid.New().String()},
+		{name: "invalid", partner: "not-a-uuid"},
+		{name: "unset", partner: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := testTeleporter(tt.partner)
+			got, ok := linkTeleporter(gdat, here, entity)
+			if ok != tt.wantOK {
+				t.Fatalf("linkTeleporter() ok = %v; want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Entity != entity {
+				t.Errorf("linkTeleporter().Entity = %v; want %v", got.Entity, entity)
+			}
+			if got.Partner != tt.wantPartner {
+				t.Errorf("linkTeleporter().Partner = %v; want %v", got.Partner.IID, tt.wantPartner.IID)
+			}
+			if got.PartnerLevel != tt.wantLevel {
+				t.Errorf("linkTeleporter().PartnerLevel = %v; want %v", got.PartnerLevel.ID, tt.wantLevel.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package testutil provides helpers for tests which render with Ebitengine.
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// GoldenTolerance is the largest difference allowed in any channel of a pixel before it is considered a mismatch. It
+// allows for small differences between graphics drivers.
+var GoldenTolerance uint8 = 2
+
+// updateGoldenEnv is the environment variable which, when set to 1, causes AssertGoldenScreen to overwrite golden
+// images instead of comparing against them.
+const updateGoldenEnv = "UPDATE_GOLDEN"
+
+// AssertGoldenScreen compares the pixels of screen against the golden image testdata/<name>.png, failing the test if
+// any pixel differs by more than GoldenTolerance. On mismatch, an image marking each differing pixel in red is written
+// to testdata/<name>.diff.png. If UPDATE_GOLDEN=1, the golden image is written instead.
+//
+// Pixels can only be read while the game loop is running, so tests calling AssertGoldenScreen must be run with
+// RunTests.
+func AssertGoldenScreen(t *testing.T, screen *ebiten.Image, name string) {
+	t.Helper()
+	got := readScreen(screen)
+	path := filepath.Join("testdata", name+".png")
+	if os.Getenv(updateGoldenEnv) == "1" {
+		if err := writePNG(path, got); err != nil {
+			t.Fatalf("could not write golden image: %v", err)
+		}
+		return
+	}
+	want, err := readPNG(path)
+	if err != nil {
+		t.Fatalf("could not read golden image; run with %s=1 to create it: %v", updateGoldenEnv, err)
+	}
+	if want.Bounds().Size() != got.Bounds().Size() {
+		t.Fatalf("screen is %v; golden image %s is %v", got.Bounds().Size(), path, want.Bounds().Size())
+	}
+	diff, mismatched := diffImages(want, got)
+	if mismatched == 0 {
+		return
+	}
+	diffPath := filepath.Join("testdata", name+".diff.png")
+	if err := writePNG(diffPath, diff); err != nil {
+		t.Errorf("could not write diff image: %v", err)
+	}
+	t.Fatalf("%d pixels differ from golden image %s; see %s", mismatched, path, diffPath)
+}
+
+// ErrNoDisplay is returned by RunTests when there is no display to run the game loop on.
+var ErrNoDisplay = errors.New("no display available")
+
+// RunTests runs the tests of m from within the Ebitengine game loop and returns their exit code. It should be called
+// from TestMain. If no display is available, ErrNoDisplay is returned without running any tests.
+func RunTests(m *testing.M) (int, error) {
+	if !hasDisplay() {
+		return 0, ErrNoDisplay
+	}
+	g := &testGame{m: m}
+	if err := ebiten.RunGameWithOptions(g, &ebiten.RunGameOptions{InitUnfocused: true}); err != nil {
+		return 0, fmt.Errorf("could not run game loop: %w", err)
+	}
+	return g.code, nil
+}
+
+// hasDisplay returns true if a window can be opened. Only X11 and Wayland platforms can lack a display.
+func hasDisplay() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "android", "js":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// testGame is a game whose first update runs a suite of tests, then terminates.
+type testGame struct {
+	m    *testing.M
+	code int // code is the exit code of the test suite.
+}
+
+func (g *testGame) Update() error {
+	g.code = g.m.Run()
+	return ebiten.Termination
+}
+
+func (g *testGame) Draw(*ebiten.Image) {}
+
+func (g *testGame) Layout(w, h int) (int, int) {
+	return w, h
+}
+
+// readScreen copies the pixels of screen into an image.RGBA.
+func readScreen(screen *ebiten.Image) *image.RGBA {
+	result := image.NewRGBA(image.Rectangle{Max: screen.Bounds().Size()})
+	screen.ReadPixels(result.Pix)
+	return result
+}
+
+// diffImages returns an image which is a dimmed copy of want with every pixel differing from got by more than
+// GoldenTolerance in red, along with the number of differing pixels.
+func diffImages(want image.Image, got *image.RGBA) (*image.RGBA, int) {
+	bounds := got.Bounds()
+	diff := image.NewRGBA(bounds)
+	mismatched := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			w := color.RGBAModel.Convert(want.At(x+want.Bounds().Min.X, y+want.Bounds().Min.Y)).(color.RGBA)
+			g := got.RGBAAt(x, y)
+			if channelDiff(w.R, g.R) > GoldenTolerance || channelDiff(w.G, g.G) > GoldenTolerance ||
+				channelDiff(w.B, g.B) > GoldenTolerance || channelDiff(w.A, g.A) > GoldenTolerance {
+				diff.SetRGBA(x, y, color.RGBA{R: 0xff, A: 0xff})
+				mismatched++
+				continue
+			}
+			diff.SetRGBA(x, y, color.RGBA{R: w.R / 4, G: w.G / 4, B: w.B / 4, A: 0xff})
+		}
+	}
+	return diff, mismatched
+}
+
+// channelDiff returns the absolute difference between two channel values.
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// readPNG decodes the PNG image at the provided path.
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// writePNG encodes img as a PNG at the provided path, creating its directory if needed.
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
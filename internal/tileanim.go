@@ -0,0 +1,58 @@
+package internal
+
+// TileAnim cycles a single tile in a layer through a list of frames, holding each frame for frameDuration updates.
+type TileAnim struct {
+	layer         *TileLayer // layer is the layer containing the animated tile.
+	tileIdx       int        // tileIdx is the index of the animated tile in layer.Tiles.
+	frames        []Tile
+	frameDuration int
+	current       int
+	timer         int
+}
+
+// Tick advances the animation by one update, returning true if the current frame changed.
+func (a *TileAnim) Tick() bool {
+	if len(a.frames) < 2 {
+		return false
+	}
+	a.timer++
+	if a.timer < a.frameDuration {
+		return false
+	}
+	a.timer = 0
+	a.current = (a.current + 1) % len(a.frames)
+	return true
+}
+
+// Frame returns the current frame of the animation.
+func (a *TileAnim) Frame() Tile {
+	return a.frames[a.current]
+}
+
+// AddTileAnim animates the tile at index tileIdx in the provided layer. Each frame is drawn at the position of the
+// original tile, regardless of the PxCoords of the frame. Animations are removed when a new level is loaded.
+func (s *PlatformerScene) AddTileAnim(layer *TileLayer, tileIdx int, frames []Tile, frameDuration int) {
+	pos := layer.Tiles[tileIdx].PxCoords
+	for i := range frames {
+		frames[i].PxCoords = pos
+	}
+	s.animatedTiles = append(s.animatedTiles, TileAnim{
+		layer:         layer,
+		tileIdx:       tileIdx,
+		frames:        frames,
+		frameDuration: frameDuration,
+	})
+}
+
+// updateTileAnims ticks all animated tiles, marking the background dirty wherever a frame has changed.
+func (s *PlatformerScene) updateTileAnims() {
+	for i := range s.animatedTiles {
+		anim := &s.animatedTiles[i]
+		if !anim.Tick() {
+			continue
+		}
+		anim.layer.Tiles[anim.tileIdx] = anim.Frame()
+		gridSize := anim.layer.GridSize
+		s.MarkDirty(IRect{X: anim.Frame().PxCoords.X, Y: anim.Frame().PxCoords.Y, W: gridSize, H: gridSize})
+	}
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+	"testing"
+)
+
+// testTilesetUID is the UID of the tileset added by addTestTileset.
+const testTilesetUID = 1
+
+// testTileColors are the colors of each tile in the tileset added by addTestTileset, from left to right.
+var testTileColors = []color.RGBA{
+	{R: 0xff, A: 0xff},
+	{B: 0xff, A: 0xff},
+	{G: 0xff, A: 0xff},
+}
+
+// addTestTileset adds a tileset holding one solid-colored tile for each of testTileColors to the scene, along with an
+// empty layer drawn using it, which is returned.
+func addTestTileset(s *PlatformerScene) *TileLayer {
+	img := ebiten.NewImage(len(testTileColors)*testCellSize, testCellSize)
+	for i, c := range testTileColors {
+		img.SubImage(IRect{X: i * testCellSize, W: testCellSize, H: testCellSize}.Rectangle()).(*ebiten.Image).Fill(c)
+	}
+	if s.gdat.Tilesets == nil {
+		s.gdat.Tilesets = make(map[UID]*ebiten.Image)
+	}
+	s.gdat.Tilesets[testTilesetUID] = img
+	uid := int64(testTilesetUID)
+	layer := &TileLayer{ID: "Tiles", Opacity: 1, GridSize: testCellSize, CellDims: s.collisionLayer.CellDims,
+		TileSetUID: &uid}
+	s.level.layers = append(s.level.layers, layer)
+	return layer
+}
+
+// testTile returns a tile at the provided cell drawn with the tile of testTileColors at index idx.
+func testTile(cx, cy, idx int) Tile {
+	return Tile{
+		PxCoords:  IVec2{X: cx * testCellSize, Y: cy * testCellSize},
+		SrcCoords: IVec2{X: idx * testCellSize},
+		TileID:    idx,
+	}
+}
+
+// backgroundAt returns the color of the background at the provided pixel.
+func backgroundAt(s *PlatformerScene, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(s.background.At(x, y)).(color.RGBA)
+}
+
+func TestTileAnimChangesBackground(t *testing.T) {
+	const frameDuration = 5
+	s := newTestScene(t, grid(4, 4)...)
+	layer := addTestTileset(s)
+	layer.Tiles = []Tile{testTile(1, 1, 0)}
+	s.redrawTiles(IRect{W: 4 * testCellSize, H: 4 * testCellSize})
+	s.AddTileAnim(layer, 0, []Tile{testTile(0, 0, 0), testTile(0, 0, 1)}, frameDuration)
+
+	x, y := testCellSize+testCellSize/2, testCellSize+testCellSize/2
+	if got := backgroundAt(s, x, y); got != testTileColors[0] {
+		t.Fatalf("background at the tile is %v before animating; want %v", got, testTileColors[0])
+	}
+	for i := 1; i <= frameDuration; i++ {
+		s.updateTileAnims()
+		s.flushDirtyRegions()
+		got := backgroundAt(s, x, y)
+		if i < frameDuration && got != testTileColors[0] {
+			t.Fatalf("background changed to %v after %d updates; want it to hold for %d", got, i, frameDuration)
+		}
+		if i == frameDuration && got != testTileColors[1] {
+			t.Errorf("background is %v after %d updates; want %v", got, i, testTileColors[1])
+		}
+	}
+}
+
+func TestRedrawRegionDrawsOnlyOverlappingTiles(t *testing.T) {
+	s := newTestScene(t, grid(4, 4)...)
+	layer := addTestTileset(s)
+	layer.Opacity = 0.5 // a tile drawn twice would be more opaque than one drawn once.
+	for cy := 0; cy < 3; cy++ {
+		for cx := 0; cx < 3; cx++ {
+			layer.Tiles = append(layer.Tiles, testTile(cx, cy, 0))
+		}
+	}
+	s.background.Clear()
+	s.RedrawRegion(layer, IRect{X: testCellSize, Y: testCellSize, W: testCellSize, H: testCellSize})
+
+	once := ebiten.NewImage(1, 1)
+	opts := ebiten.DrawImageOptions{}
+	opts.ColorScale.SetA(layer.Opacity)
+	src := ebiten.NewImage(1, 1)
+	src.Fill(testTileColors[0])
+	once.DrawImage(src, &opts)
+	want := color.RGBAModel.Convert(once.At(0, 0)).(color.RGBA)
+
+	half := testCellSize / 2
+	if got := backgroundAt(s, testCellSize+half, testCellSize+half); got != want {
+		t.Errorf("background in the region is %v; want %v, the tile drawn once", got, want)
+	}
+	for _, pt := range []IVec2{{X: half, Y: half}, {X: 2*testCellSize + half, Y: testCellSize + half}} {
+		if got := backgroundAt(s, pt.X, pt.Y); got != (color.RGBA{}) {
+			t.Errorf("background outside the region at %v is %v; want it left clear", pt, got)
+		}
+	}
+}
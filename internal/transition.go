@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image"
+	"image/color"
+)
+
+const WipeSpeed = 1.0 / 20 // WipeSpeed is the fraction of the screen covered by a wipe each frame.
+const wipeEpsilon = 1e-9   // wipeEpsilon tolerates floating point error when repeatedly adding speed to progress.
+
+// A Transition is a Scene which hides the scene beneath it, calls onSwitch once it is fully hidden, and then reveals the
+// scene again before popping itself.
+type Transition interface {
+	Scene
+	// Start prepares this transition to be pushed onto the provided game. drawBelow draws the scene being transitioned.
+	Start(g *Game, drawBelow func(*ebiten.Image), onSwitch func())
+}
+
+// PlayTransition starts the provided transition over the current scene.
+func (g *Game) PlayTransition(t Transition, drawBelow func(*ebiten.Image), onSwitch func()) {
+	t.Start(g, drawBelow, onSwitch)
+	g.PushScene(t)
+}
+
+// WipeTransition sweeps a solid color horizontally across the screen to hide the scene, then continues sweeping in the
+// same direction to reveal it.
+type WipeTransition struct {
+	*BaseScene
+	direction int     // direction is 1 to sweep rightwards, or -1 to sweep leftwards.
+	progress  float64 // progress is the fraction of the screen covered by the wipe.
+	speed     float64 // speed is the change in progress each frame.
+	revealing bool    // revealing is set once the scene has switched and the wipe is uncovering the screen.
+
+	Color color.RGBA // Color is the color of the wipe.
+
+	drawBelow func(*ebiten.Image)
+	onSwitch  func()
+	cover     *ebiten.Image
+}
+
+// NewWipeTransition constructs a new WipeTransition sweeping in the direction given by the sign of dir, covering speed
+// of the screen each frame.
+func NewWipeTransition(dir int, speed float64) *WipeTransition {
+	if dir == 0 {
+		dir = 1
+	}
+	return &WipeTransition{direction: sign(dir), speed: speed, Color: color.RGBA{A: 0xff}}
+}
+
+// Start prepares this transition to be pushed onto the provided game.
+func (t *WipeTransition) Start(g *Game, drawBelow func(*ebiten.Image), onSwitch func()) {
+	t.BaseScene = NewBaseScene(g)
+	t.drawBelow, t.onSwitch = drawBelow, onSwitch
+}
+
+// Update advances the wipe, switching scenes once the screen is covered and popping itself once it is uncovered.
+func (t *WipeTransition) Update() error {
+	if !t.revealing {
+		t.progress = min(t.progress+t.speed, 1)
+		if t.progress >= 1-wipeEpsilon {
+			t.progress = 1
+			t.revealing = true
+			if t.onSwitch != nil {
+				t.onSwitch()
+			}
+		}
+		return nil
+	}
+	t.progress = max(t.progress-t.speed, 0)
+	if t.progress <= wipeEpsilon {
+		t.game.PopScene()
+	}
+	return nil
+}
+
+// Draw draws the scene beneath, then the portion of the screen covered by the wipe.
+func (t *WipeTransition) Draw(screen *ebiten.Image) {
+	if t.drawBelow != nil {
+		t.drawBelow(screen)
+	}
+	w, h := t.Layout(0, 0)
+	if t.cover == nil {
+		t.cover = ebiten.NewImage(w, h)
+		t.cover.Fill(t.Color)
+	}
+	covered := int(t.progress * float64(w))
+	if covered <= 0 {
+		return
+	}
+	leading := t.direction > 0 != t.revealing // the covered region is at the left edge when true.
+	x := 0
+	if !leading {
+		x = w - covered
+	}
+	opts := ebiten.DrawImageOptions{}
+	opts.GeoM.Translate(float64(x), 0)
+	screen.DrawImage(t.cover.SubImage(image.Rect(0, 0, covered, h)).(*ebiten.Image), &opts)
+}
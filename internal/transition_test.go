@@ -0,0 +1,37 @@
+package internal
+
+import "testing"
+
+func TestWipeTransitionTiming(t *testing.T) {
+	g := newTestGame(t)
+	for _, speed := range []float64{WipeSpeed, 1.0 / 8, 0.25, 1.0 / 3, 0.1} {
+		frames := int(1/speed + wipeEpsilon)
+		depth := len(g.scenes)
+		switches := 0
+		wipe := NewWipeTransition(-1, speed)
+		g.PlayTransition(wipe, nil, func() { switches++ })
+
+		for i := 1; i <= frames; i++ {
+			if err := wipe.Update(); err != nil {
+				t.Fatalf("wipe.Update() returned error: %v", err)
+			}
+			if i < frames && (wipe.progress >= 1 || switches != 0) {
+				t.Fatalf("speed %v: progress = %v after %d updates; want it to reach 1 after %d", speed,
+					wipe.progress, i, frames)
+			}
+		}
+		if wipe.progress != 1 || switches != 1 {
+			t.Errorf("speed %v: progress = %v and %d switches after %d updates; want 1 and 1", speed, wipe.progress,
+				switches, frames)
+		}
+		for i := 0; i < frames; i++ {
+			if err := wipe.Update(); err != nil {
+				t.Fatalf("wipe.Update() returned error: %v", err)
+			}
+		}
+		if len(g.scenes) != depth {
+			t.Errorf("speed %v: %d scenes after revealing; want the wipe popped, leaving %d", speed, len(g.scenes),
+				depth)
+		}
+	}
+}
@@ -4,17 +4,14 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/colorm"
 	"image/color"
-	"log"
+	"log/slog"
+	"strings"
 	"time"
 )
 
 // N.B.: do NOT create a util package; create a util file and leave common helpers there.
 // See https://www.adam-bien.com/roller/abien/entry/util_packages_are_evil for more details
 
-type float interface {
-	~float64 | ~float32
-}
-
 type number interface {
 	~float64 | ~int
 }
@@ -33,18 +30,85 @@ func min[T number](a, b T) T {
 	return b
 }
 
-func orZero[T float](x T) T {
-	if -1e-2 < x && x < 1e-2 {
+func abs[T number](a T) T {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func sign[T number](a T) T {
+	switch {
+	case a < 0:
+		return -1
+	case a > 0:
+		return 1
+	}
+	return 0
+}
+
+// SnapThreshold is the default threshold used by SnapToZero.
+const SnapThreshold = 1e-2
+
+// SnapToZero returns zero if v lies strictly within threshold of zero, otherwise v is returned unchanged. If threshold
+// is not positive, SnapThreshold is used. Friction is applied multiplicatively, so without snapping velocities would
+// decay forever, leaving actors drifting by sub-pixel amounts long after they appear to have stopped.
+func SnapToZero(v, threshold float64) float64 {
+	if threshold <= 0 {
+		threshold = SnapThreshold
+	}
+	if -threshold < v && v < threshold {
 		return 0
 	}
-	return x
+	return v
 }
 
 func timeit(operation string, f func()) {
+	node := TimeitContext(operation, nil, f)
+	slog.Info("completed "+operation, "duration", node.Duration.Round(1*time.Microsecond))
+}
+
+// TimingNode records how long an operation took, along with any operations timed within it.
+type TimingNode struct {
+	Label    string
+	Duration time.Duration
+	Children []*TimingNode
+}
+
+// TimeitContext times f, returning the result as a node which is added to the children of parent. parent may be nil
+// for the root of a timing tree.
+func TimeitContext(label string, parent *TimingNode, f func()) *TimingNode {
+	result := &TimingNode{Label: label}
 	start := time.Now()
 	f()
-	duration := time.Now().Sub(start)
-	log.Printf("completed %s in %s\n", operation, duration.Round(1*time.Microsecond))
+	result.Duration = time.Since(start)
+	if parent != nil {
+		parent.Children = append(parent.Children, result)
+	}
+	return result
+}
+
+// Time times f as a child of this node, returning any error from f.
+func (n *TimingNode) Time(label string, f func() error) error {
+	var err error
+	TimeitContext(label, n, func() { err = f() })
+	return err
+}
+
+// String formats the children of this node as a comma-separated list of labels and durations, e.g.
+// "loadBackground: 45ms, loadCells: 2ms". The children of each child follow it in parentheses.
+func (n *TimingNode) String() string {
+	var sb strings.Builder
+	for i, child := range n.Children {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(child.Label + ": " + child.Duration.Round(time.Microsecond).String())
+		if len(child.Children) > 0 {
+			sb.WriteString(" (" + child.String() + ")")
+		}
+	}
+	return sb.String()
 }
 
 func placeholderImage(w, h int, baseColor color.Color) *ebiten.Image {
@@ -64,3 +128,13 @@ func placeholderImage(w, h int, baseColor color.Color) *ebiten.Image {
 	}
 	return result
 }
+
+// fadeRGBA returns c with its opacity scaled by alpha. Since color.RGBA is premultiplied, every channel is scaled.
+func fadeRGBA(c color.RGBA, alpha float32) color.RGBA {
+	return color.RGBA{
+		R: uint8(float32(c.R) * alpha),
+		G: uint8(float32(c.G) * alpha),
+		B: uint8(float32(c.B) * alpha),
+		A: uint8(float32(c.A) * alpha),
+	}
+}
@@ -0,0 +1,25 @@
+package internal
+
+import "testing"
+
+func TestSnapToZero(t *testing.T) {
+	tests := []struct {
+		name         string
+		v, threshold float64
+		want         float64
+	}{
+		{name: "zero", v: 0, threshold: 0, want: 0},
+		{name: "just above zero", v: 1e-3, threshold: 0, want: 0},
+		{name: "just below zero", v: -1e-3, threshold: 0, want: 0},
+		{name: "at threshold", v: SnapThreshold, threshold: 0, want: SnapThreshold},
+		{name: "at negative threshold", v: -SnapThreshold, threshold: 0, want: -SnapThreshold},
+		{name: "above threshold", v: 0.5, threshold: 0, want: 0.5},
+		{name: "custom threshold", v: 0.5, threshold: 1, want: 0},
+		{name: "negative threshold uses default", v: 1e-3, threshold: -1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := SnapToZero(tt.v, tt.threshold); got != tt.want {
+			t.Errorf("%s: SnapToZero(%v, %v) = %v; want %v", tt.name, tt.v, tt.threshold, got, tt.want)
+		}
+	}
+}
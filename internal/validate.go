@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// staticEntityIDs is the set of entity types loaded directly by loadEntities. Enemies are loaded by their registered
+// EntityFactory instead.
+var staticEntityIDs = map[EntityID]bool{
+	EtyPlayer:            true,
+	EtyPlayer2:           true,
+	EtyCoin:              true,
+	EtyKey:               true,
+	EtyPowerUpDoubleJump: true,
+	EtyExit:              true,
+	EtyDialogue:          true,
+	EtySliderDoor:        true,
+	EtySwitch:            true,
+	EtyWind:              true,
+	EtyWater:             true,
+	EtyDarkness:          true,
+	EtyTorch:             true,
+	EtyLight:             true,
+	EtyCamera:            true,
+	EtyZipLine:           true,
+	EtyRopeAnchor:        true,
+	EtyEscalator:         true,
+	EtyCrate:             true,
+	EtyTeleporter:        true,
+}
+
+// ValidateGameData checks the provided game data for references which cannot be resolved, returning one error for each
+// problem found. Entities are known if they are loaded directly by the scene or have a factory in factories. Levels are
+// checked in UID order.
+func ValidateGameData(gdat *GameData, factories map[EntityID]EntityFactory) []error {
+	var errs []error
+	if gdat.LevelStart == -1 {
+		errs = append(errs, fmt.Errorf("no player start found"))
+	}
+	uids := make([]UID, 0, len(gdat.Levels))
+	for uid := range gdat.Levels {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	for _, uid := range uids {
+		level := gdat.Levels[uid]
+		collisionLayers := 0
+		for _, layer := range level.layers {
+			if layer.ID == CollisionLayerID {
+				collisionLayers++
+			}
+			if layer.TileSetUID == nil {
+				continue
+			}
+			if _, ok := gdat.Tilesets[*layer.TileSetUID]; !ok {
+				errs = append(errs, fmt.Errorf("level '%s': layer '%s' refers to unknown tileset UID: %d",
+					level.ID, layer.ID, *layer.TileSetUID))
+			}
+		}
+		if collisionLayers != 1 {
+			errs = append(errs, fmt.Errorf("level '%s': expected exactly one '%s' layer; found %d",
+				level.ID, CollisionLayerID, collisionLayers))
+		}
+		for _, entity := range level.Entities {
+			if _, ok := factories[entity.ID]; !ok && !staticEntityIDs[entity.ID] {
+				errs = append(errs, fmt.Errorf("level '%s': unknown entity type '%s'", level.ID, entity.ID))
+			}
+			if entity.ID == EtyTeleporter {
+				if _, ok := linkTeleporter(gdat, level, entity); !ok {
+					errs = append(errs, fmt.Errorf("level '%s': teleporter %s refers to unknown partner: '%s'",
+						level.ID, entity.IID, entity.FieldString("partner_iid")))
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+)
+
+// validTestLevel returns a level which passes ValidateGameData, holding the provided entities.
+func validTestLevel(id string, entities ...*Entity) *Level {
+	collisions := &TileLayer{ID: CollisionLayerID}
+	return &Level{
+		ID:         id,
+		layers:     []*TileLayer{collisions},
+		layersByID: map[string]*TileLayer{CollisionLayerID: collisions},
+		Entities:   entities,
+	}
+}
+
+func TestValidateGameData(t *testing.T) {
+	missingTileset := int64(42)
+	tests := []struct {
+		name   string
+		gdat   GameData
+		errors []string // errors holds a substring of each error expected, in order.
+	}{
+		{
+			name: "valid",
+			gdat: GameData{Levels: map[UID]*Level{
+				1: validTestLevel("Valid", &Entity{ID: EtyPlayer}, &Entity{ID: EtyCoin}, &Entity{ID: EtyPatrolEnemy}),
+			}},
+		},
+		{
+			name:   "no start",
+			gdat:   GameData{LevelStart: -1, Levels: map[UID]*Level{1: validTestLevel("Start")}},
+			errors: []string{"no player start"},
+		},
+		{
+			name: "unknown tileset",
+			gdat: GameData{Levels: map[UID]*Level{1: {
+				ID:     "Tileset",
+				layers: []*TileLayer{{ID: CollisionLayerID}, {ID: "Tiles", TileSetUID: &missingTileset}},
+			}}},
+			errors: []string{"unknown tileset UID: 42"},
+		},
+		{
+			name: "collision layers",
+			gdat: GameData{Levels: map[UID]*Level{
+				1: {ID: "None"},
+				2: {ID: "Two", layers: []*TileLayer{{ID: CollisionLayerID}, {ID: CollisionLayerID}}},
+			}},
+			errors: []string{"'None': expected exactly one 'Collisions' layer; found 0", "'Two': expected exactly one"},
+		},
+		{
+			name:   "unknown entity",
+			gdat:   GameData{Levels: map[UID]*Level{1: validTestLevel("Entity", &Entity{ID: "Dragon"})}},
+			errors: []string{"unknown entity type 'Dragon'"},
+		},
+		{
+			name: "unknown teleporter partner",
+			gdat: GameData{Levels: map[UID]*Level{1: validTestLevel("Teleporter", &Entity{
+				ID:     EtyTeleporter,
+				IID:    uuid.New(),
+				Fields: map[string]any{"partner_iid": uuid.New().String()},
+			})}},
+			errors: []string{"refers to unknown partner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateGameData(&tt.gdat, enemyFactories)
+			if len(errs) != len(tt.errors) {
+				t.Fatalf("ValidateGameData() = %v; want %d errors", errs, len(tt.errors))
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tt.errors[i]) {
+					t.Errorf("error %d = %q; want it to contain %q", i, err, tt.errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateGameDataUsesFactories(t *testing.T) {
+	gdat := GameData{Levels: map[UID]*Level{1: validTestLevel("Custom", &Entity{ID: "Dragon"})}}
+	factories := map[EntityID]EntityFactory{"Dragon": NewPatrolEnemy}
+	if errs := ValidateGameData(&gdat, factories); len(errs) != 0 {
+		t.Errorf("ValidateGameData() = %v with a factory for the entity; want no errors", errs)
+	}
+	gdat.Levels[1].Entities[0].ID = EtyPatrolEnemy
+	if errs := ValidateGameData(&gdat, factories); len(errs) != 1 {
+		t.Errorf("ValidateGameData() = %v without a factory for the enemy; want 1 error", errs)
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	DefaultWaterAmplitude = 0.003 // DefaultWaterAmplitude is used for water zones without an "amplitude" field.
+	DefaultWaterFrequency = 20    // DefaultWaterFrequency is used for water zones without a "frequency" field.
+)
+
+// WaterZone ripples the screen while the player is inside its bounds.
+type WaterZone struct {
+	Bounds    IRect
+	Amplitude float64 // Amplitude is the horizontal size of each ripple, as a fraction of the screen width.
+	Frequency float64 // Frequency is the number of radians each ripple advances per screen height.
+}
+
+// NewWaterZone constructs a WaterZone from the "amplitude" and "frequency" fields of the provided entity.
+func NewWaterZone(entity *Entity) WaterZone {
+	result := WaterZone{
+		Bounds:    entity.PxBounds(),
+		Amplitude: entity.FieldFloat("amplitude"),
+		Frequency: entity.FieldFloat("frequency"),
+	}
+	if result.Amplitude == 0 {
+		result.Amplitude = DefaultWaterAmplitude
+	}
+	if result.Frequency == 0 {
+		result.Frequency = DefaultWaterFrequency
+	}
+	return result
+}
+
+// updateWater finds the water zone containing the center of the player's hitbox, if any.
+func (s *PlatformerScene) updateWater() {
+	center := s.player.Hitbox().Center()
+	s.activeWater = nil
+	for i := range s.waterZones {
+		if s.waterZones[i].Bounds.Contains(center) {
+			s.activeWater = &s.waterZones[i]
+			return
+		}
+	}
+}
+
+// PostProcess draws the screen through the water shader while the player is underwater.
+func (s *PlatformerScene) PostProcess() (*ebiten.Shader, map[string]any) {
+	if s.activeWater == nil {
+		return nil, nil
+	}
+	return s.game.water, map[string]any{
+		"Amplitude": float32(s.activeWater.Amplitude),
+		"Frequency": float32(s.activeWater.Frequency),
+	}
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/colornames"
+	"math"
+)
+
+const WindMaxSpeed = 6     // WindMaxSpeed is the fastest wind is able to push an actor in any direction.
+const windArrowScale = 0.5 // windArrowScale converts wind force into the length of the debug arrow in pixels.
+const windArrowPeriod = 30 // windArrowPeriod is the number of frames it takes the debug arrow to cycle once.
+
+// WindZone accelerates any actors inside its bounds.
+type WindZone struct {
+	Bounds IRect
+	Force  Vec2 // Force is the acceleration applied by this zone in pixels per second^2.
+	// TerminalVelocity overrides the max Y velocity of players falling inside this zone, such as an updraft or a
+	// low-gravity area. Zero leaves PlayerTerminalVelocity in effect.
+	TerminalVelocity float64
+}
+
+// NewWindZone constructs a WindZone from the "force_x", "force_y", and "terminal_velocity" fields of the provided
+// entity.
+func NewWindZone(entity *Entity) WindZone {
+	return WindZone{
+		Bounds:           entity.PxBounds(),
+		Force:            Vec2{X: entity.FieldFloat("force_x"), Y: entity.FieldFloat("force_y")},
+		TerminalVelocity: entity.FieldFloat("terminal_velocity"),
+	}
+}
+
+// Apply returns vel after one frame of acceleration by this zone. Wind never pushes an actor beyond WindMaxSpeed, but
+// does not slow actors which are already moving faster than WindMaxSpeed.
+func (w WindZone) Apply(vel Vec2) Vec2 {
+	return Vec2{X: windAccel(vel.X, w.Force.X/TPS), Y: windAccel(vel.Y, w.Force.Y/TPS)}
+}
+
+// windAccel accelerates v by accel, clamping the result to WindMaxSpeed in the direction of accel.
+func windAccel(v, accel float64) float64 {
+	if accel > 0 {
+		return max(v, min(v+accel, WindMaxSpeed))
+	}
+	if accel < 0 {
+		return min(v, max(v+accel, -WindMaxSpeed))
+	}
+	return v
+}
+
+// updateWind accelerates each player while they are inside any wind zones. A player inside zones which override the
+// terminal velocity falls no faster than the lowest override; the override is reset once they leave.
+func (s *PlatformerScene) updateWind() {
+	for _, p := range s.players() {
+		hitbox := p.Hitbox()
+		terminal := 0.0
+		for _, zone := range s.windZones {
+			if !hitbox.Overlaps(zone.Bounds) {
+				continue
+			}
+			p.Vel = zone.Apply(p.Vel)
+			if zone.TerminalVelocity > 0 && (terminal == 0 || zone.TerminalVelocity < terminal) {
+				terminal = zone.TerminalVelocity
+			}
+		}
+		if terminal == 0 {
+			p.ResetTerminalVelocity()
+			continue
+		}
+		p.SetTerminalVelocity(terminal)
+		p.Vel.Y = min(p.Vel.Y, terminal)
+	}
+}
+
+// drawWindDebug draws an arrow in each wind zone showing the direction and magnitude of its force. The arrow drifts in
+// the direction of the wind.
+func (s *PlatformerScene) drawWindDebug(screen *ebiten.Image) {
+	t := float64(s.frame%windArrowPeriod) / windArrowPeriod
+	for _, zone := range s.windZones {
+		if zone.Force.Mag() == 0 {
+			continue
+		}
+		arrow := zone.Force.Scale(windArrowScale)
+		dir := zone.Force.Normalize()
+		center := zone.Bounds.Add(s.camera.Offset().IVec2()).Center().Vec2()
+		start := center.Sub(arrow.Scale(0.5)).Add(dir.Scale(4 * t))
+		end := start.Add(arrow)
+		head := dir.Angle() + math.Pi
+		left, right := end.Add(Vec2FromAngle(head-math.Pi/6, 4)), end.Add(Vec2FromAngle(head+math.Pi/6, 4))
+		for _, seg := range [][2]Vec2{{start, end}, {end, left}, {end, right}} {
+			vector.StrokeLine(screen, float32(seg[0].X), float32(seg[0].Y), float32(seg[1].X), float32(seg[1].Y), 1,
+				colornames.Lightskyblue, false)
+		}
+	}
+}
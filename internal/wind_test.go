@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWindZoneApply(t *testing.T) {
+	newTestGame(t)
+	zone := WindZone{Force: Vec2{X: TPS}}
+	var vel Vec2
+	for i := 1; i <= 3; i++ {
+		vel = zone.Apply(vel)
+		if math.Abs(vel.X-float64(i)) > vecTolerance {
+			t.Fatalf("vel.X = %v after %d frames of wind; want %d", vel.X, i, i)
+		}
+	}
+	vel = Vec2{X: WindMaxSpeed - 0.5}
+	if got := zone.Apply(vel); got.X != WindMaxSpeed {
+		t.Errorf("zone.Apply(%v) = %v; want X clamped to %v", vel, got, WindMaxSpeed)
+	}
+	vel = Vec2{X: WindMaxSpeed + 2}
+	if got := zone.Apply(vel); got != vel {
+		t.Errorf("zone.Apply(%v) = %v; want %v", vel, got, vel)
+	}
+}
+
+func TestUpdateWindPushesPlayer(t *testing.T) {
+	s := newTestScene(t, grid(20, 8)...)
+	p := newTestPlayer(t, s, IVec2{X: 64, Y: 32})
+	s.windZones = []WindZone{{Bounds: p.Hitbox(), Force: Vec2{X: TPS}}}
+	p.Vel = Vec2{}
+	s.updateWind()
+	if math.Abs(p.Vel.X-1) > vecTolerance {
+		t.Errorf("player Vel.X = %v after one frame of wind; want 1", p.Vel.X)
+	}
+	s.windZones[0].Bounds = s.windZones[0].Bounds.Add(IVec2{X: 100})
+	s.updateWind()
+	if math.Abs(p.Vel.X-1) > vecTolerance {
+		t.Errorf("player Vel.X = %v after leaving the wind; want 1", p.Vel.X)
+	}
+}
+
+func TestWindZoneTerminalVelocity(t *testing.T) {
+	const terminal = 2
+	s := newTestScene(t, grid(8, 60)...)
+	p := newTestPlayer(t, s, IVec2{X: 48, Y: 16})
+	s.windZones = []WindZone{{Bounds: IRect{W: 8 * testCellSize, H: 60 * testCellSize}, TerminalVelocity: terminal}}
+	for i := 0; i < 120; i++ {
+		s.updateWind()
+		updatePlayer(p, InputNone)
+		if p.Vel.Y > terminal {
+			t.Fatalf("player Vel.Y = %v on frame %d inside the zone; want <= %v", p.Vel.Y, i, terminal)
+		}
+	}
+	if p.Vel.Y != terminal {
+		t.Errorf("player Vel.Y = %v after falling inside the zone; want %v", p.Vel.Y, terminal)
+	}
+
+	s.windZones = nil
+	for i := 0; i < 60; i++ {
+		s.updateWind()
+		updatePlayer(p, InputNone)
+	}
+	if p.Vel.Y <= terminal {
+		t.Errorf("player Vel.Y = %v after falling outside the zone; want > %v", p.Vel.Y, terminal)
+	}
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"sort"
+)
+
+// buildWorldIndex indexes every level by each world cell it covers, so that levels can be found by world position.
+func (gd *GameData) buildWorldIndex(cellSize int) {
+	gd.worldCellSize = max(cellSize, 1)
+	gd.worldLevelIndex = make(map[IVec2]*Level)
+	for _, level := range gd.Levels {
+		r := level.WorldRect()
+		lo, hi := gd.worldCell(r.IVec2()), gd.worldCell(IVec2{X: r.X + r.W - 1, Y: r.Y + r.H - 1})
+		for cx := lo.X; cx <= hi.X; cx++ {
+			for cy := lo.Y; cy <= hi.Y; cy++ {
+				if _, ok := gd.worldLevelIndex[IVec2{X: cx, Y: cy}]; !ok {
+					gd.worldLevelIndex[IVec2{X: cx, Y: cy}] = level
+				}
+			}
+		}
+	}
+}
+
+// worldCell returns the coordinates of the world cell containing the provided point.
+func (gd *GameData) worldCell(worldPt IVec2) IVec2 {
+	return IVec2{X: floorDiv(worldPt.X, gd.worldCellSize), Y: floorDiv(worldPt.Y, gd.worldCellSize)}
+}
+
+// LevelAt finds the level containing the provided point in world coordinates.
+func (gd *GameData) LevelAt(worldPt IVec2) (*Level, bool) {
+	if level, ok := gd.worldLevelIndex[gd.worldCell(worldPt)]; ok && level.WorldRect().Contains(worldPt) {
+		return level, true
+	}
+	// levels which are not aligned to world cells may share a cell with another level; fallback to a full search.
+	for _, level := range gd.Levels {
+		if level.WorldRect().Contains(worldPt) {
+			return level, true
+		}
+	}
+	return nil, false
+}
+
+// AdjacentLevel finds a level touching the edge of current in the provided cardinal direction. If several levels touch
+// the same edge, the level with the lowest UID is returned.
+func (gd *GameData) AdjacentLevel(current *Level, dir IVec2) (*Level, bool) {
+	var result *Level
+	r := current.WorldRect()
+	for _, level := range gd.Levels {
+		if level == current || !touches(r, level.WorldRect(), dir) {
+			continue
+		}
+		if result == nil || level.UID < result.UID {
+			result = level
+		}
+	}
+	return result, result != nil
+}
+
+// SortedLevels returns every level in world order: top-to-bottom, then left-to-right. Levels at the same world
+// coordinates are ordered by UID.
+func (gd *GameData) SortedLevels() []*Level {
+	result := make([]*Level, 0, len(gd.LevelsByID))
+	for _, level := range gd.LevelsByID {
+		result = append(result, level)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.WorldCoords.Y != b.WorldCoords.Y {
+			return a.WorldCoords.Y < b.WorldCoords.Y
+		}
+		if a.WorldCoords.X != b.WorldCoords.X {
+			return a.WorldCoords.X < b.WorldCoords.X
+		}
+		return a.UID < b.UID
+	})
+	return result
+}
+
+// touches returns true if other shares the edge of r in the provided cardinal direction.
+func touches(r, other IRect, dir IVec2) bool {
+	overlapsX := r.X < other.X+other.W && other.X < r.X+r.W
+	overlapsY := r.Y < other.Y+other.H && other.Y < r.Y+r.H
+	switch dir.CardinalDir() {
+	case IVec2{X: 1}:
+		return other.X == r.X+r.W && overlapsY
+	case IVec2{X: -1}:
+		return other.X+other.W == r.X && overlapsY
+	case IVec2{Y: 1}:
+		return other.Y == r.Y+r.H && overlapsX
+	case IVec2{Y: -1}:
+		return other.Y+other.H == r.Y && overlapsX
+	}
+	return false
+}
+
+// floorDiv divides a by b, rounding toward negative infinity.
+func floorDiv(a, b int) int {
+	if (a < 0) != (b < 0) && a%b != 0 {
+		return a/b - 1
+	}
+	return a / b
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestSortedLevels(t *testing.T) {
+	levels := []*Level{
+		{UID: 1, ID: "Lower_Right", WorldCoords: IVec2{X: 256, Y: 256}},
+		{UID: 2, ID: "Upper_Right", WorldCoords: IVec2{X: 256, Y: 0}},
+		{UID: 3, ID: "Lower_Left", WorldCoords: IVec2{X: -256, Y: 256}},
+		{UID: 4, ID: "Upper_Left", WorldCoords: IVec2{X: 0, Y: 0}},
+	}
+	gd := &GameData{LevelsByID: make(map[string]*Level, len(levels))}
+	for _, level := range levels {
+		gd.LevelsByID[level.ID] = level
+	}
+	want := []string{"Upper_Left", "Upper_Right", "Lower_Left", "Lower_Right"}
+	got := gd.SortedLevels()
+	if len(got) != len(want) {
+		t.Fatalf("SortedLevels() returned %d levels; want %d", len(got), len(want))
+	}
+	for i, level := range got {
+		if level.ID != want[i] {
+			t.Errorf("SortedLevels()[%d] = %s; want %s", i, level.ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
+)
+
+const ZipLineSpeed = 4       // ZipLineSpeed is the distance in pixels the player slides along a zip line each frame.
+const zipLineTileSpacing = 4 // zipLineTileSpacing is the distance in pixels between each tile drawn along a zip line.
+
+var zipLineColor = color.RGBA{R: 0xc0, G: 0xc0, B: 0xc0, A: 0xff}
+
+// ZipLine is a straight line the player slides along from Start to End after grabbing it at its start anchor.
+type ZipLine struct {
+	Start, End Vec2  // Start and End are the ends of the line in world coordinates.
+	Anchor     IRect // Anchor is the area the player must overlap to grab the line.
+}
+
+// NewZipLine constructs a ZipLine from the "start" and "end" point fields of the provided entity. Each end of the line
+// lies at the center of the cell named by its field.
+func NewZipLine(entity *Entity, cellSize int) ZipLine {
+	start, _ := entity.FieldPoint("start")
+	end, _ := entity.FieldPoint("end")
+	cellCenter := func(cell IVec2) Vec2 {
+		return Vec2{X: (float64(cell.X) + 0.5) * float64(cellSize), Y: (float64(cell.Y) + 0.5) * float64(cellSize)}
+	}
+	return ZipLine{
+		Start:  cellCenter(start),
+		End:    cellCenter(end),
+		Anchor: IRect{X: start.X * cellSize, Y: start.Y * cellSize, W: cellSize, H: cellSize},
+	}
+}
+
+// Length returns the length of this zip line in pixels.
+func (z ZipLine) Length() float64 {
+	return z.End.Sub(z.Start).Mag()
+}
+
+// PointAt returns the point dist pixels along this zip line from its start.
+func (z ZipLine) PointAt(dist float64) Vec2 {
+	length := z.Length()
+	if length == 0 {
+		return z.Start
+	}
+	return z.Start.Lerp(z.End, min(dist/length, 1))
+}
+
+// zipLineAt returns the zip line whose start anchor overlaps the provided hitbox, if any.
+func (s *PlatformerScene) zipLineAt(hitbox IRect) (ZipLine, bool) {
+	for _, line := range s.zipLines {
+		if hitbox.Overlaps(line.Anchor) {
+			return line, true
+		}
+	}
+	return ZipLine{}, false
+}
+
+// drawZipLines draws each zip line as a series of small tiles.
+func (s *PlatformerScene) drawZipLines(screen *ebiten.Image) {
+	if len(s.zipLines) == 0 {
+		return
+	}
+	if s.zipLineTile == nil {
+		s.zipLineTile = ebiten.NewImage(2, 2)
+		s.zipLineTile.Fill(zipLineColor)
+	}
+	offset := s.camera.Offset().Sub(Vec2{X: 1, Y: 1}) // center each tile on the line.
+	for _, line := range s.zipLines {
+		for d := 0.0; d <= line.Length(); d += zipLineTileSpacing {
+			pt := line.PointAt(d).Add(offset)
+			opts := ebiten.DrawImageOptions{}
+			opts.GeoM.Translate(pt.X, pt.Y)
+			screen.DrawImage(s.zipLineTile, &opts)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZipLineReachesEnd(t *testing.T) {
+	s := newTestScene(t, grid(20, 12)...)
+	line := NewZipLine(&Entity{ID: EtyZipLine, Fields: map[string]any{
+		"start": map[string]any{"cx": 2.0, "cy": 2.0},
+		"end":   map[string]any{"cx": 15.0, "cy": 6.0},
+	}}, testCellSize)
+	p := newTestPlayer(t, s, IVec2{})
+	p.states.Set(p.startZipping(line))
+
+	frames := int(math.Ceil(line.Length() / ZipLineSpeed))
+	for i := 0; i < frames; i++ {
+		if p.state() != PlayerStateZipping {
+			t.Fatalf("player let go of the zip line after %d frames; want %d", i, frames)
+		}
+		updatePlayer(p, 0)
+	}
+	if p.state() == PlayerStateZipping {
+		t.Fatalf("player is still zipping after %d frames", frames)
+	}
+	hb := p.Hitbox()
+	if grip := (Vec2{X: float64(hb.X + hb.W/2), Y: float64(hb.Y)}); grip.Sub(line.End).Mag() > 1 {
+		t.Errorf("player let go at %v; want the end of the line at %v", grip, line.End)
+	}
+}
+
+func TestZipLineLetGoEarly(t *testing.T) {
+	s := newTestScene(t, grid(20, 12)...)
+	line := NewZipLine(&Entity{ID: EtyZipLine, Fields: map[string]any{
+		"start": map[string]any{"cx": 2.0, "cy": 2.0},
+		"end":   map[string]any{"cx": 15.0, "cy": 2.0},
+	}}, testCellSize)
+	p := newTestPlayer(t, s, IVec2{})
+	p.states.Set(p.startZipping(line))
+	updatePlayer(p, 0)
+	updatePlayer(p, InputJumped)
+	if p.state() != PlayerStateFalling {
+		t.Fatalf("state = %v after pressing jump on a zip line; want %v", p.state(), PlayerStateFalling)
+	}
+	if p.Vel.X <= 0 {
+		t.Errorf("Vel.X = %v after letting go; want the player to keep moving along the line", p.Vel.X)
+	}
+}